@@ -1,25 +1,33 @@
 package app
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"yourapp/internal/model"
 	"yourapp/internal/repository"
 	"yourapp/internal/service"
+	"yourapp/internal/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 type AuctionHandler struct {
-	auctionService service.AuctionService
-	jwtSecret      string
+	auctionService   service.AuctionService
+	watchlistService service.WatchlistService
+	jwtSecret        string
 }
 
-func NewAuctionHandler(auctionService service.AuctionService, jwtSecret string) *AuctionHandler {
+func NewAuctionHandler(auctionService service.AuctionService, watchlistService service.WatchlistService, jwtSecret string) *AuctionHandler {
 	return &AuctionHandler{
-		auctionService: auctionService,
-		jwtSecret:      jwtSecret,
+		auctionService:   auctionService,
+		watchlistService: watchlistService,
+		jwtSecret:        jwtSecret,
 	}
 }
 
@@ -111,6 +119,28 @@ func (h *AuctionHandler) GetOrganizer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": organizer})
 }
 
+func (h *AuctionHandler) UpdateOrganizer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organizer id"})
+		return
+	}
+
+	var req service.UpdateOrganizerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organizer, err := h.auctionService.UpdateOrganizer(uint(id), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": organizer})
+}
+
 // ========== CATEGORY HANDLERS ==========
 
 func (h *AuctionHandler) CreateCategory(c *gin.Context) {
@@ -193,6 +223,13 @@ func (h *AuctionHandler) GetAuctionItems(c *gin.Context) {
 		id := uint(categoryID)
 		filters.CategoryID = &id
 	}
+	filters.Province = c.Query("province")
+	if minPrice, err := decimal.NewFromString(c.Query("min_price")); err == nil {
+		filters.MinPrice = &minPrice
+	}
+	if maxPrice, err := decimal.NewFromString(c.Query("max_price")); err == nil {
+		filters.MaxPrice = &maxPrice
+	}
 
 	items, total, err := h.auctionService.GetPublishedAuctions(filters)
 	if err != nil {
@@ -224,7 +261,7 @@ func (h *AuctionHandler) GetAuctionItem(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": item})
+	c.JSON(http.StatusOK, gin.H{"data": newPublicAuctionItem(item)})
 }
 
 func (h *AuctionHandler) UpdateAuctionItem(c *gin.Context) {
@@ -300,7 +337,7 @@ func (h *AuctionHandler) PlaceBid(c *gin.Context) {
 
 	bid, err := h.auctionService.PlaceBid(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBidError(c, err)
 		return
 	}
 
@@ -316,6 +353,10 @@ func (h *AuctionHandler) GetItemBids(c *gin.Context) {
 
 	bids, err := h.auctionService.GetItemBids(uint(itemID))
 	if err != nil {
+		if errors.Is(err, service.ErrBidsSealed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -337,11 +378,395 @@ func (h *AuctionHandler) GetUserBids(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": bids})
+	// Active maximums are only ever shown to their owner
+	proxyBids, err := h.auctionService.GetUserProxyBids(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bids, "proxy_bids": proxyBids})
+}
+
+func (h *AuctionHandler) CancelAuction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req service.CancelAuctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.ItemID = uint(id)
+	req.AdminID = userID.(string)
+
+	item, err := h.auctionService.CancelAuction(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+func (h *AuctionHandler) CancelBid(c *gin.Context) {
+	bidID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bid id"})
+		return
+	}
+
+	var req service.CancelBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.BidID = uint(bidID)
+	req.AdminID = userID.(string)
+
+	cancellation, err := h.auctionService.CancelBid(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cancellation})
+}
+
+func (h *AuctionHandler) GetBidCancellations(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	cancellations, err := h.auctionService.GetBidCancellations(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cancellations})
+}
+
+// ========== PROXY BID HANDLERS ==========
+
+func (h *AuctionHandler) SetProxyBid(c *gin.Context) {
+	var req service.ProxyBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ItemID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "item_id is required"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.UserID = userID.(string)
+
+	proxy, err := h.auctionService.SetProxyBid(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": proxy})
+}
+
+func (h *AuctionHandler) RaiseProxyBid(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req service.ProxyBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.ItemID = uint(itemID)
+	req.UserID = userID.(string)
+
+	proxy, err := h.auctionService.RaiseProxyBid(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": proxy})
+}
+
+func (h *AuctionHandler) WithdrawProxyBid(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.auctionService.WithdrawProxyBid(uint(itemID), userID.(string)); err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "maximum bid withdrawn successfully"})
+}
+
+// AcceptClockPrice buys a descending-price lot at its current price
+func (h *AuctionHandler) AcceptClockPrice(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	bid, err := h.auctionService.AcceptClockPrice(service.AcceptClockPriceRequest{
+		ItemID:    uint(itemID),
+		UserID:    userID.(string),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": bid})
+}
+
+// GetNextBids lists the next valid bid amounts of a lot
+func (h *AuctionHandler) GetNextBids(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	count, _ := strconv.Atoi(c.Query("count"))
+	next, err := h.auctionService.GetNextBids(uint(itemID), count)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": next})
+}
+
+// ========== INCREMENT TABLE HANDLERS ==========
+
+func (h *AuctionHandler) SaveIncrementTable(c *gin.Context) {
+	var req service.IncrementTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := h.auctionService.SaveIncrementTable(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": table})
+}
+
+func (h *AuctionHandler) GetIncrementTables(c *gin.Context) {
+	tables, err := h.auctionService.GetIncrementTables()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tables})
+}
+
+func (h *AuctionHandler) DeleteIncrementTable(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid table id"})
+		return
+	}
+
+	if err := h.auctionService.DeleteIncrementTable(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "increment table deleted successfully"})
+}
+
+// ========== SOFT CLOSE HANDLERS ==========
+
+func (h *AuctionHandler) SaveSoftCloseRule(c *gin.Context) {
+	var req service.SoftCloseRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.auctionService.SaveSoftCloseRule(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+func (h *AuctionHandler) GetSoftCloseRules(c *gin.Context) {
+	rules, err := h.auctionService.GetSoftCloseRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+func (h *AuctionHandler) DeleteSoftCloseRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule id"})
+		return
+	}
+
+	if err := h.auctionService.DeleteSoftCloseRule(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "soft close rule deleted successfully"})
+}
+
+func (h *AuctionHandler) GetAuctionExtensions(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	extensions, err := h.auctionService.GetAuctionExtensions(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": extensions})
+}
+
+// ========== SEALED BID HANDLERS ==========
+
+func (h *AuctionHandler) RevealSealedBids(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	result, err := h.auctionService.RevealSealedBids(uint(itemID))
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+func (h *AuctionHandler) GetSealedBidResult(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	result, err := h.auctionService.GetSealedBidResult(uint(itemID))
+	if err != nil {
+		if errors.Is(err, service.ErrBidsSealed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondBidError maps bidding errors to a response; lost races are retryable
+func respondBidError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrBidConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "retryable": true})
+		return
+	}
+	if errors.Is(err, service.ErrNotParticipant) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 }
 
 // ========== RESPONSE TRANSFORMER ==========
 
+// PublicAuctionItem is the bidder-facing item detail. It shadows limit_price,
+// which is left out when the organizer hides it, and adds reserve_met and,
+// for descending-price lots, the clock price.
+type PublicAuctionItem struct {
+	*model.AuctionItem
+	LimitPrice *decimal.Decimal    `json:"limit_price,omitempty"`
+	ReserveMet *bool               `json:"reserve_met"`
+	ClockPrice *service.ClockPrice `json:"clock_price,omitempty"`
+}
+
+func newPublicAuctionItem(item *model.AuctionItem) PublicAuctionItem {
+	resp := PublicAuctionItem{
+		AuctionItem: item,
+		ReserveMet:  service.ReserveStatus(item),
+		ClockPrice:  service.CurrentClockPrice(item, time.Now()),
+	}
+	if item.Organizer == nil || !item.Organizer.HideLimitPrice {
+		resp.LimitPrice = &item.LimitPrice
+	}
+	return resp
+}
+
 // TransformAuctionItemForFrontend transforms an auction item to match frontend expectations
 type AuctionItemResponse struct {
 	ID            uint                     `json:"id"`
@@ -350,20 +775,28 @@ type AuctionItemResponse struct {
 	Category      string                   `json:"category"`
 	Image         string                   `json:"image"`
 	CurrentBid    float64                  `json:"current_bid"`
-	PreviousBid   float64                  `json:"previous_bid"`
 	StartingPrice float64                  `json:"starting_price"`
 	TotalBids     int                      `json:"total_bids"`
+	Watchers      int                      `json:"watcher_count"`
+	IsWatched     *bool                    `json:"is_watched,omitempty"` // set for authenticated callers only
 	TimeLeft      string                   `json:"time_left"`
 	IsHot         bool                     `json:"is_hot"`
+	IsSealed      bool                     `json:"is_sealed"`
+	ClockPrice    *float64                 `json:"clock_price,omitempty"`
+	NextPriceDrop string                   `json:"next_price_drop,omitempty"`
+	ReserveMet    *bool                    `json:"reserve_met"`
 	Status        model.AuctionStatus      `json:"status"`
+	Outcome       *model.AuctionOutcome    `json:"outcome,omitempty"`
 	Description   string                   `json:"description"`
 	Images        []string                 `json:"images"`
 	Schedule      *AuctionScheduleResponse `json:"schedule,omitempty"`
 }
 
 type AuctionScheduleResponse struct {
-	AuctionStart string `json:"auction_start"`
-	AuctionEnd   string `json:"auction_end"`
+	AuctionStart       string `json:"auction_start"`
+	AuctionEnd         string `json:"auction_end"`
+	OriginalAuctionEnd string `json:"original_auction_end,omitempty"`
+	ExtensionCount     int    `json:"extension_count"`
 }
 
 func (h *AuctionHandler) GetAuctionItemsForFrontend(c *gin.Context) {
@@ -399,6 +832,22 @@ func (h *AuctionHandler) GetAuctionItemsForFrontend(c *gin.Context) {
 		response = append(response, transformed)
 	}
 
+	if userID := h.optionalUserID(c); userID != "" {
+		ids := make([]uint, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		watched, err := h.watchlistService.WatchedItemIDs(userID, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for i := range response {
+			isWatched := watched[response[i].ID]
+			response[i].IsWatched = &isWatched
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": response,
 		"meta": gin.H{
@@ -414,12 +863,7 @@ func transformAuctionItem(item model.AuctionItem) AuctionItemResponse {
 	currentBid, _ := item.CurrentHighestBid.Float64()
 	startingPrice, _ := item.StartingPrice.Float64()
 
-	// Calculate previous bid (current - increment)
-	previousBid := currentBid
-	if item.BidCount > 0 {
-		increment, _ := item.IncrementAmount.Float64()
-		previousBid = currentBid - increment
-	}
+	sealed := item.AuctionMethod == model.AuctionMethodClosedBidding && item.Status != model.AuctionStatusClosed
 
 	// Get main image
 	mainImage := ""
@@ -459,38 +903,73 @@ func transformAuctionItem(item model.AuctionItem) AuctionItemResponse {
 		Category:      categoryName,
 		Image:         mainImage,
 		CurrentBid:    currentBid,
-		PreviousBid:   previousBid,
 		StartingPrice: startingPrice,
 		TotalBids:     item.BidCount,
+		Watchers:      item.WatcherCount,
 		TimeLeft:      timeLeft,
 		IsHot:         isHot,
+		IsSealed:      sealed,
+		ReserveMet:    service.ReserveStatus(&item),
 		Status:        item.Status,
+		Outcome:       item.Outcome,
 		Description:   description,
 		Images:        allImages,
 	}
 
+	if clock := service.CurrentClockPrice(&item, time.Now()); clock != nil {
+		price, _ := clock.Price.Float64()
+		resp.ClockPrice = &price
+		if clock.NextDrop != nil {
+			resp.NextPriceDrop = clock.NextDrop.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
 	if item.Schedule != nil {
 		resp.Schedule = &AuctionScheduleResponse{
-			AuctionStart: item.Schedule.AuctionStart.Format("2006-01-02T15:04:05Z"),
-			AuctionEnd:   item.Schedule.AuctionEnd.Format("2006-01-02T15:04:05Z"),
+			AuctionStart:   item.Schedule.AuctionStart.Format("2006-01-02T15:04:05Z"),
+			AuctionEnd:     item.Schedule.AuctionEnd.Format("2006-01-02T15:04:05Z"),
+			ExtensionCount: item.Schedule.ExtensionCount,
+		}
+		if item.Schedule.OriginalEnd != nil {
+			resp.Schedule.OriginalAuctionEnd = item.Schedule.OriginalEnd.Format("2006-01-02T15:04:05Z")
 		}
 	}
 
 	return resp
 }
 
-func calculateTimeLeft(endTime interface{}) string {
-	// Handle different time formats
-	var end interface{}
-	switch v := endTime.(type) {
-	case string:
-		end = v
-	default:
-		end = v
+// optionalUserID returns the caller's user ID on public routes when a valid
+// bearer token is sent, and "" for anonymous callers
+func (h *AuctionHandler) optionalUserID(c *gin.Context) string {
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return ""
+	}
+	claims, err := util.ValidateToken(parts[1], h.jwtSecret)
+	if err != nil {
+		return ""
 	}
+	return claims.UserID
+}
 
-	// For now, return a simple format
-	// In production, calculate actual time difference
-	_ = end
-	return "2h 30m"
+func calculateTimeLeft(endTime time.Time) string {
+	remaining := time.Until(endTime)
+	if remaining <= 0 {
+		return "ended"
+	}
+
+	days := int(remaining.Hours()) / 24
+	hours := int(remaining.Hours()) % 24
+	minutes := int(remaining.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", int(remaining.Seconds()))
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"yourapp/internal/model"
 	"yourapp/internal/service"
 	"yourapp/internal/util"
 
@@ -283,7 +284,36 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	util.SuccessResponse(c, http.StatusOK, "User retrieved successfully", gin.H{"user": user})
+	util.SuccessResponse(c, http.StatusOK, "User retrieved successfully", gin.H{
+		"user": user,
+		"balance": gin.H{
+			"available": user.AvailableBalance(),
+			"held":      user.HeldBalance,
+			"total":     user.Balance,
+		},
+	})
+}
+
+// AdminMiddleware lets only admins through. It runs after AuthMiddleware and
+// reads the user type from the database rather than the token, so a demoted
+// admin loses access without waiting for their token to expire.
+func (h *AuthHandler) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			util.Unauthorized(c, "unauthorized")
+			c.Abort()
+			return
+		}
+
+		user, err := h.authService.GetMe(userID.(string))
+		if err != nil || user.UserType != model.UserTypeAdmin {
+			util.Forbidden(c, "Admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
 }
 
 // AuthMiddleware validates JWT token
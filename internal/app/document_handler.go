@@ -0,0 +1,176 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+	documentService service.DocumentService
+}
+
+func NewDocumentHandler(documentService service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// ========== USER HANDLERS ==========
+
+// GetAuctionMinutes lets the winner or the seller download the minutes
+func (h *DocumentHandler) GetAuctionMinutes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	document, err := h.documentService.GetAuctionMinutesForUser(uint(itemID), userID.(string), c.Query("format"))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+// GetMyDocuments lists the user's invoices and receipts
+func (h *DocumentHandler) GetMyDocuments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	documents, err := h.documentService.GetMyDocuments(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": documents})
+}
+
+// GetMyDocument downloads one of the user's invoices or receipts as PDF
+func (h *DocumentHandler) GetMyDocument(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document id"})
+		return
+	}
+
+	document, err := h.documentService.GetMyDocument(uint(id), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+// ========== ADMIN HANDLERS ==========
+
+func (h *DocumentHandler) AdminGetDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document id"})
+		return
+	}
+
+	document, err := h.documentService.GetDocument(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+func (h *DocumentHandler) AdminGetAuctionMinutes(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	document, err := h.documentService.GetAuctionMinutes(uint(itemID), c.Query("format"))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+func (h *DocumentHandler) IssueAuctionMinutes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	document, err := h.documentService.IssueAuctionMinutes(uint(itemID), userID.(string))
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": document})
+}
+
+func (h *DocumentHandler) GetAuctionMinutesRevisions(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	documents, err := h.documentService.GetAuctionMinutesRevisions(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": documents})
+}
+
+// sendDocument writes the rendered file as a download. The number and hash
+// are repeated in headers so clients can verify a copy without parsing it.
+func sendDocument(c *gin.Context, document *service.RenderedDocument) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.Filename))
+	c.Header("X-Document-Number", document.Document.DocumentNumber)
+	c.Header("X-Content-Hash", document.Document.ContentHash)
+	c.Data(http.StatusOK, document.ContentType, document.Body)
+}
+
+func respondDocumentError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrDocumentForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
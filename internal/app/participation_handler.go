@@ -0,0 +1,122 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ParticipationHandler struct {
+	participationService service.ParticipationService
+}
+
+func NewParticipationHandler(participationService service.ParticipationService) *ParticipationHandler {
+	return &ParticipationHandler{
+		participationService: participationService,
+	}
+}
+
+// ========== BIDDER HANDLERS ==========
+
+func (h *ParticipationHandler) Register(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	participant, err := h.participationService.Register(uint(itemID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": participant})
+}
+
+func (h *ParticipationHandler) PayDeposit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	participant, err := h.participationService.PayDeposit(uint(itemID), userID.(string))
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": participant})
+}
+
+func (h *ParticipationHandler) GetMyParticipation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	participant, err := h.participationService.GetMyParticipation(uint(itemID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not registered for this lot"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": participant})
+}
+
+func (h *ParticipationHandler) GetMyParticipations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	participants, err := h.participationService.GetMyParticipations(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": participants})
+}
+
+// ========== ADMIN HANDLERS ==========
+
+func (h *ParticipationHandler) GetParticipants(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	participants, err := h.participationService.GetParticipants(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": participants})
+}
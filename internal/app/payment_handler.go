@@ -0,0 +1,135 @@
+package app
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookSignatureHeader carries the hex HMAC-SHA256 of the raw webhook body
+const webhookSignatureHeader = "X-Callback-Signature"
+
+type PaymentHandler struct {
+	topUpService service.TopUpService
+}
+
+func NewPaymentHandler(topUpService service.TopUpService) *PaymentHandler {
+	return &PaymentHandler{
+		topUpService: topUpService,
+	}
+}
+
+// ========== TOP-UP HANDLERS ==========
+
+func (h *PaymentHandler) CreateTopUp(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req service.CreateTopUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.UserID = userID.(string)
+
+	order, err := h.topUpService.CreateTopUp(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": order})
+}
+
+func (h *PaymentHandler) GetMyTopUps(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orders, err := h.topUpService.GetMyTopUps(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": orders})
+}
+
+func (h *PaymentHandler) GetTopUp(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid top-up id"})
+		return
+	}
+
+	order, err := h.topUpService.GetTopUp(uint(orderID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// SimulatePayment pays a pending top-up when the simulator gateway is active
+func (h *PaymentHandler) SimulatePayment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid top-up id"})
+		return
+	}
+
+	order, err := h.topUpService.SimulatePayment(uint(orderID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// ========== WEBHOOK ==========
+
+// Webhook receives payment notifications from the gateway. The signature is
+// checked over the raw body, so the body is read before any binding.
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unreadable body"})
+		return
+	}
+
+	order, err := h.topUpService.HandleWebhook(payload, c.GetHeader(webhookSignatureHeader))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"order_code": order.OrderCode, "status": order.Status}})
+}
@@ -9,13 +9,16 @@ import (
 	"yourapp/internal/repository"
 	"yourapp/internal/service"
 	"yourapp/internal/util"
+	"yourapp/internal/websocket"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewRouter(cfg *config.Config) *gin.Engine {
+// NewRouter wires the application. The returned shutdown func stops the
+// background jobs and must be called once the server stopped serving.
+func NewRouter(cfg *config.Config) (*gin.Engine, func()) {
 	// Set Gin mode
 	if cfg.ServerPort == "5000" {
 		gin.SetMode(gin.DebugMode)
@@ -51,11 +54,48 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		&model.ItemImage{},
 		&model.AuctionSchedule{},
 		&model.Bid{},
+		&model.BidCancellation{},
+		&model.ProxyBid{},
+		&model.SoftCloseRule{},
+		&model.IncrementTable{},
+		&model.IncrementBand{},
+		&model.AuctionExtension{},
+		&model.SealedBidReveal{},
+		&model.FundsHold{},
+		&model.AuctionParticipant{},
+		&model.TopUpOrder{},
+		&model.Settlement{},
+		&model.SecondChanceOffer{},
+		&model.FeeRule{},
+		&model.SettlementCharge{},
+		&model.Document{},
+		&model.DocumentSequence{},
+		&model.Notification{},
+		&model.WatchlistEntry{},
+		&model.SavedSearch{},
+		&model.SavedSearchMatch{},
+		&model.LedgerAccount{},
+		&model.JournalEntry{},
+		&model.JournalLine{},
+		&model.Tender{},
+		&model.TenderCriterion{},
+		&model.TenderCommitteeMember{},
+		&model.TenderOffer{},
+		&model.TenderDocument{},
+		&model.TenderScore{},
 	); err != nil {
 		panic("Failed to migrate database: " + err.Error())
 	}
 
+	// Settlements used to be unique per item; a second-chance sale adds another
+	if db.Migrator().HasIndex(&model.Settlement{}, "idx_settlements_item_id") {
+		if err := db.Migrator().DropIndex(&model.Settlement{}, "idx_settlements_item_id"); err != nil {
+			panic("Failed to migrate database: " + err.Error())
+		}
+	}
+
 	// Initialize repositories
+	transactor := repository.NewTransactor(db)
 	userRepo := repository.NewUserRepository(db)
 	sellerRepo := repository.NewSellerRepository(db)
 	organizerRepo := repository.NewOrganizerRepository(db)
@@ -64,10 +104,29 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	imageRepo := repository.NewItemImageRepository(db)
 	scheduleRepo := repository.NewAuctionScheduleRepository(db)
 	bidRepo := repository.NewBidRepository(db)
+	proxyBidRepo := repository.NewProxyBidRepository(db)
+	softCloseRepo := repository.NewSoftCloseRepository(db)
+	sealedBidRepo := repository.NewSealedBidRepository(db)
+	incrementRepo := repository.NewIncrementTableRepository(db)
+	tenderRepo := repository.NewTenderRepository(db)
+	fundsHoldRepo := repository.NewFundsHoldRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	participantRepo := repository.NewParticipantRepository(db)
+	topUpRepo := repository.NewTopUpRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	secondChanceRepo := repository.NewSecondChanceRepository(db)
+	feeRuleRepo := repository.NewFeeRuleRepository(db)
+	documentRepo := repository.NewDocumentRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	watchlistRepo := repository.NewWatchlistRepository(db)
+	savedSearchRepo := repository.NewSavedSearchRepository(db)
 
 	// Initialize RabbitMQ with retry logic
 	rabbitMQ := initRabbitMQWithRetry(cfg)
 
+	// Live auction feed; fans out through RabbitMQ once it is connected
+	auctionHub := websocket.NewHub(rabbitMQ)
+
 	// Initialize email service
 	emailService := service.NewEmailService(cfg)
 
@@ -82,12 +141,48 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		}
 	} else {
 		log.Println("Email worker not started - RabbitMQ connection failed. Will retry on first email send.")
+	}
+
+	// Initialize services
+	authService := service.NewAuthServiceWithConfig(userRepo, cfg.JWTSecret, rabbitMQ, cfg)
+	ledgerService := service.NewLedgerService(transactor, ledgerRepo, userRepo)
+	fundsService := service.NewFundsService(fundsHoldRepo, userRepo, ledgerService)
+	participationService := service.NewParticipationService(transactor, participantRepo, itemRepo, fundsService)
+	feeService := service.NewFeeService(feeRuleRepo, itemRepo, organizerRepo, categoryRepo)
+	notificationService := service.NewNotificationService(
+		transactor,
+		notificationRepo,
+		itemRepo,
+		bidRepo,
+		participantRepo,
+		userRepo,
+		rabbitMQ,
+		auctionHub,
+	)
+	documentService := service.NewDocumentService(
+		transactor,
+		documentRepo,
+		itemRepo,
+		bidRepo,
+		participantRepo,
+		settlementRepo,
+		tenderRepo,
+		userRepo,
+		rabbitMQ,
+		notificationService,
+		cfg.EmailName,
+	)
+
+	if rabbitMQ == nil {
 		// Start background goroutine to retry RabbitMQ connection and start email worker
 		go func() {
 			for {
 				time.Sleep(10 * time.Second)
 				newRabbitMQ := initRabbitMQWithRetry(cfg)
 				if newRabbitMQ != nil {
+					auctionHub.Attach(newRabbitMQ)
+					notificationService.AttachRabbitMQ(newRabbitMQ)
+					documentService.AttachRabbitMQ(newRabbitMQ)
 					log.Println("RabbitMQ reconnected! Starting email worker...")
 					emailWorker = service.NewEmailWorker(emailService, newRabbitMQ)
 					if err := emailWorker.Start(); err != nil {
@@ -101,9 +196,29 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		}()
 	}
 
-	// Initialize services
-	authService := service.NewAuthServiceWithConfig(userRepo, cfg.JWTSecret, rabbitMQ, cfg)
+	settlementService := service.NewSettlementService(
+		transactor,
+		settlementRepo,
+		itemRepo,
+		fundsService,
+		ledgerService,
+		feeService,
+		participationService,
+		documentService,
+		time.Duration(cfg.SettlementPaymentHours)*time.Hour,
+	)
+	watchlistService := service.NewWatchlistService(
+		transactor,
+		watchlistRepo,
+		itemRepo,
+		bidRepo,
+		notificationService,
+		time.Duration(cfg.WatchReminderStartMinutes)*time.Minute,
+		time.Duration(cfg.WatchReminderEndMinutes)*time.Minute,
+	)
+	savedSearchService := service.NewSavedSearchService(transactor, savedSearchRepo, notificationService)
 	auctionService := service.NewAuctionService(
+		transactor,
 		sellerRepo,
 		organizerRepo,
 		categoryRepo,
@@ -111,12 +226,190 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		imageRepo,
 		scheduleRepo,
 		bidRepo,
+		proxyBidRepo,
+		softCloseRepo,
+		sealedBidRepo,
+		incrementRepo,
 		userRepo,
+		fundsService,
+		participationService,
+		settlementService,
+		notificationService,
+		savedSearchService,
+		auctionHub,
+	)
+	secondChanceService := service.NewSecondChanceService(
+		transactor,
+		secondChanceRepo,
+		itemRepo,
+		bidRepo,
+		settlementRepo,
+		settlementService,
+		time.Duration(cfg.SecondChanceResponseHours)*time.Hour,
+	)
+	tenderService := service.NewTenderService(
+		transactor,
+		tenderRepo,
+		itemRepo,
+		userRepo,
+		fundsService,
+		participationService,
+		auctionService,
 	)
 
+	// Top-ups are only offered once a payment gateway is configured
+	var topUpService service.TopUpService
+	if cfg.PaymentGateway != "" {
+		paymentGateway, err := service.NewPaymentGateway(cfg.PaymentGateway, cfg.PaymentWebhookSecret)
+		if err != nil {
+			panic("Failed to initialize payment gateway: " + err.Error())
+		}
+		topUpService = service.NewTopUpService(
+			transactor,
+			topUpRepo,
+			userRepo,
+			ledgerService,
+			paymentGateway,
+			documentService,
+			time.Duration(cfg.TopUpExpiryMinutes)*time.Minute,
+		)
+	} else {
+		log.Println("Warning: PAYMENT_GATEWAY is not set. Wallet top-ups are disabled.")
+	}
+
 	// Initialize handlers
 	authHandler := NewAuthHandler(authService, cfg.JWTSecret)
-	auctionHandler := NewAuctionHandler(auctionService, cfg.JWTSecret)
+	auctionHandler := NewAuctionHandler(auctionService, watchlistService, cfg.JWTSecret)
+	wsHandler := websocket.NewHandler(auctionHub, auctionService, cfg.JWTSecret, cfg.ClientURL)
+	tenderHandler := NewTenderHandler(tenderService)
+	walletHandler := NewWalletHandler(ledgerService)
+	participationHandler := NewParticipationHandler(participationService)
+	watchlistHandler := NewWatchlistHandler(watchlistService)
+	notificationHandler := NewNotificationHandler(notificationService)
+	savedSearchHandler := NewSavedSearchHandler(savedSearchService)
+	settlementHandler := NewSettlementHandler(settlementService)
+	secondChanceHandler := NewSecondChanceHandler(secondChanceService)
+	feeHandler := NewFeeHandler(feeService)
+	documentHandler := NewDocumentHandler(documentService)
+
+	// Carry balances that predate the ledger into it as opening entries
+	if migrated, err := ledgerService.MigrateLegacyBalances(); err != nil {
+		log.Printf("Warning: Failed to migrate legacy balances into the ledger: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Ledger: opened %d legacy balances", migrated)
+	}
+
+	// Install the default fee schedule on a fresh database
+	if seeded, err := feeService.SeedDefaultRules(); err != nil {
+		log.Printf("Warning: Failed to seed the fee schedule: %v", err)
+	} else if seeded > 0 {
+		log.Printf("Fees: seeded %d default fee rules", seeded)
+	}
+
+	// Start background jobs. Every job is replica-safe, so SCHEDULER_ENABLED
+	// only exists to keep the load off API-only instances.
+	shutdown := func() {}
+	if cfg.SchedulerEnabled {
+		scheduler := service.NewScheduler()
+		scheduler.Register("auction-lifecycle", time.Duration(cfg.SchedulerIntervalSeconds)*time.Second, func(now time.Time) error {
+			started, err := auctionService.StartDueAuctions(now)
+			if err != nil {
+				return err
+			}
+			closed, err := auctionService.CloseDueAuctions(now)
+			if err != nil {
+				return err
+			}
+			if started > 0 || closed > 0 {
+				log.Printf("Auction lifecycle: %d started, %d closed", started, closed)
+			}
+			return nil
+		})
+		scheduler.Register("settlement-deadlines", time.Duration(cfg.SchedulerIntervalSeconds)*time.Second, func(now time.Time) error {
+			defaulted, err := settlementService.ForfeitOverdueSettlements(now)
+			if err != nil {
+				return err
+			}
+			if defaulted > 0 {
+				log.Printf("Settlement: %d winners defaulted, lots flagged for re-auction", defaulted)
+			}
+			return nil
+		})
+		scheduler.Register("second-chance-expiry", time.Minute, func(now time.Time) error {
+			expired, err := secondChanceService.ExpireOverdueOffers(now)
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				log.Printf("Second-chance offers: %d expired", expired)
+			}
+			return nil
+		})
+		if topUpService != nil {
+			scheduler.Register("topup-expiry", time.Minute, func(now time.Time) error {
+				expired, err := topUpService.ExpireStaleTopUps(now)
+				if err != nil {
+					return err
+				}
+				if expired > 0 {
+					log.Printf("Top-ups: %d expired", expired)
+				}
+				return nil
+			})
+		}
+		scheduler.Register("document-mailer", time.Minute, func(now time.Time) error {
+			sent, err := documentService.EmailPendingDocuments()
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				log.Printf("Documents: %d invoices and receipts emailed", sent)
+			}
+			return nil
+		})
+		scheduler.Register("ending-soon-notifier", time.Minute, func(now time.Time) error {
+			raised, err := notificationService.NotifyEndingSoon(now)
+			if err != nil {
+				return err
+			}
+			if raised > 0 {
+				log.Printf("Notifications: %d ending-soon reminders raised", raised)
+			}
+			return nil
+		})
+		scheduler.Register("watchlist-reminders", time.Minute, func(now time.Time) error {
+			raised, err := watchlistService.RemindWatchers(now)
+			if err != nil {
+				return err
+			}
+			if raised > 0 {
+				log.Printf("Watchlist: %d watcher reminders raised", raised)
+			}
+			return nil
+		})
+		scheduler.Register("saved-search-digests", time.Minute, func(now time.Time) error {
+			sent, err := savedSearchService.SendDailyDigests(now)
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				log.Printf("Saved searches: %d daily digests raised", sent)
+			}
+			return nil
+		})
+		scheduler.Register("notification-mailer", time.Minute, func(now time.Time) error {
+			sent, err := notificationService.EmailPendingNotifications()
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				log.Printf("Notifications: %d emails queued", sent)
+			}
+			return nil
+		})
+		scheduler.Start()
+		shutdown = scheduler.Stop
+	}
 
 	// API routes
 	api := r.Group("/api/v1")
@@ -146,6 +439,14 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			auctions.GET("", auctionHandler.GetAuctionItemsForFrontend)
 			auctions.GET("/:id", auctionHandler.GetAuctionItem)
 			auctions.GET("/:id/bids", auctionHandler.GetItemBids)
+			auctions.GET("/:id/next-bids", auctionHandler.GetNextBids)
+			auctions.GET("/:id/ws", wsHandler.Subscribe)
+			auctions.GET("/:id/stream", wsHandler.StreamItem)
+			auctions.GET("/stream", wsHandler.StreamListing)
+			auctions.GET("/:id/extensions", auctionHandler.GetAuctionExtensions)
+			auctions.GET("/:id/sealed-result", auctionHandler.GetSealedBidResult)
+			auctions.GET("/:id/tender-result", tenderHandler.GetTenderResult)
+			auctions.GET("/:id/quote", feeHandler.GetQuote)
 
 			// Categories
 			auctions.GET("/categories", auctionHandler.GetCategories)
@@ -154,7 +455,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 
 		// Admin auction management (protected)
 		adminAuctions := api.Group("/admin/auctions")
-		adminAuctions.Use(authHandler.AuthMiddleware())
+		adminAuctions.Use(authHandler.AuthMiddleware(), authHandler.AdminMiddleware())
 		{
 			// Sellers
 			adminAuctions.POST("/sellers", auctionHandler.CreateSeller)
@@ -165,6 +466,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			adminAuctions.POST("/organizers", auctionHandler.CreateOrganizer)
 			adminAuctions.GET("/organizers", auctionHandler.GetOrganizers)
 			adminAuctions.GET("/organizers/:id", auctionHandler.GetOrganizer)
+			adminAuctions.PUT("/organizers/:id", auctionHandler.UpdateOrganizer)
 
 			// Categories
 			adminAuctions.POST("/categories", auctionHandler.CreateCategory)
@@ -174,7 +476,47 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			adminAuctions.GET("/items", auctionHandler.GetAuctionItems)
 			adminAuctions.PUT("/items/:id", auctionHandler.UpdateAuctionItem)
 			adminAuctions.POST("/items/:id/publish", auctionHandler.PublishAuctionItem)
+			adminAuctions.POST("/items/:id/cancel", auctionHandler.CancelAuction)
 			adminAuctions.DELETE("/items/:id", auctionHandler.DeleteAuctionItem)
+			adminAuctions.POST("/items/:id/reveal", auctionHandler.RevealSealedBids)
+			adminAuctions.GET("/items/:id/bid-cancellations", auctionHandler.GetBidCancellations)
+			adminAuctions.GET("/items/:id/participants", participationHandler.GetParticipants)
+			adminAuctions.GET("/items/:id/settlement", settlementHandler.GetItemSettlement)
+			adminAuctions.GET("/items/:id/minutes", documentHandler.AdminGetAuctionMinutes)
+			adminAuctions.POST("/items/:id/minutes", documentHandler.IssueAuctionMinutes)
+			adminAuctions.GET("/items/:id/minutes/revisions", documentHandler.GetAuctionMinutesRevisions)
+			adminAuctions.GET("/documents/:id", documentHandler.AdminGetDocument)
+			adminAuctions.POST("/items/:id/second-chance", secondChanceHandler.CreateOffer)
+			adminAuctions.GET("/items/:id/second-chance", secondChanceHandler.GetItemOffers)
+
+			// Bids
+			adminAuctions.POST("/bids/:id/cancel", auctionHandler.CancelBid)
+
+			// Soft close (anti-sniping) rules
+			adminAuctions.POST("/soft-close-rules", auctionHandler.SaveSoftCloseRule)
+			adminAuctions.GET("/soft-close-rules", auctionHandler.GetSoftCloseRules)
+			adminAuctions.DELETE("/soft-close-rules/:id", auctionHandler.DeleteSoftCloseRule)
+
+			// Bid increment tables by price band
+			adminAuctions.POST("/increment-tables", auctionHandler.SaveIncrementTable)
+			adminAuctions.GET("/increment-tables", auctionHandler.GetIncrementTables)
+			adminAuctions.DELETE("/increment-tables/:id", auctionHandler.DeleteIncrementTable)
+
+			// Fee and tax schedule
+			adminAuctions.POST("/fee-rules", feeHandler.CreateRule)
+			adminAuctions.GET("/fee-rules", feeHandler.GetRules)
+			adminAuctions.PUT("/fee-rules/:id", feeHandler.UpdateRule)
+			adminAuctions.DELETE("/fee-rules/:id", feeHandler.DeleteRule)
+
+			// Tender evaluation committee
+			adminAuctions.POST("/items/:id/tender", tenderHandler.OpenTender)
+			adminAuctions.GET("/items/:id/tender", tenderHandler.GetTender)
+			adminAuctions.POST("/items/:id/tender/close", tenderHandler.CloseSubmissions)
+			adminAuctions.GET("/items/:id/tender/offers", tenderHandler.GetOffers)
+			adminAuctions.POST("/items/:id/tender/scores", tenderHandler.ScoreOffer)
+			adminAuctions.GET("/items/:id/tender/evaluation", tenderHandler.GetEvaluation)
+			adminAuctions.POST("/items/:id/tender/sign-off", tenderHandler.SignOffEvaluation)
+			adminAuctions.POST("/items/:id/tender/publish", tenderHandler.PublishEvaluation)
 		}
 
 		// Bidding routes (protected)
@@ -183,6 +525,121 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		{
 			bids.POST("", auctionHandler.PlaceBid)
 			bids.GET("/my-bids", auctionHandler.GetUserBids)
+
+			// Proxy (maximum) bids
+			bids.POST("/proxy", auctionHandler.SetProxyBid)
+			bids.PUT("/proxy/:itemId", auctionHandler.RaiseProxyBid)
+			bids.DELETE("/proxy/:itemId", auctionHandler.WithdrawProxyBid)
+
+			// Descending-price lots: take the lot at the current clock price
+			bids.POST("/descending/:itemId/accept", auctionHandler.AcceptClockPrice)
+		}
+
+		// Tender offers (protected)
+		tenders := api.Group("/tenders")
+		tenders.Use(authHandler.AuthMiddleware())
+		{
+			tenders.POST("/:id/offers", tenderHandler.SubmitOffer)
+			tenders.GET("/:id/my-offer", tenderHandler.GetMyOffer)
+		}
+
+		// Lot registration and deposits (protected)
+		participations := api.Group("/participations")
+		participations.Use(authHandler.AuthMiddleware())
+		{
+			participations.GET("", participationHandler.GetMyParticipations)
+			participations.POST("/:id", participationHandler.Register)
+			participations.GET("/:id", participationHandler.GetMyParticipation)
+			participations.POST("/:id/deposit", participationHandler.PayDeposit)
+		}
+
+		// Watchlist (protected)
+		watchlist := api.Group("/watchlist")
+		watchlist.Use(authHandler.AuthMiddleware())
+		{
+			watchlist.GET("", watchlistHandler.GetMyWatchlist)
+			watchlist.POST("/:id", watchlistHandler.Watch)
+			watchlist.DELETE("/:id", watchlistHandler.Unwatch)
+		}
+
+		// Notification center (protected). The live stream takes its token
+		// as a query parameter since EventSource can't send headers.
+		api.GET("/notifications/stream", wsHandler.StreamNotifications)
+		notifications := api.Group("/notifications")
+		notifications.Use(authHandler.AuthMiddleware())
+		{
+			notifications.GET("", notificationHandler.GetMyNotifications)
+			notifications.POST("/read-all", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Saved searches with new-listing alerts (protected). Unsubscribing
+		// comes from an email link, so it takes the link's token instead.
+		api.POST("/saved-searches/unsubscribe", savedSearchHandler.Unsubscribe)
+		savedSearches := api.Group("/saved-searches")
+		savedSearches.Use(authHandler.AuthMiddleware())
+		{
+			savedSearches.GET("", savedSearchHandler.GetMySavedSearches)
+			savedSearches.POST("", savedSearchHandler.CreateSavedSearch)
+			savedSearches.PUT("/:id", savedSearchHandler.UpdateSavedSearch)
+			savedSearches.DELETE("/:id", savedSearchHandler.DeleteSavedSearch)
+		}
+
+		// Winner settlement (protected)
+		settlements := api.Group("/settlements")
+		settlements.Use(authHandler.AuthMiddleware())
+		{
+			settlements.GET("", settlementHandler.GetMySettlements)
+			settlements.GET("/:id", settlementHandler.GetMySettlement)
+			settlements.POST("/:id/pay", settlementHandler.PaySettlement)
+		}
+
+		// Official documents: minutes for winners and sellers, and each
+		// user's own invoices and receipts (protected)
+		documents := api.Group("/documents")
+		documents.Use(authHandler.AuthMiddleware())
+		{
+			documents.GET("", documentHandler.GetMyDocuments)
+			documents.GET("/:id", documentHandler.GetMyDocument)
+			documents.GET("/auction-minutes/:id", documentHandler.GetAuctionMinutes)
+		}
+
+		// Second-chance offers to runner-up bidders (protected)
+		secondChance := api.Group("/second-chance-offers")
+		secondChance.Use(authHandler.AuthMiddleware())
+		{
+			secondChance.GET("", secondChanceHandler.GetMyOffers)
+			secondChance.POST("/:id/accept", secondChanceHandler.AcceptOffer)
+			secondChance.POST("/:id/decline", secondChanceHandler.DeclineOffer)
+		}
+
+		// Wallet ledger (protected)
+		wallet := api.Group("/wallet")
+		wallet.Use(authHandler.AuthMiddleware())
+		{
+			wallet.GET("/balance", walletHandler.GetBalance)
+			wallet.GET("/transactions", walletHandler.GetTransactions)
+		}
+
+		// Top-ups via virtual account, and the gateway callbacks
+		// (authenticated by signature)
+		if topUpService != nil {
+			paymentHandler := NewPaymentHandler(topUpService)
+			wallet.POST("/topups", paymentHandler.CreateTopUp)
+			wallet.GET("/topups", paymentHandler.GetMyTopUps)
+			wallet.GET("/topups/:id", paymentHandler.GetTopUp)
+			if cfg.DevMode {
+				wallet.POST("/topups/:id/simulate-payment", paymentHandler.SimulatePayment)
+			}
+			api.POST("/payments/webhook", paymentHandler.Webhook)
+		}
+
+		// Admin wallet management (protected)
+		adminWallet := api.Group("/admin/wallet")
+		adminWallet.Use(authHandler.AuthMiddleware(), authHandler.AdminMiddleware())
+		{
+			adminWallet.POST("/adjustments", walletHandler.PostAdjustment)
+			adminWallet.GET("/consistency", walletHandler.CheckConsistency)
 		}
 	}
 
@@ -191,7 +648,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	return r
+	return r, shutdown
 }
 
 func initDB(cfg *config.Config) (*gorm.DB, error) {
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecondChanceHandler struct {
+	secondChanceService service.SecondChanceService
+}
+
+func NewSecondChanceHandler(secondChanceService service.SecondChanceService) *SecondChanceHandler {
+	return &SecondChanceHandler{
+		secondChanceService: secondChanceService,
+	}
+}
+
+// ========== BIDDER HANDLERS ==========
+
+func (h *SecondChanceHandler) GetMyOffers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	offers, err := h.secondChanceService.GetMyOffers(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offers})
+}
+
+func (h *SecondChanceHandler) AcceptOffer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offer id"})
+		return
+	}
+
+	offer, err := h.secondChanceService.AcceptOffer(uint(offerID), userID.(string))
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offer})
+}
+
+func (h *SecondChanceHandler) DeclineOffer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offer id"})
+		return
+	}
+
+	offer, err := h.secondChanceService.DeclineOffer(uint(offerID), userID.(string))
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offer})
+}
+
+// ========== ADMIN HANDLERS ==========
+
+func (h *SecondChanceHandler) CreateOffer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	// The body is optional; it only overrides the response window
+	var req service.CreateSecondChanceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	req.ItemID = uint(itemID)
+	req.AdminID = userID.(string)
+
+	offer, err := h.secondChanceService.CreateOffer(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": offer})
+}
+
+func (h *SecondChanceHandler) GetItemOffers(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	offers, err := h.secondChanceService.GetItemOffers(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offers})
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementHandler struct {
+	settlementService service.SettlementService
+}
+
+func NewSettlementHandler(settlementService service.SettlementService) *SettlementHandler {
+	return &SettlementHandler{
+		settlementService: settlementService,
+	}
+}
+
+// ========== WINNER HANDLERS ==========
+
+func (h *SettlementHandler) GetMySettlements(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	settlements, err := h.settlementService.GetMySettlements(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settlements})
+}
+
+func (h *SettlementHandler) GetMySettlement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	settlement, err := h.settlementService.GetMySettlement(uint(itemID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settlement})
+}
+
+func (h *SettlementHandler) PaySettlement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	settlement, err := h.settlementService.PaySettlement(uint(itemID), userID.(string))
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settlement})
+}
+
+// ========== ADMIN HANDLERS ==========
+
+func (h *SettlementHandler) GetItemSettlement(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	settlement, err := h.settlementService.GetItemSettlement(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "settlement not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settlement})
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"yourapp/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenderHandler struct {
+	tenderService service.TenderService
+}
+
+func NewTenderHandler(tenderService service.TenderService) *TenderHandler {
+	return &TenderHandler{
+		tenderService: tenderService,
+	}
+}
+
+// ========== COMMITTEE HANDLERS ==========
+
+func (h *TenderHandler) OpenTender(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req service.OpenTenderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tender, err := h.tenderService.OpenTender(uint(itemID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": tender})
+}
+
+func (h *TenderHandler) CloseSubmissions(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	// Closing before the submission period ends must be asked for explicitly
+	force, _ := strconv.ParseBool(c.Query("force"))
+
+	tender, err := h.tenderService.CloseSubmissions(uint(itemID), force)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tender})
+}
+
+func (h *TenderHandler) GetTender(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	tender, err := h.tenderService.GetTender(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tender})
+}
+
+func (h *TenderHandler) GetOffers(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	offers, err := h.tenderService.GetOffers(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offers})
+}
+
+func (h *TenderHandler) ScoreOffer(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req service.ScoreOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.EvaluatorID = userID.(string)
+
+	if err := h.tenderService.ScoreOffer(uint(itemID), req); err != nil {
+		respondTenderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "scores saved successfully"})
+}
+
+func (h *TenderHandler) GetEvaluation(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	evaluation, err := h.tenderService.GetEvaluation(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": evaluation})
+}
+
+func (h *TenderHandler) SignOffEvaluation(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	evaluation, err := h.tenderService.SignOffEvaluation(uint(itemID), userID.(string))
+	if err != nil {
+		respondTenderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": evaluation})
+}
+
+func (h *TenderHandler) PublishEvaluation(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	evaluation, err := h.tenderService.PublishEvaluation(uint(itemID), userID.(string))
+	if err != nil {
+		respondTenderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": evaluation})
+}
+
+// ========== BIDDER HANDLERS ==========
+
+func (h *TenderHandler) SubmitOffer(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req service.SubmitOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	req.ItemID = uint(itemID)
+	req.UserID = userID.(string)
+
+	offer, err := h.tenderService.SubmitOffer(req)
+	if err != nil {
+		respondBidError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": offer})
+}
+
+func (h *TenderHandler) GetMyOffer(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	offer, err := h.tenderService.GetMyOffer(uint(itemID), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offer})
+}
+
+func (h *TenderHandler) GetTenderResult(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	result, err := h.tenderService.GetTenderResult(uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondTenderError maps committee errors to a response
+func respondTenderError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrNotCommitteeMember) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
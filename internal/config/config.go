@@ -52,6 +52,26 @@ type Config struct {
 	RateLimitEnabled bool
 	RateLimitRPS     int // Requests per second
 	RateLimitBurst   int // Burst size
+
+	// Background scheduler
+	SchedulerEnabled         bool
+	SchedulerIntervalSeconds int
+
+	// Development mode enables the payment simulator and its routes
+	DevMode bool
+
+	// Payments
+	PaymentGateway       string // empty disables top-ups; "simulator" needs DevMode
+	PaymentWebhookSecret string
+	TopUpExpiryMinutes   int
+
+	// Settlement
+	SettlementPaymentHours    int
+	SecondChanceResponseHours int
+
+	// Watchlist reminders
+	WatchReminderStartMinutes int
+	WatchReminderEndMinutes   int
 }
 
 func Load() (*Config, error) {
@@ -103,6 +123,28 @@ func Load() (*Config, error) {
 		RateLimitEnabled: getEnvBool("RATE_LIMIT_ENABLED", true),
 		RateLimitRPS:     getEnvInt("RATE_LIMIT_RPS", 100),
 		RateLimitBurst:   getEnvInt("RATE_LIMIT_BURST", 200),
+
+		// Background scheduler (default: enabled, every 10 seconds)
+		SchedulerEnabled:         getEnvBool("SCHEDULER_ENABLED", true),
+		SchedulerIntervalSeconds: getEnvInt("SCHEDULER_INTERVAL_SECONDS", 10),
+
+		// Development mode (default: off)
+		DevMode: getEnvBool("DEV_MODE", false),
+
+		// Payments (default: no gateway, virtual accounts valid for 24 hours)
+		PaymentGateway:       getEnv("PAYMENT_GATEWAY", ""),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		TopUpExpiryMinutes:   getEnvInt("TOPUP_EXPIRY_MINUTES", 1440),
+
+		// Settlement (default: winners have 5 days to pay the balance)
+		SettlementPaymentHours: getEnvInt("SETTLEMENT_PAYMENT_HOURS", 120),
+
+		// Second-chance offers (default: the runner-up has 2 days to answer)
+		SecondChanceResponseHours: getEnvInt("SECOND_CHANCE_RESPONSE_HOURS", 48),
+
+		// Watchlist reminders (default: an hour before the start and the end)
+		WatchReminderStartMinutes: getEnvInt("WATCH_REMINDER_START_MINUTES", 60),
+		WatchReminderEndMinutes:   getEnvInt("WATCH_REMINDER_END_MINUTES", 60),
 	}
 
 	// Build database URL if not provided
@@ -121,6 +163,16 @@ func Load() (*Config, error) {
 	if cfg.JWTSecret == "" || cfg.JWTSecret == "your-secret-key-change-in-production" {
 		return nil, fmt.Errorf("JWT_SECRET must be set")
 	}
+	if cfg.PaymentGateway != "" {
+		// The simulator lets users pay their own top-ups
+		if cfg.PaymentGateway == "simulator" && !cfg.DevMode {
+			return nil, fmt.Errorf("PAYMENT_GATEWAY=simulator is only allowed with DEV_MODE=true")
+		}
+		// Anyone could forge a webhook signed with an empty or published secret
+		if cfg.PaymentWebhookSecret == "" || cfg.PaymentWebhookSecret == "simulator-webhook-secret" {
+			return nil, fmt.Errorf("PAYMENT_WEBHOOK_SECRET must be set when PAYMENT_GATEWAY is set")
+		}
+	}
 
 	return cfg, nil
 }
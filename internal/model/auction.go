@@ -40,6 +40,16 @@ const (
 	AuctionMethodOpenBidding   AuctionMethod = "open_bidding"
 	AuctionMethodClosedBidding AuctionMethod = "closed_bidding"
 	AuctionMethodTender        AuctionMethod = "tender"
+	AuctionMethodDescending    AuctionMethod = "descending" // Dutch: the price falls until a bidder accepts it
+)
+
+type AuctionOutcome string
+
+const (
+	AuctionOutcomeSold   AuctionOutcome = "sold"
+	AuctionOutcomeUnsold AuctionOutcome = "unsold" // closed without a bid meeting the limit price
+
+	AuctionOutcomeSecondChance AuctionOutcome = "sold_second_chance" // sold to the runner-up after the winner fell through
 )
 
 type AuctionStatus string
@@ -66,6 +76,7 @@ const (
 	BidTypeManual BidType = "manual"
 	BidTypeAuto   BidType = "auto"
 	BidTypeProxy  BidType = "proxy"
+	BidTypeTender BidType = "tender" // a ranked offer, recorded when the tender is published
 )
 
 type BidStatus string
@@ -79,6 +90,14 @@ const (
 	BidStatusCancelled BidStatus = "cancelled"
 )
 
+type ProxyBidStatus string
+
+const (
+	ProxyBidStatusActive    ProxyBidStatus = "active"
+	ProxyBidStatusExhausted ProxyBidStatus = "exhausted"
+	ProxyBidStatusWithdrawn ProxyBidStatus = "withdrawn"
+)
+
 // ========== MODELS ==========
 
 // Seller represents the seller of auction items
@@ -121,6 +140,10 @@ type Organizer struct {
 	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Reserve (limit price) settings
+	RequireStartAtLimit bool `gorm:"default:false" json:"require_start_at_limit"` // starting price may not sit below the limit price
+	HideLimitPrice      bool `gorm:"default:false" json:"hide_limit_price"`       // public API only says whether the reserve is met
 }
 
 func (Organizer) TableName() string {
@@ -166,21 +189,29 @@ type AuctionItem struct {
 	StartingPrice       decimal.Decimal `gorm:"type:decimal(15,2)" json:"starting_price"`
 	CurrentHighestBid   decimal.Decimal `gorm:"type:decimal(15,2)" json:"current_highest_bid"`
 	IncrementAmount     decimal.Decimal `gorm:"type:decimal(15,2)" json:"increment_amount"`
+	DecrementAmount     decimal.Decimal `gorm:"type:decimal(15,2);default:0" json:"decrement_amount"` // descending lots: price drop per interval
+	DecrementInterval   int             `gorm:"column:decrement_interval_seconds;default:0" json:"decrement_interval_seconds"`
 	AuctionMethod       AuctionMethod   `gorm:"type:varchar(20)" json:"auction_method"`
 	Status              AuctionStatus   `gorm:"type:varchar(20);default:'draft';index" json:"status"`
 	ViewCount           int             `gorm:"default:0" json:"view_count"`
 	BidCount            int             `gorm:"default:0" json:"bid_count"`
+	WatcherCount        int             `gorm:"default:0" json:"watcher_count"`
+	Outcome             *AuctionOutcome `gorm:"type:varchar(20);index" json:"outcome,omitempty"`
+	OutcomeReason       *string         `gorm:"type:text" json:"outcome_reason,omitempty"`
+	ClosedAt            *time.Time      `gorm:"type:timestamp" json:"closed_at,omitempty"`
+	ReauctionRequired   bool            `gorm:"default:false;index" json:"reauction_required"` // winner defaulted on payment
 	CreatedAt           time.Time       `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt           time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt           gorm.DeletedAt  `gorm:"index" json:"-"`
 
 	// Relations
-	Category  *ItemCategory    `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	Seller    *Seller          `gorm:"foreignKey:SellerID" json:"seller,omitempty"`
-	Organizer *Organizer       `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"`
-	Images    []ItemImage      `gorm:"foreignKey:ItemID" json:"images,omitempty"`
-	Schedule  *AuctionSchedule `gorm:"foreignKey:ItemID" json:"schedule,omitempty"`
-	Bids      []Bid            `gorm:"foreignKey:ItemID" json:"bids,omitempty"`
+	Category   *ItemCategory      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Seller     *Seller            `gorm:"foreignKey:SellerID" json:"seller,omitempty"`
+	Organizer  *Organizer         `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"`
+	Images     []ItemImage        `gorm:"foreignKey:ItemID" json:"images,omitempty"`
+	Schedule   *AuctionSchedule   `gorm:"foreignKey:ItemID" json:"schedule,omitempty"`
+	Bids       []Bid              `gorm:"foreignKey:ItemID" json:"bids,omitempty"`
+	Extensions []AuctionExtension `gorm:"foreignKey:ItemID" json:"extensions,omitempty"`
 }
 
 func (AuctionItem) TableName() string {
@@ -213,6 +244,8 @@ type AuctionSchedule struct {
 	AuctionStart      time.Time      `gorm:"type:timestamp;not null;index" json:"auction_start"`
 	AuctionEnd        time.Time      `gorm:"type:timestamp;not null;index" json:"auction_end"`
 	AnnouncementDate  *time.Time     `gorm:"type:timestamp" json:"announcement_date,omitempty"`
+	OriginalEnd       *time.Time     `gorm:"type:timestamp" json:"original_auction_end,omitempty"` // set on first soft-close extension
+	ExtensionCount    int            `gorm:"default:0" json:"extension_count"`
 	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
@@ -244,3 +277,107 @@ type Bid struct {
 func (Bid) TableName() string {
 	return "bids"
 }
+
+// BidCancellation is the audit record of an admin voiding a bid, with the
+// item's leading bid before and after the recomputation
+type BidCancellation struct {
+	ID                  uint            `gorm:"primaryKey;column:cancellation_id" json:"id"`
+	BidID               uint            `gorm:"not null;uniqueIndex" json:"bid_id"`
+	ItemID              uint            `gorm:"not null;index" json:"item_id"`
+	BidderID            string          `gorm:"type:uuid;not null;index" json:"bidder_id"`
+	CancelledBy         string          `gorm:"type:uuid;not null" json:"cancelled_by"`
+	Reason              string          `gorm:"type:text;not null" json:"reason"`
+	BidAmount           decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"bid_amount"`
+	PreviousHighestBid  decimal.Decimal `gorm:"type:decimal(15,2)" json:"previous_highest_bid"`
+	NewHighestBid       decimal.Decimal `gorm:"type:decimal(15,2)" json:"new_highest_bid"`
+	PreviousLeaderBidID *uint           `json:"previous_leader_bid_id,omitempty"`
+	NewLeaderBidID      *uint           `json:"new_leader_bid_id,omitempty"`
+	CreatedAt           time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (BidCancellation) TableName() string {
+	return "bid_cancellations"
+}
+
+// ProxyBid holds a bidder's secret maximum for an item. The proxy engine bids
+// on their behalf in increment steps until the maximum is reached.
+type ProxyBid struct {
+	ID        uint            `gorm:"primaryKey;column:proxy_bid_id" json:"id"`
+	ItemID    uint            `gorm:"not null;index" json:"item_id"`
+	UserID    string          `gorm:"type:uuid;not null;index" json:"user_id"`
+	MaxAmount decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"max_amount"`
+	Status    ProxyBidStatus  `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	PlacedAt  time.Time       `gorm:"type:timestamp;not null" json:"placed_at"` // reset on raise, used for tie-breaks
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt  `gorm:"index" json:"-"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (ProxyBid) TableName() string {
+	return "proxy_bids"
+}
+
+// SoftCloseRule extends an auction when a bid lands in its final minutes.
+// A rule for an item takes precedence over its organizer's rule.
+type SoftCloseRule struct {
+	ID               uint           `gorm:"primaryKey;column:rule_id" json:"id"`
+	OrganizerID      *uint          `gorm:"index" json:"organizer_id,omitempty"`
+	ItemID           *uint          `gorm:"index" json:"item_id,omitempty"`
+	WindowMinutes    int            `gorm:"not null" json:"window_minutes"`
+	ExtensionMinutes int            `gorm:"not null" json:"extension_minutes"`
+	MaxExtensions    int            `gorm:"not null" json:"max_extensions"`
+	IsActive         bool           `gorm:"not null" json:"is_active"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (SoftCloseRule) TableName() string {
+	return "soft_close_rules"
+}
+
+// AuctionExtension records one push of AuctionSchedule.AuctionEnd
+type AuctionExtension struct {
+	ID              uint      `gorm:"primaryKey;column:extension_id" json:"id"`
+	ItemID          uint      `gorm:"not null;index" json:"item_id"`
+	BidID           *uint     `gorm:"index" json:"bid_id,omitempty"`
+	RuleID          *uint     `json:"rule_id,omitempty"`
+	ExtensionNumber int       `gorm:"not null" json:"extension_number"`
+	PreviousEnd     time.Time `gorm:"type:timestamp;not null" json:"previous_end"`
+	NewEnd          time.Time `gorm:"type:timestamp;not null" json:"new_end"`
+	Reason          string    `gorm:"type:text;not null" json:"reason"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AuctionExtension) TableName() string {
+	return "auction_extensions"
+}
+
+// SealedBidReveal is the opening record of a closed-bidding lot. Ranking and
+// Digest are derived from the stored bids so the reveal can be re-verified.
+type SealedBidReveal struct {
+	ID           uint      `gorm:"primaryKey;column:reveal_id" json:"id"`
+	ItemID       uint      `gorm:"not null;uniqueIndex" json:"item_id"`
+	WinningBidID *uint     `json:"winning_bid_id,omitempty"`
+	BidCount     int       `gorm:"not null" json:"bid_count"`
+	Ranking      string    `gorm:"type:text;not null" json:"ranking"` // JSON array of SealedBidRank
+	Digest       string    `gorm:"type:varchar(64);not null" json:"digest"`
+	RevealedAt   time.Time `gorm:"type:timestamp;not null" json:"revealed_at"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (SealedBidReveal) TableName() string {
+	return "sealed_bid_reveals"
+}
+
+// SealedBidRank is one line of a sealed-bid ranking
+type SealedBidRank struct {
+	Rank      int       `json:"rank"`
+	BidID     uint      `json:"bid_id"`
+	UserID    string    `json:"user_id"`
+	BidAmount string    `json:"bid_amount"`
+	BidTime   time.Time `json:"bid_time"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ========== ENUMS ==========
+
+type DocumentType string
+
+const (
+	DocumentTypeAuctionMinutes DocumentType = "auction_minutes" // Risalah Lelang
+	DocumentTypeInvoice        DocumentType = "invoice"
+	DocumentTypeReceipt        DocumentType = "receipt" // kuitansi
+)
+
+// ========== MODELS ==========
+
+// Document is an issued official document. Content is the canonical JSON
+// snapshot the PDF and HTML are rendered from, and ContentHash its SHA-256,
+// so a copy can be checked against what was issued. Reissuing after the
+// underlying data changed adds a new revision instead of altering this one.
+//
+// Invoices and receipts belong to the user they were issued to (OwnerID) and
+// are issued once per source, e.g. one invoice per settlement. EmailedAt is
+// set once the document mailer has queued the owner's copy.
+type Document struct {
+	ID             uint         `gorm:"primaryKey;column:document_id" json:"id"`
+	DocumentType   DocumentType `gorm:"type:varchar(30);not null;index:idx_document_item" json:"document_type"`
+	DocumentNumber string       `gorm:"type:varchar(100);uniqueIndex;not null" json:"document_number"`
+	ItemID         *uint        `gorm:"index:idx_document_item" json:"item_id,omitempty"`
+	OwnerID        *string      `gorm:"type:uuid;index" json:"owner_id,omitempty"`
+	SourceKey      *string      `gorm:"type:varchar(100);uniqueIndex" json:"source_key,omitempty"` // e.g. invoice:settlement:12
+	Revision       int          `gorm:"not null;default:1" json:"revision"`
+	ContentHash    string       `gorm:"type:varchar(64);not null" json:"content_hash"`
+	Content        string       `gorm:"type:text;not null" json:"-"`
+	IssuedBy       *string      `gorm:"type:uuid" json:"issued_by,omitempty"`
+	IssuedAt       time.Time    `gorm:"type:timestamp;not null" json:"issued_at"`
+	EmailedAt      *time.Time   `gorm:"type:timestamp" json:"emailed_at,omitempty"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Document) TableName() string {
+	return "documents"
+}
+
+// DocumentSequence hands out gap-free document numbers per scope, e.g. one
+// invoice series per organizer and year
+type DocumentSequence struct {
+	Scope     string    `gorm:"type:varchar(100);primaryKey" json:"scope"`
+	LastValue int       `gorm:"not null;default:0" json:"last_value"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DocumentSequence) TableName() string {
+	return "document_sequences"
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ========== ENUMS ==========
+
+type FeeParty string
+
+const (
+	FeePartyBuyer  FeeParty = "buyer"
+	FeePartySeller FeeParty = "seller"
+)
+
+type FeeKind string
+
+const (
+	FeeKindDuty    FeeKind = "duty"    // bea lelang
+	FeeKindPremium FeeKind = "premium" // buyer's premium of a private auction house
+	FeeKindTax     FeeKind = "tax"     // PPh, BPHTB; collected on behalf of the state
+)
+
+// ========== MODELS ==========
+
+// FeeRule is one line of the fee schedule. Rules sharing a Code are
+// alternatives: for each code the most specific active rule matching the
+// item (category, then organizer type, then item type) applies. The charge
+// is Rate percent of the hammer price above ExemptAmount plus FixedAmount,
+// bounded by MinAmount and MaxAmount.
+type FeeRule struct {
+	ID            uint             `gorm:"primaryKey;column:rule_id" json:"id"`
+	Code          string           `gorm:"type:varchar(50);not null;index" json:"code"`
+	Name          string           `gorm:"type:varchar(255);not null" json:"name"`
+	Party         FeeParty         `gorm:"type:varchar(20);not null" json:"party"`
+	Kind          FeeKind          `gorm:"type:varchar(20);not null" json:"kind"`
+	ItemType      *ItemType        `gorm:"type:varchar(20)" json:"item_type,omitempty"`
+	OrganizerType *OrganizerType   `gorm:"type:varchar(20)" json:"organizer_type,omitempty"`
+	CategoryID    *uint            `gorm:"index" json:"category_id,omitempty"`
+	Rate          decimal.Decimal  `gorm:"type:decimal(7,4);not null;default:0" json:"rate"` // percent
+	FixedAmount   decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"fixed_amount"`
+	ExemptAmount  decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"exempt_amount"` // e.g. NPOPTKP for BPHTB
+	MinAmount     decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"min_amount"`
+	MaxAmount     *decimal.Decimal `gorm:"type:decimal(15,2)" json:"max_amount,omitempty"`
+	IsActive      bool             `gorm:"not null" json:"is_active"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt   `gorm:"index" json:"-"`
+}
+
+func (FeeRule) TableName() string {
+	return "fee_rules"
+}
+
+// SettlementCharge is a fee or tax fixed on a settlement when it opens, so
+// later changes to the schedule do not alter what a winner was billed
+type SettlementCharge struct {
+	ID           uint            `gorm:"primaryKey;column:charge_id" json:"id"`
+	SettlementID uint            `gorm:"not null;index" json:"settlement_id"`
+	RuleID       *uint           `json:"rule_id,omitempty"`
+	Code         string          `gorm:"type:varchar(50);not null" json:"code"`
+	Name         string          `gorm:"type:varchar(255);not null" json:"name"`
+	Party        FeeParty        `gorm:"type:varchar(20);not null" json:"party"`
+	Kind         FeeKind         `gorm:"type:varchar(20);not null" json:"kind"`
+	Rate         decimal.Decimal `gorm:"type:decimal(7,4);not null" json:"rate"`
+	Amount       decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"amount"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (SettlementCharge) TableName() string {
+	return "settlement_charges"
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type HoldKind string
+
+const (
+	HoldKindBid     HoldKind = "bid"
+	HoldKindDeposit HoldKind = "deposit"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusReleased HoldStatus = "released"
+	HoldStatusCaptured HoldStatus = "captured"
+)
+
+// ========== MODELS ==========
+
+// FundsHold reserves part of a user's balance for one item. A user has at most
+// one active hold per item and kind; its amount moves with their bidding. The
+// sum of active holds is the balance of the user's held ledger account.
+type FundsHold struct {
+	ID         uint            `gorm:"primaryKey;column:hold_id" json:"id"`
+	UserID     string          `gorm:"type:uuid;not null;index" json:"user_id"`
+	ItemID     uint            `gorm:"not null;index" json:"item_id"`
+	Kind       HoldKind        `gorm:"type:varchar(20);not null" json:"kind"`
+	Amount     decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Status     HoldStatus      `gorm:"type:varchar(20);not null;index" json:"status"`
+	ReleasedAt *time.Time      `gorm:"type:timestamp" json:"released_at,omitempty"`
+	CapturedAt *time.Time      `gorm:"type:timestamp" json:"captured_at,omitempty"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (FundsHold) TableName() string {
+	return "funds_holds"
+}
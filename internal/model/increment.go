@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ========== MODELS ==========
+
+// IncrementTable sets the bid increment by price band. It is assigned to
+// exactly one of an item, a category or an organizer; for an item the most
+// specific active table applies (item, its category, the parent category,
+// then the organizer), falling back to the item's fixed IncrementAmount.
+type IncrementTable struct {
+	ID          uint           `gorm:"primaryKey;column:table_id" json:"id"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
+	OrganizerID *uint          `gorm:"index" json:"organizer_id,omitempty"`
+	CategoryID  *uint          `gorm:"index" json:"category_id,omitempty"`
+	ItemID      *uint          `gorm:"index" json:"item_id,omitempty"`
+	IsActive    bool           `gorm:"not null" json:"is_active"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	Bands []IncrementBand `gorm:"foreignKey:TableID" json:"bands"`
+}
+
+func (IncrementTable) TableName() string {
+	return "increment_tables"
+}
+
+// IncrementBand applies Increment to prices from MinPrice up to the next
+// band's MinPrice. The lowest band of a table starts at zero.
+type IncrementBand struct {
+	ID        uint            `gorm:"primaryKey;column:band_id" json:"id"`
+	TableID   uint            `gorm:"not null;index" json:"table_id"`
+	MinPrice  decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"min_price"`
+	Increment decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"increment"`
+}
+
+func (IncrementBand) TableName() string {
+	return "increment_bands"
+}
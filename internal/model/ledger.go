@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type LedgerAccountType string
+
+const (
+	LedgerAccountAsset     LedgerAccountType = "asset"     // debit-normal
+	LedgerAccountLiability LedgerAccountType = "liability" // credit-normal
+	LedgerAccountEquity    LedgerAccountType = "equity"    // credit-normal
+	LedgerAccountRevenue   LedgerAccountType = "revenue"   // credit-normal
+)
+
+type JournalEntryType string
+
+const (
+	JournalEntryTopUp      JournalEntryType = "topup"
+	JournalEntryHold       JournalEntryType = "hold"
+	JournalEntryRelease    JournalEntryType = "release"
+	JournalEntryCharge     JournalEntryType = "charge"
+	JournalEntryRefund     JournalEntryType = "refund"
+	JournalEntryFee        JournalEntryType = "fee"
+	JournalEntryWithdrawal JournalEntryType = "withdrawal"
+	JournalEntryAdjustment JournalEntryType = "adjustment"
+	JournalEntryForfeit    JournalEntryType = "forfeit"
+	JournalEntryOpening    JournalEntryType = "opening_balance" // legacy User.Balance carried into the ledger
+)
+
+// ========== MODELS ==========
+
+// LedgerAccount is one account of the double-entry ledger. Each user has an
+// available and a held wallet account; platform accounts have no UserID.
+type LedgerAccount struct {
+	ID        uint              `gorm:"primaryKey;column:account_id" json:"id"`
+	Code      string            `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"`
+	Name      string            `gorm:"type:varchar(255);not null" json:"name"`
+	Type      LedgerAccountType `gorm:"type:varchar(20);not null" json:"type"`
+	UserID    *string           `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	CreatedAt time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+// JournalEntry is one balanced posting. Entries and their lines are never
+// updated or deleted; corrections are new entries.
+type JournalEntry struct {
+	ID          uint             `gorm:"primaryKey;column:entry_id" json:"id"`
+	EntryType   JournalEntryType `gorm:"type:varchar(30);not null;index" json:"entry_type"`
+	Description string           `gorm:"type:text;not null" json:"description"`
+	Reference   *string          `gorm:"type:varchar(255);uniqueIndex" json:"reference,omitempty"` // idempotency key
+	ItemID      *uint            `gorm:"index" json:"item_id,omitempty"`
+	CreatedBy   *string          `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// Relations
+	Lines []JournalLine `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+}
+
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// JournalLine debits or credits one account; exactly one side is non-zero
+type JournalLine struct {
+	ID        uint            `gorm:"primaryKey;column:line_id" json:"id"`
+	EntryID   uint            `gorm:"not null;index" json:"entry_id"`
+	AccountID uint            `gorm:"not null;index" json:"account_id"`
+	Debit     decimal.Decimal `gorm:"type:decimal(15,2);not null;default:0" json:"debit"`
+	Credit    decimal.Decimal `gorm:"type:decimal(15,2);not null;default:0" json:"credit"`
+
+	// Relations
+	Entry   *JournalEntry  `gorm:"foreignKey:EntryID" json:"entry,omitempty"`
+	Account *LedgerAccount `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+func (JournalLine) TableName() string {
+	return "journal_lines"
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// ========== ENUMS ==========
+
+type NotificationType string
+
+const (
+	NotificationOutbid           NotificationType = "outbid"
+	NotificationEndingSoon       NotificationType = "ending_soon"
+	NotificationWon              NotificationType = "won"
+	NotificationLost             NotificationType = "lost"
+	NotificationAuctionCancelled NotificationType = "auction_cancelled"
+	NotificationBidCancelled     NotificationType = "bid_cancelled"   // an admin voided one of the user's bids
+	NotificationWatchStarting    NotificationType = "watch_starting"  // watched lot opens soon
+	NotificationWatchEnding      NotificationType = "watch_ending"    // watched lot closes soon
+	NotificationDocumentIssued   NotificationType = "document_issued" // invoice or receipt, emailed by the document mailer
+	NotificationSavedSearchMatch NotificationType = "saved_search_match"
+	NotificationSavedSearchDaily NotificationType = "saved_search_digest"
+)
+
+// ========== MODELS ==========
+
+// Notification tells a user about something that happened on an auction.
+// Payload is a JSON snapshot of what the message shows, taken when it was
+// raised. DedupKey makes raising the same notice twice a no-op, e.g. one
+// "ending soon" per bidder and lot. EmailedAt is set once the notification
+// mailer has queued the email; notifications whose email goes out elsewhere
+// are stored as already emailed. ReadAt is set when the user reads it in the
+// notification center.
+type Notification struct {
+	ID        uint             `gorm:"primaryKey;column:notification_id" json:"id"`
+	UserID    string           `gorm:"type:uuid;not null;index:idx_notification_user_item" json:"user_id"`
+	Type      NotificationType `gorm:"type:varchar(30);not null" json:"type"`
+	ItemID    *uint            `gorm:"index:idx_notification_user_item" json:"item_id,omitempty"`
+	BidID     *uint            `json:"bid_id,omitempty"`
+	Payload   string           `gorm:"type:text;not null" json:"-"`
+	DedupKey  string           `gorm:"type:varchar(150);uniqueIndex;not null" json:"-"`
+	EmailedAt *time.Time       `gorm:"type:timestamp;index" json:"emailed_at,omitempty"`
+	ReadAt    *time.Time       `gorm:"type:timestamp" json:"read_at,omitempty"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type ParticipantStatus string
+
+const (
+	ParticipantStatusRegistered  ParticipantStatus = "registered"   // waiting for the deposit
+	ParticipantStatusDepositPaid ParticipantStatus = "deposit_paid" // holds a NUP and may bid
+	ParticipantStatusRefunded    ParticipantStatus = "refunded"     // deposit returned after close
+	ParticipantStatusApplied     ParticipantStatus = "applied"      // winner's deposit credited against the price
+	ParticipantStatusForfeited   ParticipantStatus = "forfeited"    // winner missed the payment deadline
+)
+
+// ========== MODELS ==========
+
+// AuctionParticipant is a user's registration for one lot. The deposit
+// (jaminan) is held in the user's wallet once paid, at which point the
+// participant receives their participant number (NUP).
+type AuctionParticipant struct {
+	ID                uint              `gorm:"primaryKey;column:participant_id" json:"id"`
+	ItemID            uint              `gorm:"not null;uniqueIndex:idx_participant_item_user" json:"item_id"`
+	UserID            string            `gorm:"type:uuid;not null;uniqueIndex:idx_participant_item_user;index" json:"user_id"`
+	NUP               *string           `gorm:"column:nup;type:varchar(60);uniqueIndex" json:"nup,omitempty"`
+	Status            ParticipantStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	DepositAmount     decimal.Decimal   `gorm:"type:decimal(15,2);not null" json:"deposit_amount"`
+	RegisteredAt      time.Time         `gorm:"type:timestamp;not null" json:"registered_at"`
+	DepositPaidAt     *time.Time        `gorm:"type:timestamp" json:"deposit_paid_at,omitempty"`
+	DepositRefundedAt *time.Time        `gorm:"type:timestamp" json:"deposit_refunded_at,omitempty"`
+	CreatedAt         time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	User *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (AuctionParticipant) TableName() string {
+	return "auction_participants"
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type SavedSearchDelivery string
+
+const (
+	SavedSearchDeliveryInstant     SavedSearchDelivery = "instant"      // one alert per matching lot as it is published
+	SavedSearchDeliveryDailyDigest SavedSearchDelivery = "daily_digest" // matches collected into one alert a day
+)
+
+// ========== MODELS ==========
+
+// SavedSearch is a buyer's listing search kept to be alerted about newly
+// published lots that match it. The filters mirror the public listing's.
+// UnsubscribeToken backs the unsubscribe link in alert emails, which works
+// without signing in; unsubscribing deactivates the search.
+type SavedSearch struct {
+	ID               uint                `gorm:"primaryKey;column:saved_search_id" json:"id"`
+	UserID           string              `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name             string              `gorm:"type:varchar(100);not null" json:"name"`
+	Search           string              `gorm:"type:varchar(255)" json:"search,omitempty"`
+	CategoryID       *uint               `gorm:"index" json:"category_id,omitempty"`
+	Province         *string             `gorm:"type:varchar(100)" json:"province,omitempty"`
+	MinPrice         *decimal.Decimal    `gorm:"type:decimal(15,2)" json:"min_price,omitempty"`
+	MaxPrice         *decimal.Decimal    `gorm:"type:decimal(15,2)" json:"max_price,omitempty"`
+	Delivery         SavedSearchDelivery `gorm:"type:varchar(20);not null" json:"delivery"`
+	Active           bool                `gorm:"default:true;index" json:"active"`
+	UnsubscribeToken string              `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	LastDigestAt     *time.Time          `gorm:"type:timestamp" json:"last_digest_at,omitempty"`
+	CreatedAt        time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// SavedSearchMatch records that a published lot matched a saved search, so a
+// lot is alerted once per search. Digest searches collect matches until the
+// daily digest sets DigestedAt; instant matches are alerted straight away and
+// stored as digested.
+type SavedSearchMatch struct {
+	ID            uint       `gorm:"primaryKey;column:match_id" json:"id"`
+	SavedSearchID uint       `gorm:"not null;uniqueIndex:idx_saved_search_match" json:"saved_search_id"`
+	ItemID        uint       `gorm:"not null;uniqueIndex:idx_saved_search_match" json:"item_id"`
+	DigestedAt    *time.Time `gorm:"type:timestamp" json:"digested_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (SavedSearchMatch) TableName() string {
+	return "saved_search_matches"
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type SecondChanceStatus string
+
+const (
+	SecondChanceStatusPending  SecondChanceStatus = "pending"
+	SecondChanceStatusAccepted SecondChanceStatus = "accepted"
+	SecondChanceStatusDeclined SecondChanceStatus = "declined"
+	SecondChanceStatusExpired  SecondChanceStatus = "expired"
+)
+
+// ========== MODELS ==========
+
+// SecondChanceOffer offers a lot whose winner fell through to the runner-up
+// at the runner-up's own bid. A lot has at most one pending offer; declined
+// or expired offers let the admin move on to the next bidder.
+type SecondChanceOffer struct {
+	ID          uint               `gorm:"primaryKey;column:offer_id" json:"id"`
+	ItemID      uint               `gorm:"not null;index" json:"item_id"`
+	BidID       uint               `gorm:"not null" json:"bid_id"`
+	UserID      string             `gorm:"type:uuid;not null;index" json:"user_id"`
+	Amount      decimal.Decimal    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Status      SecondChanceStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	ExpiresAt   time.Time          `gorm:"type:timestamp;not null;index" json:"expires_at"`
+	RespondedAt *time.Time         `gorm:"type:timestamp" json:"responded_at,omitempty"`
+	OfferedBy   string             `gorm:"type:uuid;not null" json:"offered_by"`
+	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (SecondChanceOffer) TableName() string {
+	return "second_chance_offers"
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type SettlementStatus string
+
+const (
+	SettlementStatusPending   SettlementStatus = "pending_payment"
+	SettlementStatusPaid      SettlementStatus = "paid"
+	SettlementStatusDefaulted SettlementStatus = "defaulted" // deadline missed, deposit forfeited
+	SettlementStatusCancelled SettlementStatus = "cancelled" // winning bid cancelled by an admin
+)
+
+// ========== MODELS ==========
+
+// Settlement is the winner's payment obligation for a sold lot. The buyer's
+// fees and taxes are added to the winning bid, the deposit is credited
+// against that total and the rest is due by PaymentDeadline.
+// A lot has more than one settlement when a second-chance offer is accepted
+// after the first winner defaulted; the latest one is current.
+type Settlement struct {
+	ID                uint             `gorm:"primaryKey;column:settlement_id" json:"id"`
+	ItemID            uint             `gorm:"not null;index:idx_settlement_item" json:"item_id"`
+	WinnerID          string           `gorm:"type:uuid;not null;index" json:"winner_id"`
+	WinningBidID      uint             `gorm:"not null" json:"winning_bid_id"`
+	BidAmount         decimal.Decimal  `gorm:"type:decimal(15,2);not null" json:"bid_amount"`
+	BuyerCharges      decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"buyer_charges"`
+	TotalAmount       decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"total_amount"` // bid plus buyer charges
+	SellerCharges     decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"seller_charges"`
+	SellerNet         decimal.Decimal  `gorm:"type:decimal(15,2);not null;default:0" json:"seller_net"`
+	DepositCredit     decimal.Decimal  `gorm:"type:decimal(15,2);not null" json:"deposit_credit"`
+	OutstandingAmount decimal.Decimal  `gorm:"type:decimal(15,2);not null" json:"outstanding_amount"`
+	PaymentDeadline   time.Time        `gorm:"type:timestamp;not null;index" json:"payment_deadline"`
+	Status            SettlementStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	PaidAt            *time.Time       `gorm:"type:timestamp" json:"paid_at,omitempty"`
+	DefaultedAt       *time.Time       `gorm:"type:timestamp" json:"defaulted_at,omitempty"`
+	CancelledAt       *time.Time       `gorm:"type:timestamp" json:"cancelled_at,omitempty"`
+	CreatedAt         time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Item    *AuctionItem       `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Charges []SettlementCharge `gorm:"foreignKey:SettlementID" json:"charges,omitempty"`
+}
+
+func (Settlement) TableName() string {
+	return "settlements"
+}
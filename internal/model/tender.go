@@ -0,0 +1,146 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ========== ENUMS ==========
+
+type TenderStatus string
+
+const (
+	TenderStatusOpen       TenderStatus = "open"       // accepting offers
+	TenderStatusEvaluating TenderStatus = "evaluating" // submissions closed, committee scoring
+	TenderStatusPublished  TenderStatus = "published"  // ranking signed off, winner declared
+)
+
+type TenderOfferStatus string
+
+const (
+	TenderOfferStatusSubmitted   TenderOfferStatus = "submitted"
+	TenderOfferStatusWithdrawn   TenderOfferStatus = "withdrawn"
+	TenderOfferStatusWinner      TenderOfferStatus = "winner"
+	TenderOfferStatusNotSelected TenderOfferStatus = "not_selected"
+)
+
+type CommitteeRole string
+
+const (
+	CommitteeRoleChair  CommitteeRole = "chair"
+	CommitteeRoleMember CommitteeRole = "member"
+)
+
+// ========== MODELS ==========
+
+// Tender holds the evaluation state of an AuctionItem sold by AuctionMethodTender
+type Tender struct {
+	ID             uint           `gorm:"primaryKey;column:tender_id" json:"id"`
+	ItemID         uint           `gorm:"not null;uniqueIndex" json:"item_id"`
+	Status         TenderStatus   `gorm:"type:varchar(20);not null;index" json:"status"`
+	WinningOfferID *uint          `json:"winning_offer_id,omitempty"`
+	OpenedAt       time.Time      `gorm:"type:timestamp;not null" json:"opened_at"`
+	ClosedAt       *time.Time     `gorm:"type:timestamp" json:"closed_at,omitempty"`
+	PublishedAt    *time.Time     `gorm:"type:timestamp" json:"published_at,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	Item      *AuctionItem            `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Criteria  []TenderCriterion       `gorm:"foreignKey:TenderID" json:"criteria,omitempty"`
+	Committee []TenderCommitteeMember `gorm:"foreignKey:TenderID" json:"committee,omitempty"`
+}
+
+func (Tender) TableName() string {
+	return "tenders"
+}
+
+// TenderCriterion is one weighted evaluation criterion. The price criterion is
+// scored automatically from the offer amounts; the others by the committee.
+type TenderCriterion struct {
+	ID          uint            `gorm:"primaryKey;column:criterion_id" json:"id"`
+	TenderID    uint            `gorm:"not null;index" json:"tender_id"`
+	Name        string          `gorm:"type:varchar(255);not null" json:"name"`
+	Description *string         `gorm:"type:text" json:"description,omitempty"`
+	Weight      decimal.Decimal `gorm:"type:decimal(5,2);not null" json:"weight"`
+	IsPrice     bool            `gorm:"default:false" json:"is_price"`
+	MaxScore    decimal.Decimal `gorm:"type:decimal(5,2);not null" json:"max_score"`
+}
+
+func (TenderCriterion) TableName() string {
+	return "tender_criteria"
+}
+
+// TenderCommitteeMember is an evaluator; SignedDigest pins the ranking they approved
+type TenderCommitteeMember struct {
+	ID           uint          `gorm:"primaryKey;column:member_id" json:"id"`
+	TenderID     uint          `gorm:"not null;index" json:"tender_id"`
+	UserID       string        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Role         CommitteeRole `gorm:"type:varchar(20);not null" json:"role"`
+	SignedDigest *string       `gorm:"type:varchar(64)" json:"signed_digest,omitempty"`
+	SignedOffAt  *time.Time    `gorm:"type:timestamp" json:"signed_off_at,omitempty"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (TenderCommitteeMember) TableName() string {
+	return "tender_committee_members"
+}
+
+// TenderOffer is a bidder's offer; one per bidder, revisable while open
+type TenderOffer struct {
+	ID          uint              `gorm:"primaryKey;column:offer_id" json:"id"`
+	TenderID    uint              `gorm:"not null;index" json:"tender_id"`
+	UserID      string            `gorm:"type:uuid;not null;index" json:"user_id"`
+	OfferAmount decimal.Decimal   `gorm:"type:decimal(15,2);not null" json:"offer_amount"`
+	Notes       *string           `gorm:"type:text" json:"notes,omitempty"`
+	Status      TenderOfferStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	TotalScore  *decimal.Decimal  `gorm:"type:decimal(7,4)" json:"total_score,omitempty"`
+	Rank        *int              `json:"rank,omitempty"`
+	SubmittedAt time.Time         `gorm:"type:timestamp;not null" json:"submitted_at"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `gorm:"index" json:"-"`
+
+	// Relations
+	Documents []TenderDocument `gorm:"foreignKey:OfferID" json:"documents,omitempty"`
+	User      *User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (TenderOffer) TableName() string {
+	return "tender_offers"
+}
+
+// TenderDocument is a supporting document attached to an offer
+type TenderDocument struct {
+	ID           uint      `gorm:"primaryKey;column:document_id" json:"id"`
+	OfferID      uint      `gorm:"not null;index" json:"offer_id"`
+	DocumentName string    `gorm:"type:varchar(255);not null" json:"document_name"`
+	DocumentURL  string    `gorm:"type:varchar(500);not null" json:"document_url"`
+	DocumentType *string   `gorm:"type:varchar(50)" json:"document_type,omitempty"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (TenderDocument) TableName() string {
+	return "tender_documents"
+}
+
+// TenderScore is one evaluator's score for one offer on one criterion
+type TenderScore struct {
+	ID          uint            `gorm:"primaryKey;column:score_id" json:"id"`
+	OfferID     uint            `gorm:"not null;uniqueIndex:idx_tender_score" json:"offer_id"`
+	CriterionID uint            `gorm:"not null;uniqueIndex:idx_tender_score" json:"criterion_id"`
+	EvaluatorID string          `gorm:"type:uuid;not null;uniqueIndex:idx_tender_score" json:"evaluator_id"`
+	Score       decimal.Decimal `gorm:"type:decimal(5,2);not null" json:"score"`
+	Notes       *string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TenderScore) TableName() string {
+	return "tender_scores"
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ========== ENUMS ==========
+
+type TopUpStatus string
+
+const (
+	TopUpStatusPending TopUpStatus = "pending"
+	TopUpStatusPaid    TopUpStatus = "paid"
+	TopUpStatusExpired TopUpStatus = "expired"
+	TopUpStatusFailed  TopUpStatus = "failed"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodVirtualAccount PaymentMethod = "virtual_account"
+)
+
+// ========== MODELS ==========
+
+// TopUpOrder is a request to add funds to a wallet. The user pays into the
+// virtual account issued by the gateway; the gateway's webhook then marks the
+// order paid and the wallet is credited through the ledger.
+type TopUpOrder struct {
+	ID               uint            `gorm:"primaryKey;column:order_id" json:"id"`
+	OrderCode        string          `gorm:"type:varchar(50);uniqueIndex;not null" json:"order_code"`
+	UserID           string          `gorm:"type:uuid;not null;index" json:"user_id"`
+	Amount           decimal.Decimal `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Method           PaymentMethod   `gorm:"type:varchar(30);not null" json:"method"`
+	Bank             string          `gorm:"type:varchar(20);not null" json:"bank"`
+	Gateway          string          `gorm:"type:varchar(30);not null" json:"gateway"`
+	GatewayReference *string         `gorm:"type:varchar(100);index" json:"gateway_reference,omitempty"`
+	VANumber         *string         `gorm:"column:va_number;type:varchar(50)" json:"va_number,omitempty"`
+	Status           TopUpStatus     `gorm:"type:varchar(20);not null;index" json:"status"`
+	ExpiresAt        time.Time       `gorm:"type:timestamp;not null;index" json:"expires_at"`
+	PaidAt           *time.Time      `gorm:"type:timestamp" json:"paid_at,omitempty"`
+	FailureReason    *string         `gorm:"type:text" json:"failure_reason,omitempty"`
+	JournalEntryID   *uint           `json:"journal_entry_id,omitempty"`
+	CreatedAt        time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TopUpOrder) TableName() string {
+	return "topup_orders"
+}
@@ -16,6 +16,13 @@ const (
 	UserStatusBlocked   UserStatus = "blocked"
 )
 
+// User types. Only admins may use the /admin routes; the type can't be
+// chosen at registration.
+const (
+	UserTypeMember = "member"
+	UserTypeAdmin  = "admin"
+)
+
 type IDCardType string
 
 const (
@@ -46,8 +53,12 @@ type User struct {
 	Province   *string `gorm:"type:varchar(100)" json:"province,omitempty"`
 	PostalCode *string `gorm:"type:varchar(10)" json:"postal_code,omitempty"`
 
-	// Balance for auction bidding
-	Balance decimal.Decimal `gorm:"type:decimal(15,2);default:0" json:"balance"`
+	// Balance for auction bidding. Balance is the total; HeldBalance is the
+	// part reserved by active holds and not available for new bids. Both are
+	// a read projection of the user's ledger accounts, written only by
+	// LedgerService; never change them directly.
+	Balance     decimal.Decimal `gorm:"type:decimal(15,2);default:0" json:"balance"`
+	HeldBalance decimal.Decimal `gorm:"type:decimal(15,2);default:0" json:"held_balance"`
 
 	// Status and Verification
 	IsActive          bool       `gorm:"default:true" json:"is_active"`
@@ -87,3 +98,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 func (User) TableName() string {
 	return "users"
 }
+
+// AvailableBalance is the part of the balance not reserved by holds
+func (u *User) AvailableBalance() decimal.Decimal {
+	return u.Balance.Sub(u.HeldBalance)
+}
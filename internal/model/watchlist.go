@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ========== MODELS ==========
+
+// WatchlistEntry is a user following a lot without bidding on it. Watchers
+// are reminded before the lot starts and before it ends.
+type WatchlistEntry struct {
+	ID        uint      `gorm:"primaryKey;column:watchlist_id" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_watchlist_user_item" json:"user_id"`
+	ItemID    uint      `gorm:"not null;uniqueIndex:idx_watchlist_user_item;index" json:"item_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relations
+	Item *AuctionItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+func (WatchlistEntry) TableName() string {
+	return "watchlist_entries"
+}
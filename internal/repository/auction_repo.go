@@ -1,9 +1,14 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"yourapp/internal/model"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ========== SELLER REPOSITORY ==========
@@ -142,15 +147,28 @@ func (r *categoryRepository) Delete(id uint) error {
 // ========== AUCTION ITEM REPOSITORY ==========
 
 type AuctionItemRepository interface {
+	WithTx(tx *gorm.DB) AuctionItemRepository
 	Create(item *model.AuctionItem) error
 	FindByID(id uint) (*model.AuctionItem, error)
+	FindByIDForUpdate(id uint) (*model.AuctionItem, error)
+	FindByIDForUpdateSkipLocked(id uint) (*model.AuctionItem, error)
+	FindDueToStart(now time.Time, limit int) ([]uint, error)
+	FindDueToClose(now time.Time, after *model.AuctionSchedule, limit int) ([]model.AuctionSchedule, error)
+	FindEndingBetween(from, to time.Time) ([]uint, error)
+	FindStartingBetween(from, to time.Time) ([]uint, error)
 	FindByLotCode(lotCode string) (*model.AuctionItem, error)
 	FindAll(filters AuctionItemFilters) ([]model.AuctionItem, int64, error)
 	FindPublished(filters AuctionItemFilters) ([]model.AuctionItem, int64, error)
+	FindLive() ([]model.AuctionItem, error)
 	Update(item *model.AuctionItem) error
 	UpdateStatus(id uint, status model.AuctionStatus) error
 	UpdateBidInfo(id uint, highestBid float64, bidCount int) error
+	CloseWithOutcome(id uint, outcome model.AuctionOutcome, reason *string, closedAt time.Time) error
+	Cancel(id uint, reason string, cancelledAt time.Time) error
+	FlagForReauction(id uint, reason string) error
+	RecordSecondChanceSale(id uint) error
 	IncrementViewCount(id uint) error
+	AdjustWatcherCount(id uint, delta int) error
 	Delete(id uint) error
 }
 
@@ -159,6 +177,9 @@ type AuctionItemFilters struct {
 	SellerID   *string
 	Status     *model.AuctionStatus
 	Search     string
+	Province   string           // the organizer's province
+	MinPrice   *decimal.Decimal // on the starting price
+	MaxPrice   *decimal.Decimal
 	Page       int
 	Limit      int
 	SortBy     string
@@ -173,6 +194,10 @@ func NewAuctionItemRepository(db *gorm.DB) AuctionItemRepository {
 	return &auctionItemRepository{db: db}
 }
 
+func (r *auctionItemRepository) WithTx(tx *gorm.DB) AuctionItemRepository {
+	return &auctionItemRepository{db: tx}
+}
+
 func (r *auctionItemRepository) Create(item *model.AuctionItem) error {
 	return r.db.Create(item).Error
 }
@@ -187,10 +212,113 @@ func (r *auctionItemRepository) FindByID(id uint) (*model.AuctionItem, error) {
 			return db.Order("display_order ASC")
 		}).
 		Preload("Schedule").
+		Preload("Extensions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("extension_number ASC")
+		}).
 		First(&item, id).Error
 	return &item, err
 }
 
+// FindByIDForUpdate locks the auction_items row (SELECT ... FOR UPDATE) until
+// the surrounding transaction ends. Only the schedule is loaded alongside it.
+func (r *auctionItemRepository) FindByIDForUpdate(id uint) (*model.AuctionItem, error) {
+	var item model.AuctionItem
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&item, id).Error
+	if err != nil {
+		return &item, err
+	}
+
+	var schedule model.AuctionSchedule
+	if err := r.db.Where("item_id = ?", id).First(&schedule).Error; err == nil {
+		item.Schedule = &schedule
+	}
+	return &item, nil
+}
+
+// FindByIDForUpdateSkipLocked is FindByIDForUpdate for background jobs: a row
+// already locked by another transaction or replica yields ErrRecordNotFound
+// instead of waiting.
+func (r *auctionItemRepository) FindByIDForUpdateSkipLocked(id uint) (*model.AuctionItem, error) {
+	var item model.AuctionItem
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		First(&item, id).Error
+	if err != nil {
+		return &item, err
+	}
+
+	var schedule model.AuctionSchedule
+	if err := r.db.Where("item_id = ?", id).First(&schedule).Error; err == nil {
+		item.Schedule = &schedule
+	}
+	return &item, nil
+}
+
+// FindDueToStart returns published items whose bidding window is open now
+func (r *auctionItemRepository) FindDueToStart(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.AuctionItem{}).
+		Joins("JOIN auction_schedules ON auction_schedules.item_id = auction_items.item_id").
+		Where("auction_items.status = ?", model.AuctionStatusPublished).
+		Where("auction_schedules.auction_start <= ? AND auction_schedules.auction_end > ?", now, now).
+		Order("auction_schedules.auction_start ASC").
+		Limit(limit).
+		Pluck("auction_items.item_id", &ids).Error
+	return ids, err
+}
+
+// FindDueToClose returns the item id and end of running items whose end has
+// passed, however long ago, oldest end first. after continues past the last
+// item of an earlier batch; nil starts from the oldest. Tender lots are closed
+// by their committee instead.
+func (r *auctionItemRepository) FindDueToClose(now time.Time, after *model.AuctionSchedule, limit int) ([]model.AuctionSchedule, error) {
+	query := r.db.Model(&model.AuctionItem{}).
+		Joins("JOIN auction_schedules ON auction_schedules.item_id = auction_items.item_id").
+		Where("auction_items.status IN ?", []model.AuctionStatus{model.AuctionStatusPublished, model.AuctionStatusOngoing}).
+		Where("(auction_items.auction_method IS NULL OR auction_items.auction_method != ?)", model.AuctionMethodTender).
+		Where("auction_schedules.auction_end <= ?", now)
+	if after != nil {
+		query = query.Where("(auction_schedules.auction_end, auction_schedules.item_id) > (?, ?)", after.AuctionEnd, after.ItemID)
+	}
+
+	var due []model.AuctionSchedule
+	err := query.
+		Select("auction_schedules.item_id, auction_schedules.auction_end").
+		Order("auction_schedules.auction_end ASC, auction_schedules.item_id ASC").
+		Limit(limit).
+		Scan(&due).Error
+	return due, err
+}
+
+// FindEndingBetween returns running items due to end after from and no later
+// than to. Tender lots have no fixed end and are left out.
+func (r *auctionItemRepository) FindEndingBetween(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.AuctionItem{}).
+		Joins("JOIN auction_schedules ON auction_schedules.item_id = auction_items.item_id").
+		Where("auction_items.status IN ?", []model.AuctionStatus{model.AuctionStatusPublished, model.AuctionStatusOngoing}).
+		Where("(auction_items.auction_method IS NULL OR auction_items.auction_method != ?)", model.AuctionMethodTender).
+		Where("auction_schedules.auction_end > ? AND auction_schedules.auction_end <= ?", from, to).
+		Order("auction_schedules.auction_end ASC").
+		Pluck("auction_items.item_id", &ids).Error
+	return ids, err
+}
+
+// FindStartingBetween returns published items due to start after from and no
+// later than to
+func (r *auctionItemRepository) FindStartingBetween(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.AuctionItem{}).
+		Joins("JOIN auction_schedules ON auction_schedules.item_id = auction_items.item_id").
+		Where("auction_items.status = ?", model.AuctionStatusPublished).
+		Where("auction_schedules.auction_start > ? AND auction_schedules.auction_start <= ?", from, to).
+		Order("auction_schedules.auction_start ASC").
+		Pluck("auction_items.item_id", &ids).Error
+	return ids, err
+}
+
 func (r *auctionItemRepository) FindByLotCode(lotCode string) (*model.AuctionItem, error) {
 	var item model.AuctionItem
 	err := r.db.
@@ -280,6 +408,16 @@ func (r *auctionItemRepository) FindPublished(filters AuctionItemFilters) ([]mod
 		searchTerm := "%" + filters.Search + "%"
 		query = query.Where("item_name ILIKE ? OR lot_code ILIKE ? OR description ILIKE ?", searchTerm, searchTerm, searchTerm)
 	}
+	if filters.Province != "" {
+		query = query.Where("organizer_id IN (?)",
+			r.db.Model(&model.Organizer{}).Select("organizer_id").Where("province ILIKE ?", filters.Province))
+	}
+	if filters.MinPrice != nil {
+		query = query.Where("starting_price >= ?", *filters.MinPrice)
+	}
+	if filters.MaxPrice != nil {
+		query = query.Where("starting_price <= ?", *filters.MaxPrice)
+	}
 
 	query.Count(&total)
 
@@ -314,6 +452,19 @@ func (r *auctionItemRepository) FindPublished(filters AuctionItemFilters) ([]mod
 	return items, total, err
 }
 
+// FindLive returns the published and running items with their schedules,
+// soonest ending first
+func (r *auctionItemRepository) FindLive() ([]model.AuctionItem, error) {
+	var items []model.AuctionItem
+	err := r.db.
+		Joins("JOIN auction_schedules ON auction_schedules.item_id = auction_items.item_id AND auction_schedules.deleted_at IS NULL").
+		Where("auction_items.status IN ?", []model.AuctionStatus{model.AuctionStatusPublished, model.AuctionStatusOngoing}).
+		Order("auction_schedules.auction_end ASC").
+		Preload("Schedule").
+		Find(&items).Error
+	return items, err
+}
+
 func (r *auctionItemRepository) Update(item *model.AuctionItem) error {
 	return r.db.Save(item).Error
 }
@@ -331,12 +482,56 @@ func (r *auctionItemRepository) UpdateBidInfo(id uint, highestBid float64, bidCo
 		}).Error
 }
 
+// CloseWithOutcome closes the item and records how it ended
+func (r *auctionItemRepository) CloseWithOutcome(id uint, outcome model.AuctionOutcome, reason *string, closedAt time.Time) error {
+	return r.db.Model(&model.AuctionItem{}).Where("item_id = ?", id).Updates(map[string]interface{}{
+		"status":         model.AuctionStatusClosed,
+		"outcome":        outcome,
+		"outcome_reason": reason,
+		"closed_at":      closedAt,
+	}).Error
+}
+
+// Cancel withdraws the item from auction before it closed
+func (r *auctionItemRepository) Cancel(id uint, reason string, cancelledAt time.Time) error {
+	return r.db.Model(&model.AuctionItem{}).Where("item_id = ?", id).Updates(map[string]interface{}{
+		"status":         model.AuctionStatusCancelled,
+		"outcome_reason": reason,
+		"closed_at":      cancelledAt,
+	}).Error
+}
+
+// FlagForReauction turns a sold lot unsold after the winner defaults
+func (r *auctionItemRepository) FlagForReauction(id uint, reason string) error {
+	return r.db.Model(&model.AuctionItem{}).Where("item_id = ?", id).Updates(map[string]interface{}{
+		"outcome":            model.AuctionOutcomeUnsold,
+		"outcome_reason":     reason,
+		"reauction_required": true,
+	}).Error
+}
+
+// RecordSecondChanceSale marks a flagged lot sold to its runner-up
+func (r *auctionItemRepository) RecordSecondChanceSale(id uint) error {
+	return r.db.Model(&model.AuctionItem{}).Where("item_id = ?", id).Updates(map[string]interface{}{
+		"outcome":            model.AuctionOutcomeSecondChance,
+		"outcome_reason":     nil,
+		"reauction_required": false,
+	}).Error
+}
+
 func (r *auctionItemRepository) IncrementViewCount(id uint) error {
 	return r.db.Model(&model.AuctionItem{}).
 		Where("item_id = ?", id).
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
+// AdjustWatcherCount moves the watcher count by delta, never below zero
+func (r *auctionItemRepository) AdjustWatcherCount(id uint, delta int) error {
+	return r.db.Model(&model.AuctionItem{}).
+		Where("item_id = ?", id).
+		UpdateColumn("watcher_count", gorm.Expr("GREATEST(watcher_count + ?, 0)", delta)).Error
+}
+
 func (r *auctionItemRepository) Delete(id uint) error {
 	return r.db.Delete(&model.AuctionItem{}, id).Error
 }
@@ -389,6 +584,7 @@ func (r *itemImageRepository) DeleteByItemID(itemID uint) error {
 // ========== SCHEDULE REPOSITORY ==========
 
 type AuctionScheduleRepository interface {
+	WithTx(tx *gorm.DB) AuctionScheduleRepository
 	Create(schedule *model.AuctionSchedule) error
 	FindByItemID(itemID uint) (*model.AuctionSchedule, error)
 	Update(schedule *model.AuctionSchedule) error
@@ -403,6 +599,10 @@ func NewAuctionScheduleRepository(db *gorm.DB) AuctionScheduleRepository {
 	return &auctionScheduleRepository{db: db}
 }
 
+func (r *auctionScheduleRepository) WithTx(tx *gorm.DB) AuctionScheduleRepository {
+	return &auctionScheduleRepository{db: tx}
+}
+
 func (r *auctionScheduleRepository) Create(schedule *model.AuctionSchedule) error {
 	return r.db.Create(schedule).Error
 }
@@ -421,18 +621,118 @@ func (r *auctionScheduleRepository) Delete(id uint) error {
 	return r.db.Delete(&model.AuctionSchedule{}, id).Error
 }
 
+// ========== SOFT CLOSE REPOSITORY ==========
+
+type SoftCloseRepository interface {
+	WithTx(tx *gorm.DB) SoftCloseRepository
+	CreateRule(rule *model.SoftCloseRule) error
+	UpdateRule(rule *model.SoftCloseRule) error
+	DeleteRule(id uint) error
+	FindRuleByID(id uint) (*model.SoftCloseRule, error)
+	FindRuleByItemID(itemID uint) (*model.SoftCloseRule, error)
+	FindRuleByOrganizerID(organizerID uint) (*model.SoftCloseRule, error)
+	FindAllRules() ([]model.SoftCloseRule, error)
+	FindEffectiveRule(itemID, organizerID uint) (*model.SoftCloseRule, error)
+	CreateExtension(extension *model.AuctionExtension) error
+	FindExtensionsByItemID(itemID uint) ([]model.AuctionExtension, error)
+}
+
+type softCloseRepository struct {
+	db *gorm.DB
+}
+
+func NewSoftCloseRepository(db *gorm.DB) SoftCloseRepository {
+	return &softCloseRepository{db: db}
+}
+
+func (r *softCloseRepository) WithTx(tx *gorm.DB) SoftCloseRepository {
+	return &softCloseRepository{db: tx}
+}
+
+func (r *softCloseRepository) CreateRule(rule *model.SoftCloseRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *softCloseRepository) UpdateRule(rule *model.SoftCloseRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *softCloseRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&model.SoftCloseRule{}, id).Error
+}
+
+func (r *softCloseRepository) FindRuleByID(id uint) (*model.SoftCloseRule, error) {
+	var rule model.SoftCloseRule
+	err := r.db.First(&rule, id).Error
+	return &rule, err
+}
+
+func (r *softCloseRepository) FindRuleByItemID(itemID uint) (*model.SoftCloseRule, error) {
+	var rule model.SoftCloseRule
+	err := r.db.Where("item_id = ?", itemID).First(&rule).Error
+	return &rule, err
+}
+
+func (r *softCloseRepository) FindRuleByOrganizerID(organizerID uint) (*model.SoftCloseRule, error) {
+	var rule model.SoftCloseRule
+	err := r.db.Where("organizer_id = ? AND item_id IS NULL", organizerID).First(&rule).Error
+	return &rule, err
+}
+
+func (r *softCloseRepository) FindAllRules() ([]model.SoftCloseRule, error) {
+	var rules []model.SoftCloseRule
+	err := r.db.Order("rule_id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindEffectiveRule returns the item rule, falling back to the organizer's
+// rule. An inactive item rule turns extensions off for the item rather than
+// deferring to the organizer, and is reported as gorm.ErrRecordNotFound.
+func (r *softCloseRepository) FindEffectiveRule(itemID, organizerID uint) (*model.SoftCloseRule, error) {
+	var rule model.SoftCloseRule
+	err := r.db.Where("item_id = ?", itemID).First(&rule).Error
+	if err == nil {
+		if !rule.IsActive {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &rule, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	err = r.db.Where("is_active = ? AND organizer_id = ? AND item_id IS NULL", true, organizerID).First(&rule).Error
+	return &rule, err
+}
+
+func (r *softCloseRepository) CreateExtension(extension *model.AuctionExtension) error {
+	return r.db.Create(extension).Error
+}
+
+func (r *softCloseRepository) FindExtensionsByItemID(itemID uint) ([]model.AuctionExtension, error) {
+	var extensions []model.AuctionExtension
+	err := r.db.Where("item_id = ?", itemID).Order("extension_number ASC").Find(&extensions).Error
+	return extensions, err
+}
+
 // ========== BID REPOSITORY ==========
 
 type BidRepository interface {
+	WithTx(tx *gorm.DB) BidRepository
 	Create(bid *model.Bid) error
 	FindByID(id uint) (*model.Bid, error)
 	FindByItemID(itemID uint) ([]model.Bid, error)
 	FindByUserID(userID string) ([]model.Bid, error)
 	FindHighestBid(itemID uint) (*model.Bid, error)
+	FindWinningBid(itemID uint) (*model.Bid, error)
 	FindByItemAndUser(itemID uint, userID string) ([]model.Bid, error)
+	FindValidByItemID(itemID uint) ([]model.Bid, error)
 	Update(bid *model.Bid) error
 	UpdateStatus(id uint, status model.BidStatus) error
 	MarkAllAsOutbid(itemID uint, exceptBidID uint) error
+	MarkAllAsLost(itemID uint, exceptBidID uint) error
+	MarkCancelled(id uint) error
+	CreateCancellation(cancellation *model.BidCancellation) error
+	FindCancellationsByItemID(itemID uint) ([]model.BidCancellation, error)
 }
 
 type bidRepository struct {
@@ -443,6 +743,10 @@ func NewBidRepository(db *gorm.DB) BidRepository {
 	return &bidRepository{db: db}
 }
 
+func (r *bidRepository) WithTx(tx *gorm.DB) BidRepository {
+	return &bidRepository{db: tx}
+}
+
 func (r *bidRepository) Create(bid *model.Bid) error {
 	return r.db.Create(bid).Error
 }
@@ -479,6 +783,15 @@ func (r *bidRepository) FindHighestBid(itemID uint) (*model.Bid, error) {
 	return &bid, err
 }
 
+// FindWinningBid returns the bid currently holding the lead on an item
+func (r *bidRepository) FindWinningBid(itemID uint) (*model.Bid, error) {
+	var bid model.Bid
+	err := r.db.Where("item_id = ? AND bid_status = ? AND is_highest = ?", itemID, model.BidStatusWinning, true).
+		Order("bid_id DESC").
+		First(&bid).Error
+	return &bid, err
+}
+
 func (r *bidRepository) FindByItemAndUser(itemID uint, userID string) ([]model.Bid, error) {
 	var bids []model.Bid
 	err := r.db.Where("item_id = ? AND user_id = ?", itemID, userID).
@@ -487,6 +800,15 @@ func (r *bidRepository) FindByItemAndUser(itemID uint, userID string) ([]model.B
 	return bids, err
 }
 
+// FindValidByItemID returns every non-cancelled bid on an item without relations
+func (r *bidRepository) FindValidByItemID(itemID uint) ([]model.Bid, error) {
+	var bids []model.Bid
+	err := r.db.Where("item_id = ? AND bid_status != ?", itemID, model.BidStatusCancelled).
+		Order("bid_id ASC").
+		Find(&bids).Error
+	return bids, err
+}
+
 func (r *bidRepository) Update(bid *model.Bid) error {
 	return r.db.Save(bid).Error
 }
@@ -497,9 +819,133 @@ func (r *bidRepository) UpdateStatus(id uint, status model.BidStatus) error {
 
 func (r *bidRepository) MarkAllAsOutbid(itemID uint, exceptBidID uint) error {
 	return r.db.Model(&model.Bid{}).
-		Where("item_id = ? AND bid_id != ? AND bid_status = ?", itemID, exceptBidID, model.BidStatusActive).
+		Where("item_id = ? AND bid_id != ? AND bid_status IN ?", itemID, exceptBidID, []model.BidStatus{model.BidStatusActive, model.BidStatusWinning}).
 		Updates(map[string]interface{}{
 			"bid_status": model.BidStatusOutbid,
 			"is_highest": false,
 		}).Error
 }
+
+// MarkAllAsLost settles every other non-cancelled bid on a closed item
+func (r *bidRepository) MarkAllAsLost(itemID uint, exceptBidID uint) error {
+	return r.db.Model(&model.Bid{}).
+		Where("item_id = ? AND bid_id != ? AND bid_status != ?", itemID, exceptBidID, model.BidStatusCancelled).
+		Updates(map[string]interface{}{
+			"bid_status": model.BidStatusLost,
+			"is_highest": false,
+		}).Error
+}
+
+func (r *bidRepository) MarkCancelled(id uint) error {
+	return r.db.Model(&model.Bid{}).
+		Where("bid_id = ?", id).
+		Updates(map[string]interface{}{
+			"bid_status": model.BidStatusCancelled,
+			"is_highest": false,
+		}).Error
+}
+
+func (r *bidRepository) CreateCancellation(cancellation *model.BidCancellation) error {
+	return r.db.Create(cancellation).Error
+}
+
+func (r *bidRepository) FindCancellationsByItemID(itemID uint) ([]model.BidCancellation, error) {
+	var cancellations []model.BidCancellation
+	err := r.db.Where("item_id = ?", itemID).
+		Order("created_at DESC").
+		Find(&cancellations).Error
+	return cancellations, err
+}
+
+// ========== PROXY BID REPOSITORY ==========
+
+type ProxyBidRepository interface {
+	WithTx(tx *gorm.DB) ProxyBidRepository
+	Create(proxy *model.ProxyBid) error
+	Update(proxy *model.ProxyBid) error
+	FindActiveByItem(itemID uint) ([]model.ProxyBid, error)
+	FindActiveByItemAndUser(itemID uint, userID string) (*model.ProxyBid, error)
+	FindActiveByUser(userID string) ([]model.ProxyBid, error)
+	UpdateStatus(id uint, status model.ProxyBidStatus) error
+}
+
+type proxyBidRepository struct {
+	db *gorm.DB
+}
+
+func NewProxyBidRepository(db *gorm.DB) ProxyBidRepository {
+	return &proxyBidRepository{db: db}
+}
+
+func (r *proxyBidRepository) WithTx(tx *gorm.DB) ProxyBidRepository {
+	return &proxyBidRepository{db: tx}
+}
+
+func (r *proxyBidRepository) Create(proxy *model.ProxyBid) error {
+	return r.db.Create(proxy).Error
+}
+
+func (r *proxyBidRepository) Update(proxy *model.ProxyBid) error {
+	return r.db.Save(proxy).Error
+}
+
+// FindActiveByItem returns active proxies strongest first: highest maximum,
+// then the earliest placed one.
+func (r *proxyBidRepository) FindActiveByItem(itemID uint) ([]model.ProxyBid, error) {
+	var proxies []model.ProxyBid
+	err := r.db.Where("item_id = ? AND status = ?", itemID, model.ProxyBidStatusActive).
+		Order("max_amount DESC").
+		Order("placed_at ASC").
+		Find(&proxies).Error
+	return proxies, err
+}
+
+func (r *proxyBidRepository) FindActiveByItemAndUser(itemID uint, userID string) (*model.ProxyBid, error) {
+	var proxy model.ProxyBid
+	err := r.db.Where("item_id = ? AND user_id = ? AND status = ?", itemID, userID, model.ProxyBidStatusActive).
+		First(&proxy).Error
+	return &proxy, err
+}
+
+func (r *proxyBidRepository) FindActiveByUser(userID string) ([]model.ProxyBid, error) {
+	var proxies []model.ProxyBid
+	err := r.db.Where("user_id = ? AND status = ?", userID, model.ProxyBidStatusActive).
+		Preload("Item").
+		Order("placed_at DESC").
+		Find(&proxies).Error
+	return proxies, err
+}
+
+func (r *proxyBidRepository) UpdateStatus(id uint, status model.ProxyBidStatus) error {
+	return r.db.Model(&model.ProxyBid{}).Where("proxy_bid_id = ?", id).Update("status", status).Error
+}
+
+// ========== SEALED BID REPOSITORY ==========
+
+type SealedBidRepository interface {
+	WithTx(tx *gorm.DB) SealedBidRepository
+	CreateReveal(reveal *model.SealedBidReveal) error
+	FindRevealByItemID(itemID uint) (*model.SealedBidReveal, error)
+}
+
+type sealedBidRepository struct {
+	db *gorm.DB
+}
+
+func NewSealedBidRepository(db *gorm.DB) SealedBidRepository {
+	return &sealedBidRepository{db: db}
+}
+
+func (r *sealedBidRepository) WithTx(tx *gorm.DB) SealedBidRepository {
+	return &sealedBidRepository{db: tx}
+}
+
+func (r *sealedBidRepository) CreateReveal(reveal *model.SealedBidReveal) error {
+	return r.db.Create(reveal).Error
+}
+
+func (r *sealedBidRepository) FindRevealByItemID(itemID uint) (*model.SealedBidReveal, error) {
+	var reveal model.SealedBidReveal
+	err := r.db.Where("item_id = ?", itemID).First(&reveal).Error
+	return &reveal, err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DocumentRepository interface {
+	WithTx(tx *gorm.DB) DocumentRepository
+	Create(document *model.Document) error
+	FindLatest(docType model.DocumentType, itemID uint) (*model.Document, error)
+	FindByItem(docType model.DocumentType, itemID uint) ([]model.Document, error)
+	FindByID(id uint) (*model.Document, error)
+	FindByIDForUpdateSkipLocked(id uint) (*model.Document, error)
+	FindBySourceKey(sourceKey string) (*model.Document, error)
+	FindByOwner(ownerID string) ([]model.Document, error)
+	FindUnemailed(limit int) ([]model.Document, error)
+	MarkEmailed(id uint, at time.Time) error
+	NextSequence(scope string) (int, error)
+}
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+func (r *documentRepository) WithTx(tx *gorm.DB) DocumentRepository {
+	return &documentRepository{db: tx}
+}
+
+func (r *documentRepository) Create(document *model.Document) error {
+	return r.db.Create(document).Error
+}
+
+// FindLatest returns the current revision of the item's document
+func (r *documentRepository) FindLatest(docType model.DocumentType, itemID uint) (*model.Document, error) {
+	var document model.Document
+	err := r.db.Where("document_type = ? AND item_id = ?", docType, itemID).
+		Order("revision DESC").
+		First(&document).Error
+	return &document, err
+}
+
+func (r *documentRepository) FindByItem(docType model.DocumentType, itemID uint) ([]model.Document, error) {
+	var documents []model.Document
+	err := r.db.Where("document_type = ? AND item_id = ?", docType, itemID).
+		Order("revision ASC").
+		Find(&documents).Error
+	return documents, err
+}
+
+func (r *documentRepository) FindByID(id uint) (*model.Document, error) {
+	var document model.Document
+	err := r.db.First(&document, id).Error
+	return &document, err
+}
+
+// FindByIDForUpdateSkipLocked locks the document for a background job; a
+// document another replica holds yields ErrRecordNotFound
+func (r *documentRepository) FindByIDForUpdateSkipLocked(id uint) (*model.Document, error) {
+	var document model.Document
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).First(&document, id).Error
+	return &document, err
+}
+
+func (r *documentRepository) FindBySourceKey(sourceKey string) (*model.Document, error) {
+	var document model.Document
+	err := r.db.Where("source_key = ?", sourceKey).First(&document).Error
+	return &document, err
+}
+
+func (r *documentRepository) FindByOwner(ownerID string) ([]model.Document, error) {
+	var documents []model.Document
+	err := r.db.Where("owner_id = ?", ownerID).Order("issued_at DESC").Find(&documents).Error
+	return documents, err
+}
+
+// FindUnemailed lists owned documents whose copy has not been mailed yet
+func (r *documentRepository) FindUnemailed(limit int) ([]model.Document, error) {
+	var documents []model.Document
+	err := r.db.Where("owner_id IS NOT NULL AND emailed_at IS NULL").
+		Order("document_id ASC").
+		Limit(limit).
+		Find(&documents).Error
+	return documents, err
+}
+
+func (r *documentRepository) MarkEmailed(id uint, at time.Time) error {
+	return r.db.Model(&model.Document{}).Where("document_id = ?", id).Update("emailed_at", at).Error
+}
+
+// NextSequence increments the scope's counter under a row lock and returns
+// the new value. It must run inside a transaction so a rolled-back document
+// gives its number back.
+func (r *documentRepository) NextSequence(scope string) (int, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.DocumentSequence{Scope: scope}).Error; err != nil {
+		return 0, err
+	}
+
+	var sequence model.DocumentSequence
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ?", scope).
+		First(&sequence).Error; err != nil {
+		return 0, err
+	}
+
+	sequence.LastValue++
+	if err := r.db.Save(&sequence).Error; err != nil {
+		return 0, err
+	}
+	return sequence.LastValue, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type FeeRuleRepository interface {
+	WithTx(tx *gorm.DB) FeeRuleRepository
+	Create(rule *model.FeeRule) error
+	Update(rule *model.FeeRule) error
+	Delete(id uint) error
+	FindByID(id uint) (*model.FeeRule, error)
+	FindAll() ([]model.FeeRule, error)
+	FindActive() ([]model.FeeRule, error)
+	Count() (int64, error)
+}
+
+type feeRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewFeeRuleRepository(db *gorm.DB) FeeRuleRepository {
+	return &feeRuleRepository{db: db}
+}
+
+func (r *feeRuleRepository) WithTx(tx *gorm.DB) FeeRuleRepository {
+	return &feeRuleRepository{db: tx}
+}
+
+func (r *feeRuleRepository) Create(rule *model.FeeRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *feeRuleRepository) Update(rule *model.FeeRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *feeRuleRepository) Delete(id uint) error {
+	return r.db.Delete(&model.FeeRule{}, id).Error
+}
+
+func (r *feeRuleRepository) FindByID(id uint) (*model.FeeRule, error) {
+	var rule model.FeeRule
+	err := r.db.First(&rule, id).Error
+	return &rule, err
+}
+
+func (r *feeRuleRepository) FindAll() ([]model.FeeRule, error) {
+	var rules []model.FeeRule
+	err := r.db.Order("code ASC, rule_id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *feeRuleRepository) FindActive() ([]model.FeeRule, error) {
+	var rules []model.FeeRule
+	err := r.db.Where("is_active = ?", true).Order("code ASC, rule_id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Count includes deleted rules, so a schedule the admin emptied on purpose
+// is not seeded again
+func (r *feeRuleRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.FeeRule{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type FundsHoldRepository interface {
+	WithTx(tx *gorm.DB) FundsHoldRepository
+	Create(hold *model.FundsHold) error
+	Update(hold *model.FundsHold) error
+	FindActive(userID string, itemID uint, kind model.HoldKind) (*model.FundsHold, error)
+	FindActiveByItem(itemID uint, kind model.HoldKind) ([]model.FundsHold, error)
+}
+
+type fundsHoldRepository struct {
+	db *gorm.DB
+}
+
+func NewFundsHoldRepository(db *gorm.DB) FundsHoldRepository {
+	return &fundsHoldRepository{db: db}
+}
+
+func (r *fundsHoldRepository) WithTx(tx *gorm.DB) FundsHoldRepository {
+	return &fundsHoldRepository{db: tx}
+}
+
+func (r *fundsHoldRepository) Create(hold *model.FundsHold) error {
+	return r.db.Create(hold).Error
+}
+
+func (r *fundsHoldRepository) Update(hold *model.FundsHold) error {
+	return r.db.Omit("Item").Save(hold).Error
+}
+
+func (r *fundsHoldRepository) FindActive(userID string, itemID uint, kind model.HoldKind) (*model.FundsHold, error) {
+	var hold model.FundsHold
+	err := r.db.Where("user_id = ? AND item_id = ? AND kind = ? AND status = ?", userID, itemID, kind, model.HoldStatusActive).
+		First(&hold).Error
+	return &hold, err
+}
+
+func (r *fundsHoldRepository) FindActiveByItem(itemID uint, kind model.HoldKind) ([]model.FundsHold, error) {
+	var holds []model.FundsHold
+	err := r.db.Where("item_id = ? AND kind = ? AND status = ?", itemID, kind, model.HoldStatusActive).
+		Order("user_id ASC").
+		Find(&holds).Error
+	return holds, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type IncrementTableRepository interface {
+	WithTx(tx *gorm.DB) IncrementTableRepository
+	Create(table *model.IncrementTable) error
+	Update(table *model.IncrementTable) error
+	ReplaceBands(tableID uint, bands []model.IncrementBand) error
+	Delete(id uint) error
+	FindByID(id uint) (*model.IncrementTable, error)
+	FindByTarget(organizerID, categoryID, itemID *uint) (*model.IncrementTable, error)
+	FindAll() ([]model.IncrementTable, error)
+	FindActiveFor(itemID uint, categoryIDs []uint, organizerID uint) ([]model.IncrementTable, error)
+}
+
+type incrementTableRepository struct {
+	db *gorm.DB
+}
+
+func NewIncrementTableRepository(db *gorm.DB) IncrementTableRepository {
+	return &incrementTableRepository{db: db}
+}
+
+func (r *incrementTableRepository) WithTx(tx *gorm.DB) IncrementTableRepository {
+	return &incrementTableRepository{db: tx}
+}
+
+func (r *incrementTableRepository) Create(table *model.IncrementTable) error {
+	return r.db.Create(table).Error
+}
+
+func (r *incrementTableRepository) Update(table *model.IncrementTable) error {
+	return r.db.Omit("Bands").Save(table).Error
+}
+
+// ReplaceBands swaps the table's bands for the given ones
+func (r *incrementTableRepository) ReplaceBands(tableID uint, bands []model.IncrementBand) error {
+	if err := r.db.Where("table_id = ?", tableID).Delete(&model.IncrementBand{}).Error; err != nil {
+		return err
+	}
+	for i := range bands {
+		bands[i].ID = 0
+		bands[i].TableID = tableID
+	}
+	if len(bands) == 0 {
+		return nil
+	}
+	return r.db.Create(&bands).Error
+}
+
+func (r *incrementTableRepository) Delete(id uint) error {
+	return r.db.Delete(&model.IncrementTable{}, id).Error
+}
+
+func (r *incrementTableRepository) FindByID(id uint) (*model.IncrementTable, error) {
+	var table model.IncrementTable
+	err := r.db.Preload("Bands", orderBands).First(&table, id).Error
+	return &table, err
+}
+
+// FindByTarget returns the table assigned to exactly the given target
+func (r *incrementTableRepository) FindByTarget(organizerID, categoryID, itemID *uint) (*model.IncrementTable, error) {
+	query := r.db.Preload("Bands", orderBands)
+	query = whereTarget(query, "organizer_id", organizerID)
+	query = whereTarget(query, "category_id", categoryID)
+	query = whereTarget(query, "item_id", itemID)
+
+	var table model.IncrementTable
+	err := query.First(&table).Error
+	return &table, err
+}
+
+func (r *incrementTableRepository) FindAll() ([]model.IncrementTable, error) {
+	var tables []model.IncrementTable
+	err := r.db.Preload("Bands", orderBands).Order("table_id ASC").Find(&tables).Error
+	return tables, err
+}
+
+// FindActiveFor returns every active table that could apply to an item; the
+// caller picks the most specific
+func (r *incrementTableRepository) FindActiveFor(itemID uint, categoryIDs []uint, organizerID uint) ([]model.IncrementTable, error) {
+	var tables []model.IncrementTable
+	err := r.db.
+		Preload("Bands", orderBands).
+		Where("is_active = ?", true).
+		Where("item_id = ? OR category_id IN ? OR organizer_id = ?", itemID, categoryIDs, organizerID).
+		Find(&tables).Error
+	return tables, err
+}
+
+func orderBands(db *gorm.DB) *gorm.DB {
+	return db.Order("min_price ASC")
+}
+
+func whereTarget(query *gorm.DB, column string, id *uint) *gorm.DB {
+	if id == nil {
+		return query.Where(column + " IS NULL")
+	}
+	return query.Where(column+" = ?", *id)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountBalance is the credit-minus-debit total of one account
+type AccountBalance struct {
+	AccountID uint
+	Code      string
+	Type      model.LedgerAccountType
+	UserID    *string
+	Debits    decimal.Decimal
+	Credits   decimal.Decimal
+}
+
+// LedgerRepository only appends; there is deliberately no update or delete
+// for entries and lines.
+type LedgerRepository interface {
+	WithTx(tx *gorm.DB) LedgerRepository
+	EnsureAccount(account *model.LedgerAccount) (*model.LedgerAccount, error)
+	FindAccountsByUser(userID string) ([]model.LedgerAccount, error)
+	CreateEntry(entry *model.JournalEntry) error
+	FindEntryByReference(reference string) (*model.JournalEntry, error)
+	CountEntriesByUser(userID string) (int64, error)
+	FindLinesByAccounts(accountIDs []uint, page, limit int) ([]model.JournalLine, int64, error)
+
+	// Balances and consistency
+	AccountBalances(accountIDs []uint) ([]AccountBalance, error)
+	UserAccountBalances() ([]AccountBalance, error)
+	Totals() (debits, credits decimal.Decimal, err error)
+	FindUnbalancedEntryIDs() ([]uint, error)
+}
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) WithTx(tx *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: tx}
+}
+
+// EnsureAccount returns the account with the given code, creating it first if
+// needed. Concurrent creators are resolved by the unique code.
+func (r *ledgerRepository) EnsureAccount(account *model.LedgerAccount) (*model.LedgerAccount, error) {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(account).Error; err != nil {
+		return nil, err
+	}
+
+	var existing model.LedgerAccount
+	err := r.db.Where("code = ?", account.Code).First(&existing).Error
+	return &existing, err
+}
+
+func (r *ledgerRepository) FindAccountsByUser(userID string) ([]model.LedgerAccount, error) {
+	var accounts []model.LedgerAccount
+	err := r.db.Where("user_id = ?", userID).Order("account_id ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *ledgerRepository) CreateEntry(entry *model.JournalEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *ledgerRepository) FindEntryByReference(reference string) (*model.JournalEntry, error) {
+	var entry model.JournalEntry
+	err := r.db.Preload("Lines").Where("reference = ?", reference).First(&entry).Error
+	return &entry, err
+}
+
+func (r *ledgerRepository) CountEntriesByUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.JournalLine{}).
+		Joins("JOIN ledger_accounts ON ledger_accounts.account_id = journal_lines.account_id").
+		Where("ledger_accounts.user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindLinesByAccounts pages through the lines of the given accounts, newest first
+func (r *ledgerRepository) FindLinesByAccounts(accountIDs []uint, page, limit int) ([]model.JournalLine, int64, error) {
+	var lines []model.JournalLine
+	var total int64
+
+	query := r.db.Model(&model.JournalLine{}).Where("account_id IN ?", accountIDs)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Entry").
+		Preload("Account").
+		Order("line_id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&lines).Error
+	return lines, total, err
+}
+
+func (r *ledgerRepository) AccountBalances(accountIDs []uint) ([]AccountBalance, error) {
+	var balances []AccountBalance
+	err := r.balanceQuery().
+		Where("ledger_accounts.account_id IN ?", accountIDs).
+		Scan(&balances).Error
+	return balances, err
+}
+
+// UserAccountBalances returns the balance of every user wallet account
+func (r *ledgerRepository) UserAccountBalances() ([]AccountBalance, error) {
+	var balances []AccountBalance
+	err := r.balanceQuery().
+		Where("ledger_accounts.user_id IS NOT NULL").
+		Scan(&balances).Error
+	return balances, err
+}
+
+func (r *ledgerRepository) Totals() (decimal.Decimal, decimal.Decimal, error) {
+	var totals struct {
+		Debits  decimal.Decimal
+		Credits decimal.Decimal
+	}
+	err := r.db.Model(&model.JournalLine{}).
+		Select("COALESCE(SUM(debit), 0) AS debits, COALESCE(SUM(credit), 0) AS credits").
+		Scan(&totals).Error
+	return totals.Debits, totals.Credits, err
+}
+
+func (r *ledgerRepository) FindUnbalancedEntryIDs() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.JournalLine{}).
+		Group("entry_id").
+		Having("SUM(debit) <> SUM(credit)").
+		Pluck("entry_id", &ids).Error
+	return ids, err
+}
+
+func (r *ledgerRepository) balanceQuery() *gorm.DB {
+	return r.db.Model(&model.LedgerAccount{}).
+		Select("ledger_accounts.account_id, ledger_accounts.code, ledger_accounts.type, ledger_accounts.user_id, " +
+			"COALESCE(SUM(journal_lines.debit), 0) AS debits, COALESCE(SUM(journal_lines.credit), 0) AS credits").
+		Joins("LEFT JOIN journal_lines ON journal_lines.account_id = ledger_accounts.account_id").
+		Group("ledger_accounts.account_id")
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	WithTx(tx *gorm.DB) NotificationRepository
+	CreateOnce(notification *model.Notification) (bool, error)
+	ExistsSince(userID string, itemID uint, notificationType model.NotificationType, since time.Time) (bool, error)
+	FindByIDForUpdateSkipLocked(id uint) (*model.Notification, error)
+	FindUnemailed(limit int) ([]model.Notification, error)
+	MarkEmailed(id uint, at time.Time) error
+	FindByUser(userID string, page, limit int) ([]model.Notification, int64, error)
+	CountUnread(userID string) (int64, error)
+	MarkRead(id uint, userID string, at time.Time) (*model.Notification, error)
+	MarkAllRead(userID string, at time.Time) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) WithTx(tx *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: tx}
+}
+
+// CreateOnce stores the notification unless one with the same dedup key
+// exists, and reports whether it was stored
+func (r *notificationRepository) CreateOnce(notification *model.Notification) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoNothing: true,
+	}).Create(notification)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ExistsSince reports whether the user was sent this type of notification on
+// the item at or after since
+func (r *notificationRepository) ExistsSince(userID string, itemID uint, notificationType model.NotificationType, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND item_id = ? AND type = ? AND created_at >= ?", userID, itemID, notificationType, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *notificationRepository) FindByIDForUpdateSkipLocked(id uint) (*model.Notification, error) {
+	var notification model.Notification
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).First(&notification, id).Error
+	return &notification, err
+}
+
+func (r *notificationRepository) FindUnemailed(limit int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("emailed_at IS NULL").
+		Order("notification_id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) MarkEmailed(id uint, at time.Time) error {
+	return r.db.Model(&model.Notification{}).Where("notification_id = ?", id).Update("emailed_at", at).Error
+}
+
+// FindByUser returns a page of the user's notifications, newest first
+func (r *notificationRepository) FindByUser(userID string, page, limit int) ([]model.Notification, int64, error) {
+	var notifications []model.Notification
+	var total int64
+
+	query := r.db.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Order("notification_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications read. Reading it again keeps
+// the first read time.
+func (r *notificationRepository) MarkRead(id uint, userID string, at time.Time) (*model.Notification, error) {
+	var notification model.Notification
+	if err := r.db.Where("notification_id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return nil, err
+	}
+	if notification.ReadAt != nil {
+		return &notification, nil
+	}
+	if err := r.db.Model(&model.Notification{}).
+		Where("notification_id = ? AND read_at IS NULL", id).
+		Update("read_at", at).Error; err != nil {
+		return nil, err
+	}
+	notification.ReadAt = &at
+	return &notification, nil
+}
+
+// MarkAllRead marks every unread notification of the user read and returns
+// how many there were
+func (r *notificationRepository) MarkAllRead(userID string, at time.Time) (int64, error) {
+	result := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
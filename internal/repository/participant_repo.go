@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ParticipantRepository interface {
+	WithTx(tx *gorm.DB) ParticipantRepository
+	Create(participant *model.AuctionParticipant) error
+	Update(participant *model.AuctionParticipant) error
+	FindByItemAndUser(itemID uint, userID string) (*model.AuctionParticipant, error)
+	FindByItemAndUserForUpdate(itemID uint, userID string) (*model.AuctionParticipant, error)
+	FindByItemID(itemID uint) ([]model.AuctionParticipant, error)
+	FindByUserID(userID string) ([]model.AuctionParticipant, error)
+	FindPaidByItemID(itemID uint) ([]model.AuctionParticipant, error)
+	CountNumbered(itemID uint) (int64, error)
+}
+
+type participantRepository struct {
+	db *gorm.DB
+}
+
+func NewParticipantRepository(db *gorm.DB) ParticipantRepository {
+	return &participantRepository{db: db}
+}
+
+func (r *participantRepository) WithTx(tx *gorm.DB) ParticipantRepository {
+	return &participantRepository{db: tx}
+}
+
+func (r *participantRepository) Create(participant *model.AuctionParticipant) error {
+	return r.db.Create(participant).Error
+}
+
+func (r *participantRepository) Update(participant *model.AuctionParticipant) error {
+	return r.db.Omit("Item", "User").Save(participant).Error
+}
+
+func (r *participantRepository) FindByItemAndUser(itemID uint, userID string) (*model.AuctionParticipant, error) {
+	var participant model.AuctionParticipant
+	err := r.db.Where("item_id = ? AND user_id = ?", itemID, userID).First(&participant).Error
+	return &participant, err
+}
+
+// FindByItemAndUserForUpdate locks the registration until the surrounding transaction ends
+func (r *participantRepository) FindByItemAndUserForUpdate(itemID uint, userID string) (*model.AuctionParticipant, error) {
+	var participant model.AuctionParticipant
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND user_id = ?", itemID, userID).
+		First(&participant).Error
+	return &participant, err
+}
+
+func (r *participantRepository) FindByItemID(itemID uint) ([]model.AuctionParticipant, error) {
+	var participants []model.AuctionParticipant
+	err := r.db.Preload("User").
+		Where("item_id = ?", itemID).
+		Order("registered_at ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+func (r *participantRepository) FindByUserID(userID string) ([]model.AuctionParticipant, error) {
+	var participants []model.AuctionParticipant
+	err := r.db.Preload("Item").
+		Where("user_id = ?", userID).
+		Order("registered_at DESC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// FindPaidByItemID returns participants whose deposit is still held
+func (r *participantRepository) FindPaidByItemID(itemID uint) ([]model.AuctionParticipant, error) {
+	var participants []model.AuctionParticipant
+	err := r.db.Where("item_id = ? AND status = ?", itemID, model.ParticipantStatusDepositPaid).
+		Order("user_id ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// CountNumbered counts participants of the item that already have a NUP
+func (r *participantRepository) CountNumbered(itemID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.AuctionParticipant{}).
+		Where("item_id = ? AND nup IS NOT NULL", itemID).
+		Count(&count).Error
+	return count, err
+}
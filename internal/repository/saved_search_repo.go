@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SavedSearchRepository interface {
+	WithTx(tx *gorm.DB) SavedSearchRepository
+	Create(search *model.SavedSearch) error
+	Update(search *model.SavedSearch) error
+	Delete(id uint, userID string) (bool, error)
+	FindByIDAndUser(id uint, userID string) (*model.SavedSearch, error)
+	FindByUser(userID string) ([]model.SavedSearch, error)
+	FindByUnsubscribeToken(token string) (*model.SavedSearch, error)
+	FindActiveByCategory(categoryID uint) ([]model.SavedSearch, error)
+	FindDigestsDue(before time.Time, limit int) ([]model.SavedSearch, error)
+	FindByIDForUpdateSkipLocked(id uint) (*model.SavedSearch, error)
+
+	CreateMatchOnce(match *model.SavedSearchMatch) (bool, error)
+	FindUndigestedMatches(searchID uint) ([]model.SavedSearchMatch, error)
+	MarkMatchesDigested(ids []uint, at time.Time) error
+}
+
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedSearchRepository(db *gorm.DB) SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) WithTx(tx *gorm.DB) SavedSearchRepository {
+	return &savedSearchRepository{db: tx}
+}
+
+func (r *savedSearchRepository) Create(search *model.SavedSearch) error {
+	return r.db.Create(search).Error
+}
+
+func (r *savedSearchRepository) Update(search *model.SavedSearch) error {
+	return r.db.Save(search).Error
+}
+
+// Delete removes one of the user's saved searches with its matches and
+// reports whether there was one
+func (r *savedSearchRepository) Delete(id uint, userID string) (bool, error) {
+	result := r.db.Where("saved_search_id = ? AND user_id = ?", id, userID).Delete(&model.SavedSearch{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, r.db.Where("saved_search_id = ?", id).Delete(&model.SavedSearchMatch{}).Error
+}
+
+func (r *savedSearchRepository) FindByIDAndUser(id uint, userID string) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	err := r.db.Where("saved_search_id = ? AND user_id = ?", id, userID).First(&search).Error
+	return &search, err
+}
+
+func (r *savedSearchRepository) FindByUser(userID string) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&searches).Error
+	return searches, err
+}
+
+func (r *savedSearchRepository) FindByUnsubscribeToken(token string) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	err := r.db.Where("unsubscribe_token = ?", token).First(&search).Error
+	return &search, err
+}
+
+// FindActiveByCategory returns the active searches that could match a lot of
+// the category: those on the category and those on any category
+func (r *savedSearchRepository) FindActiveByCategory(categoryID uint) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("active = ?", true).
+		Where("category_id IS NULL OR category_id = ?", categoryID).
+		Order("saved_search_id ASC").
+		Find(&searches).Error
+	return searches, err
+}
+
+// FindDigestsDue returns active digest searches last sent before the cutoff,
+// or never sent, that have matches waiting
+func (r *savedSearchRepository) FindDigestsDue(before time.Time, limit int) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("active = ? AND delivery = ?", true, model.SavedSearchDeliveryDailyDigest).
+		Where("last_digest_at IS NULL OR last_digest_at <= ?", before).
+		Where("EXISTS (SELECT 1 FROM saved_search_matches m WHERE m.saved_search_id = saved_searches.saved_search_id AND m.digested_at IS NULL)").
+		Order("saved_search_id ASC").
+		Limit(limit).
+		Find(&searches).Error
+	return searches, err
+}
+
+func (r *savedSearchRepository) FindByIDForUpdateSkipLocked(id uint) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).First(&search, id).Error
+	return &search, err
+}
+
+// CreateMatchOnce records the match unless the lot already matched the
+// search, and reports whether it was recorded
+func (r *savedSearchRepository) CreateMatchOnce(match *model.SavedSearchMatch) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "saved_search_id"}, {Name: "item_id"}},
+		DoNothing: true,
+	}).Omit("Item").Create(match)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *savedSearchRepository) FindUndigestedMatches(searchID uint) ([]model.SavedSearchMatch, error) {
+	var matches []model.SavedSearchMatch
+	err := r.db.Preload("Item").
+		Preload("Item.Schedule").
+		Where("saved_search_id = ? AND digested_at IS NULL", searchID).
+		Order("match_id ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+func (r *savedSearchRepository) MarkMatchesDigested(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.SavedSearchMatch{}).Where("match_id IN ?", ids).Update("digested_at", at).Error
+}
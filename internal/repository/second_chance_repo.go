@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SecondChanceRepository interface {
+	WithTx(tx *gorm.DB) SecondChanceRepository
+	Create(offer *model.SecondChanceOffer) error
+	Update(offer *model.SecondChanceOffer) error
+	FindByIDForUpdate(id uint) (*model.SecondChanceOffer, error)
+	FindByItemID(itemID uint) ([]model.SecondChanceOffer, error)
+	FindByUserID(userID string) ([]model.SecondChanceOffer, error)
+	ExpirePending(now time.Time) (int64, error)
+}
+
+type secondChanceRepository struct {
+	db *gorm.DB
+}
+
+func NewSecondChanceRepository(db *gorm.DB) SecondChanceRepository {
+	return &secondChanceRepository{db: db}
+}
+
+func (r *secondChanceRepository) WithTx(tx *gorm.DB) SecondChanceRepository {
+	return &secondChanceRepository{db: tx}
+}
+
+func (r *secondChanceRepository) Create(offer *model.SecondChanceOffer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *secondChanceRepository) Update(offer *model.SecondChanceOffer) error {
+	return r.db.Omit("Item").Save(offer).Error
+}
+
+// FindByIDForUpdate locks the offer until the surrounding transaction ends
+func (r *secondChanceRepository) FindByIDForUpdate(id uint) (*model.SecondChanceOffer, error) {
+	var offer model.SecondChanceOffer
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, id).Error
+	return &offer, err
+}
+
+func (r *secondChanceRepository) FindByItemID(itemID uint) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	err := r.db.Where("item_id = ?", itemID).Order("offer_id ASC").Find(&offers).Error
+	return offers, err
+}
+
+func (r *secondChanceRepository) FindByUserID(userID string) ([]model.SecondChanceOffer, error) {
+	var offers []model.SecondChanceOffer
+	err := r.db.Preload("Item").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&offers).Error
+	return offers, err
+}
+
+// ExpirePending marks every pending offer past its deadline as expired
+func (r *secondChanceRepository) ExpirePending(now time.Time) (int64, error) {
+	result := r.db.Model(&model.SecondChanceOffer{}).
+		Where("status = ? AND expires_at < ?", model.SecondChanceStatusPending, now).
+		Update("status", model.SecondChanceStatusExpired)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettlementRepository interface {
+	WithTx(tx *gorm.DB) SettlementRepository
+	Create(settlement *model.Settlement) error
+	Update(settlement *model.Settlement) error
+	FindByItemID(itemID uint) (*model.Settlement, error)
+	FindByItemIDForUpdate(itemID uint) (*model.Settlement, error)
+	FindByItemIDForUpdateSkipLocked(itemID uint) (*model.Settlement, error)
+	FindByWinnerID(userID string) ([]model.Settlement, error)
+	FindAllByItemID(itemID uint) ([]model.Settlement, error)
+	FindOverdueItemIDs(now time.Time, limit int) ([]uint, error)
+	FindCharges(settlementID uint) ([]model.SettlementCharge, error)
+}
+
+type settlementRepository struct {
+	db *gorm.DB
+}
+
+func NewSettlementRepository(db *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+func (r *settlementRepository) WithTx(tx *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: tx}
+}
+
+func (r *settlementRepository) Create(settlement *model.Settlement) error {
+	return r.db.Create(settlement).Error
+}
+
+func (r *settlementRepository) Update(settlement *model.Settlement) error {
+	return r.db.Omit("Item", "Charges").Save(settlement).Error
+}
+
+// FindByItemID returns the item's current settlement, the latest one. The
+// locking variants below do the same.
+func (r *settlementRepository) FindByItemID(itemID uint) (*model.Settlement, error) {
+	var settlement model.Settlement
+	err := r.db.Preload("Charges").
+		Where("item_id = ?", itemID).
+		Order("settlement_id DESC").
+		First(&settlement).Error
+	return &settlement, err
+}
+
+// FindByItemIDForUpdate locks the settlement until the surrounding transaction ends
+func (r *settlementRepository) FindByItemIDForUpdate(itemID uint) (*model.Settlement, error) {
+	var settlement model.Settlement
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ?", itemID).
+		Order("settlement_id DESC").
+		First(&settlement).Error
+	return &settlement, err
+}
+
+// FindByItemIDForUpdateSkipLocked is FindByItemIDForUpdate for background
+// jobs: a settlement another transaction holds is skipped, not waited for
+func (r *settlementRepository) FindByItemIDForUpdateSkipLocked(itemID uint) (*model.Settlement, error) {
+	var settlement model.Settlement
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("item_id = ?", itemID).
+		Order("settlement_id DESC").
+		First(&settlement).Error
+	return &settlement, err
+}
+
+func (r *settlementRepository) FindByWinnerID(userID string) ([]model.Settlement, error) {
+	var settlements []model.Settlement
+	err := r.db.Preload("Item").
+		Preload("Charges").
+		Where("winner_id = ?", userID).
+		Order("created_at DESC").
+		Find(&settlements).Error
+	return settlements, err
+}
+
+func (r *settlementRepository) FindAllByItemID(itemID uint) ([]model.Settlement, error) {
+	var settlements []model.Settlement
+	err := r.db.Where("item_id = ?", itemID).Order("settlement_id ASC").Find(&settlements).Error
+	return settlements, err
+}
+
+// FindOverdueItemIDs lists pending settlements whose payment deadline has passed
+func (r *settlementRepository) FindOverdueItemIDs(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Settlement{}).
+		Where("status = ? AND payment_deadline < ?", model.SettlementStatusPending, now).
+		Order("payment_deadline ASC").
+		Limit(limit).
+		Pluck("item_id", &ids).Error
+	return ids, err
+}
+
+func (r *settlementRepository) FindCharges(settlementID uint) ([]model.SettlementCharge, error) {
+	var charges []model.SettlementCharge
+	err := r.db.Where("settlement_id = ?", settlementID).Order("charge_id ASC").Find(&charges).Error
+	return charges, err
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenderRepository interface {
+	WithTx(tx *gorm.DB) TenderRepository
+	Create(tender *model.Tender) error
+	Update(tender *model.Tender) error
+	FindByItemID(itemID uint) (*model.Tender, error)
+	FindByItemIDForUpdate(itemID uint) (*model.Tender, error)
+
+	// Committee
+	FindCommitteeMember(tenderID uint, userID string) (*model.TenderCommitteeMember, error)
+	UpdateCommitteeMember(member *model.TenderCommitteeMember) error
+	ClearSignOffs(tenderID uint) error
+
+	// Offers
+	CreateOffer(offer *model.TenderOffer) error
+	UpdateOffer(offer *model.TenderOffer) error
+	FindOfferByID(id uint) (*model.TenderOffer, error)
+	FindOfferByUser(tenderID uint, userID string) (*model.TenderOffer, error)
+	FindSubmittedOffers(tenderID uint) ([]model.TenderOffer, error)
+	ReplaceDocuments(offerID uint, documents []model.TenderDocument) error
+
+	// Scores
+	SaveScore(score *model.TenderScore) error
+	FindScoresByTender(tenderID uint) ([]model.TenderScore, error)
+}
+
+type tenderRepository struct {
+	db *gorm.DB
+}
+
+func NewTenderRepository(db *gorm.DB) TenderRepository {
+	return &tenderRepository{db: db}
+}
+
+func (r *tenderRepository) WithTx(tx *gorm.DB) TenderRepository {
+	return &tenderRepository{db: tx}
+}
+
+func (r *tenderRepository) Create(tender *model.Tender) error {
+	return r.db.Create(tender).Error
+}
+
+func (r *tenderRepository) Update(tender *model.Tender) error {
+	return r.db.Omit("Criteria", "Committee", "Item").Save(tender).Error
+}
+
+func (r *tenderRepository) FindByItemID(itemID uint) (*model.Tender, error) {
+	var tender model.Tender
+	err := r.db.
+		Preload("Criteria").
+		Preload("Committee").
+		Where("item_id = ?", itemID).
+		First(&tender).Error
+	return &tender, err
+}
+
+// FindByItemIDForUpdate locks the tender row; relations are loaded separately
+func (r *tenderRepository) FindByItemIDForUpdate(itemID uint) (*model.Tender, error) {
+	var tender model.Tender
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ?", itemID).
+		First(&tender).Error
+	if err != nil {
+		return &tender, err
+	}
+
+	if err := r.db.Where("tender_id = ?", tender.ID).Order("criterion_id ASC").Find(&tender.Criteria).Error; err != nil {
+		return &tender, err
+	}
+	err = r.db.Where("tender_id = ?", tender.ID).Order("member_id ASC").Find(&tender.Committee).Error
+	return &tender, err
+}
+
+func (r *tenderRepository) FindCommitteeMember(tenderID uint, userID string) (*model.TenderCommitteeMember, error) {
+	var member model.TenderCommitteeMember
+	err := r.db.Where("tender_id = ? AND user_id = ?", tenderID, userID).First(&member).Error
+	return &member, err
+}
+
+func (r *tenderRepository) UpdateCommitteeMember(member *model.TenderCommitteeMember) error {
+	return r.db.Save(member).Error
+}
+
+// ClearSignOffs invalidates every sign-off, e.g. after scores change
+func (r *tenderRepository) ClearSignOffs(tenderID uint) error {
+	return r.db.Model(&model.TenderCommitteeMember{}).
+		Where("tender_id = ?", tenderID).
+		Updates(map[string]interface{}{
+			"signed_digest": nil,
+			"signed_off_at": nil,
+		}).Error
+}
+
+func (r *tenderRepository) CreateOffer(offer *model.TenderOffer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *tenderRepository) UpdateOffer(offer *model.TenderOffer) error {
+	return r.db.Omit("Documents", "User").Save(offer).Error
+}
+
+func (r *tenderRepository) FindOfferByID(id uint) (*model.TenderOffer, error) {
+	var offer model.TenderOffer
+	err := r.db.Preload("Documents").First(&offer, id).Error
+	return &offer, err
+}
+
+func (r *tenderRepository) FindOfferByUser(tenderID uint, userID string) (*model.TenderOffer, error) {
+	var offer model.TenderOffer
+	err := r.db.Preload("Documents").
+		Where("tender_id = ? AND user_id = ? AND status != ?", tenderID, userID, model.TenderOfferStatusWithdrawn).
+		First(&offer).Error
+	return &offer, err
+}
+
+func (r *tenderRepository) FindSubmittedOffers(tenderID uint) ([]model.TenderOffer, error) {
+	var offers []model.TenderOffer
+	err := r.db.Preload("Documents").
+		Where("tender_id = ? AND status != ?", tenderID, model.TenderOfferStatusWithdrawn).
+		Order("submitted_at ASC").
+		Find(&offers).Error
+	return offers, err
+}
+
+func (r *tenderRepository) ReplaceDocuments(offerID uint, documents []model.TenderDocument) error {
+	if err := r.db.Where("offer_id = ?", offerID).Delete(&model.TenderDocument{}).Error; err != nil {
+		return err
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+	for i := range documents {
+		documents[i].OfferID = offerID
+	}
+	return r.db.Create(&documents).Error
+}
+
+// SaveScore upserts on (offer, criterion, evaluator)
+func (r *tenderRepository) SaveScore(score *model.TenderScore) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "offer_id"}, {Name: "criterion_id"}, {Name: "evaluator_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "notes", "updated_at"}),
+	}).Create(score).Error
+}
+
+func (r *tenderRepository) FindScoresByTender(tenderID uint) ([]model.TenderScore, error) {
+	var scores []model.TenderScore
+	err := r.db.
+		Joins("JOIN tender_offers ON tender_offers.offer_id = tender_scores.offer_id").
+		Where("tender_offers.tender_id = ?", tenderID).
+		Find(&scores).Error
+	return scores, err
+}
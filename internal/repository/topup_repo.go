@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TopUpRepository interface {
+	WithTx(tx *gorm.DB) TopUpRepository
+	Create(order *model.TopUpOrder) error
+	Update(order *model.TopUpOrder) error
+	FindByID(id uint) (*model.TopUpOrder, error)
+	FindByCodeForUpdate(code string) (*model.TopUpOrder, error)
+	FindByUserID(userID string) ([]model.TopUpOrder, error)
+	ExpirePending(now time.Time) (int64, error)
+}
+
+type topUpRepository struct {
+	db *gorm.DB
+}
+
+func NewTopUpRepository(db *gorm.DB) TopUpRepository {
+	return &topUpRepository{db: db}
+}
+
+func (r *topUpRepository) WithTx(tx *gorm.DB) TopUpRepository {
+	return &topUpRepository{db: tx}
+}
+
+func (r *topUpRepository) Create(order *model.TopUpOrder) error {
+	return r.db.Create(order).Error
+}
+
+func (r *topUpRepository) Update(order *model.TopUpOrder) error {
+	return r.db.Save(order).Error
+}
+
+func (r *topUpRepository) FindByID(id uint) (*model.TopUpOrder, error) {
+	var order model.TopUpOrder
+	err := r.db.First(&order, id).Error
+	return &order, err
+}
+
+// FindByCodeForUpdate locks the order so concurrent webhooks apply once
+func (r *topUpRepository) FindByCodeForUpdate(code string) (*model.TopUpOrder, error) {
+	var order model.TopUpOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_code = ?", code).
+		First(&order).Error
+	return &order, err
+}
+
+func (r *topUpRepository) FindByUserID(userID string) ([]model.TopUpOrder, error) {
+	var orders []model.TopUpOrder
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// ExpirePending marks every pending order past its expiry as expired
+func (r *topUpRepository) ExpirePending(now time.Time) (int64, error) {
+	result := r.db.Model(&model.TopUpOrder{}).
+		Where("status = ? AND expires_at <= ?", model.TopUpStatusPending, now).
+		Update("status", model.TopUpStatusExpired)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Transactor runs a function inside a single database transaction.
+// Repositories expose WithTx so callers can bind them to the same tx.
+type Transactor interface {
+	WithinTransaction(fn func(tx *gorm.DB) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+type afterCommitKey struct{}
+
+// afterCommitHooks collects the callbacks registered during one transaction
+type afterCommitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// WithinTransaction commits when fn returns nil and rolls back otherwise.
+// Hooks registered with AfterCommit run once the commit succeeded.
+func (t *transactor) WithinTransaction(fn func(tx *gorm.DB) error) error {
+	hooks := &afterCommitHooks{}
+	ctx := context.WithValue(t.db.Statement.Context, afterCommitKey{}, hooks)
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Don't let a hot lot queue bidders up indefinitely
+		if err := tx.Exec("SET LOCAL lock_timeout = '5s'").Error; err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks.hooks {
+		hook()
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction tx belongs to has committed, e.g.
+// to announce a change only when it is durable. It is dropped on rollback and
+// runs straight away when tx was not opened by WithinTransaction.
+func AfterCommit(tx *gorm.DB, fn func()) {
+	var hooks *afterCommitHooks
+	if tx != nil && tx.Statement != nil && tx.Statement.Context != nil {
+		hooks, _ = tx.Statement.Context.Value(afterCommitKey{}).(*afterCommitHooks)
+	}
+	if hooks == nil {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	hooks.hooks = append(hooks.hooks, fn)
+	hooks.mu.Unlock()
+}
@@ -6,12 +6,16 @@ import (
 
 	"yourapp/internal/model"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
+	WithTx(tx *gorm.DB) UserRepository
 	Create(user *model.User) error
 	FindByID(id string) (*model.User, error)
+	FindByIDForUpdate(id string) (*model.User, error)
 	FindByEmail(email string) (*model.User, error)
 	FindByUsername(username string) (*model.User, error)
 	FindByGoogleID(googleID string) (*model.User, error)
@@ -22,6 +26,8 @@ type UserRepository interface {
 	FindByResetToken(token string) (*model.User, error)
 	UpdatePassword(userID string, passwordHash string) error
 	UpdateLastLogin(userID string) error
+	UpdateBalances(userID string, balance, held decimal.Decimal) error
+	FindWithBalances() ([]model.User, error)
 }
 
 type userRepository struct {
@@ -32,6 +38,10 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{db: tx}
+}
+
 func (r *userRepository) Create(user *model.User) error {
 	return r.db.Create(user).Error
 }
@@ -45,6 +55,16 @@ func (r *userRepository) FindByID(id string) (*model.User, error) {
 	return &user, nil
 }
 
+// FindByIDForUpdate locks the user row until the surrounding transaction ends
+func (r *userRepository) FindByIDForUpdate(id string) (*model.User, error) {
+	var user model.User
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	var user model.User
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -72,8 +92,10 @@ func (r *userRepository) FindByGoogleID(googleID string) (*model.User, error) {
 	return &user, nil
 }
 
+// Update saves the profile. The balance columns are a ledger projection and
+// only change through UpdateBalances.
 func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("balance", "held_balance").Save(user).Error
 }
 
 func (r *userRepository) UpdateOTP(email string, otpCode string, expiresAt time.Time) error {
@@ -97,7 +119,7 @@ func (r *userRepository) VerifyOTP(email string, otpCode string) (*model.User, e
 	user.OTPExpiresAt = nil
 	user.IsVerified = true
 
-	if err := r.db.Save(&user).Error; err != nil {
+	if err := r.db.Omit("balance", "held_balance").Save(&user).Error; err != nil {
 		return nil, err
 	}
 
@@ -138,3 +160,21 @@ func (r *userRepository) UpdateLastLogin(userID string) error {
 		Where("id = ?", userID).
 		Update("last_login", now).Error
 }
+
+// UpdateBalances writes only the balance columns. It is called by the ledger
+// to refresh its projection and must not be used to move money.
+func (r *userRepository) UpdateBalances(userID string, balance, held decimal.Decimal) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"balance":      balance,
+			"held_balance": held,
+		}).Error
+}
+
+// FindWithBalances returns users whose cached balance columns are non-zero
+func (r *userRepository) FindWithBalances() ([]model.User, error) {
+	var users []model.User
+	err := r.db.Where("balance <> 0 OR held_balance <> 0").Order("id ASC").Find(&users).Error
+	return users, err
+}
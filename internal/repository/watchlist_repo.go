@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type WatchlistRepository interface {
+	WithTx(tx *gorm.DB) WatchlistRepository
+	Create(entry *model.WatchlistEntry) error
+	Delete(userID string, itemID uint) (bool, error)
+	FindByUserAndItem(userID string, itemID uint) (*model.WatchlistEntry, error)
+	FindByUser(userID string) ([]model.WatchlistEntry, error)
+	FindUserIDsByItem(itemID uint) ([]string, error)
+	FindWatchedItemIDs(userID string, itemIDs []uint) ([]uint, error)
+}
+
+type watchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistRepository(db *gorm.DB) WatchlistRepository {
+	return &watchlistRepository{db: db}
+}
+
+func (r *watchlistRepository) WithTx(tx *gorm.DB) WatchlistRepository {
+	return &watchlistRepository{db: tx}
+}
+
+func (r *watchlistRepository) Create(entry *model.WatchlistEntry) error {
+	return r.db.Omit("Item").Create(entry).Error
+}
+
+// Delete removes the entry and reports whether there was one
+func (r *watchlistRepository) Delete(userID string, itemID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND item_id = ?", userID, itemID).Delete(&model.WatchlistEntry{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *watchlistRepository) FindByUserAndItem(userID string, itemID uint) (*model.WatchlistEntry, error) {
+	var entry model.WatchlistEntry
+	err := r.db.Where("user_id = ? AND item_id = ?", userID, itemID).First(&entry).Error
+	return &entry, err
+}
+
+func (r *watchlistRepository) FindByUser(userID string) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	err := r.db.Preload("Item").
+		Preload("Item.Images").
+		Preload("Item.Schedule").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *watchlistRepository) FindUserIDsByItem(itemID uint) ([]string, error) {
+	var userIDs []string
+	err := r.db.Model(&model.WatchlistEntry{}).
+		Where("item_id = ?", itemID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// FindWatchedItemIDs returns which of itemIDs the user watches
+func (r *watchlistRepository) FindWatchedItemIDs(userID string, itemIDs []uint) ([]uint, error) {
+	var watched []uint
+	if len(itemIDs) == 0 {
+		return watched, nil
+	}
+	err := r.db.Model(&model.WatchlistEntry{}).
+		Where("user_id = ? AND item_id IN ?", userID, itemIDs).
+		Pluck("item_id", &watched).Error
+	return watched, err
+}
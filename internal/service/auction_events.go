@@ -0,0 +1,221 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Live auction event types pushed to subscribers of an item
+const (
+	AuctionEventBidPlaced     = "bid_placed"
+	AuctionEventHighestBid    = "highest_bid"
+	AuctionEventBidCancelled  = "bid_cancelled"
+	AuctionEventStatusChanged = "status_changed"
+	AuctionEventExtended      = "auction_extended"
+	AuctionEventViewers       = "viewers"
+
+	// AuctionEventNotification carries a new in-app notification to its
+	// recipient only
+	AuctionEventNotification = "notification"
+)
+
+// AuctionEvent is a change on an auction item as announced to live
+// subscribers. Bidders are never named; ActorID only lets a subscriber learn
+// that an event was their own and is not forwarded. An event with a
+// RecipientID is private and goes to that user's subscriptions only.
+type AuctionEvent struct {
+	Type        string      `json:"type"`
+	ItemID      uint        `json:"item_id"`
+	Data        interface{} `json:"data,omitempty"`
+	ActorID     string      `json:"actor_id,omitempty"`
+	RecipientID string      `json:"recipient_id,omitempty"`
+	At          time.Time   `json:"at"`
+}
+
+// AuctionEventPublisher delivers auction events to live subscribers on every
+// server instance
+type AuctionEventPublisher interface {
+	PublishAuctionEvent(event AuctionEvent)
+}
+
+type BidPlacedEvent struct {
+	BidID    uint             `json:"bid_id"`
+	Amount   *decimal.Decimal `json:"amount,omitempty"` // left out while sealed
+	BidType  model.BidType    `json:"bid_type"`
+	Bidder   string           `json:"bidder,omitempty"` // masked name
+	BidTime  time.Time        `json:"bid_time"`
+	BidCount int              `json:"bid_count"`
+}
+
+type HighestBidEvent struct {
+	Amount   decimal.Decimal `json:"amount"`
+	BidCount int             `json:"bid_count"`
+	Bidder   string          `json:"bidder,omitempty"`
+}
+
+type BidCancelledEvent struct {
+	BidID      uint            `json:"bid_id"`
+	HighestBid decimal.Decimal `json:"highest_bid"`
+	BidCount   int             `json:"bid_count"`
+}
+
+type StatusChangedEvent struct {
+	Status  model.AuctionStatus   `json:"status"`
+	Outcome *model.AuctionOutcome `json:"outcome,omitempty"`
+}
+
+type AuctionExtendedEvent struct {
+	PreviousEnd    time.Time `json:"previous_end"`
+	NewEnd         time.Time `json:"new_end"`
+	ExtensionCount int       `json:"extension_count"`
+}
+
+type ViewersEvent struct {
+	Count int `json:"count"`
+}
+
+// Countdown is the time left before a published lot opens and before it
+// closes. The seconds are only meaningful as of the last Tick.
+type Countdown struct {
+	ItemID       uint                `json:"item_id"`
+	Status       model.AuctionStatus `json:"status"`
+	AuctionStart time.Time           `json:"auction_start"`
+	AuctionEnd   time.Time           `json:"auction_end"`
+	StartsIn     int64               `json:"starts_in_seconds"`
+	EndsIn       int64               `json:"ends_in_seconds"`
+}
+
+// Tick recomputes the seconds left as of now
+func (c *Countdown) Tick(now time.Time) {
+	c.StartsIn = secondsUntil(c.AuctionStart, now)
+	c.EndsIn = secondsUntil(c.AuctionEnd, now)
+}
+
+func secondsUntil(t, now time.Time) int64 {
+	if !t.After(now) {
+		return 0
+	}
+	return int64(t.Sub(now).Seconds())
+}
+
+// ========== LIVE EVENTS ==========
+
+// publishTx announces an event once tx commits, so subscribers never see a
+// bid that was rolled back. fn builds the event after the commit.
+func (s *auctionService) publishTx(tx *gorm.DB, fn func() AuctionEvent) {
+	if s.events == nil {
+		return
+	}
+	repository.AfterCommit(tx, func() {
+		event := fn()
+		if event.At.IsZero() {
+			event.At = time.Now()
+		}
+		s.events.PublishAuctionEvent(event)
+	})
+}
+
+// publishBidTx announces a new bid and, on open lots, the new highest bid
+func (s *auctionService) publishBidTx(tx *gorm.DB, item *model.AuctionItem, bid *model.Bid) {
+	sealed := isSealed(item)
+	bidCount := item.BidCount
+	bidID, userID, amount, bidType, bidTime := bid.ID, bid.UserID, bid.BidAmount, bid.BidType, bid.BidTime
+
+	s.publishTx(tx, func() AuctionEvent {
+		placed := BidPlacedEvent{
+			BidID:    bidID,
+			BidType:  bidType,
+			BidTime:  bidTime,
+			BidCount: bidCount,
+		}
+		if !sealed {
+			placed.Amount = &amount
+			placed.Bidder = s.maskedBidder(userID)
+		}
+		return AuctionEvent{Type: AuctionEventBidPlaced, ItemID: item.ID, Data: placed, ActorID: userID}
+	})
+	if sealed {
+		return
+	}
+	s.publishTx(tx, func() AuctionEvent {
+		return AuctionEvent{
+			Type:    AuctionEventHighestBid,
+			ItemID:  item.ID,
+			Data:    HighestBidEvent{Amount: amount, BidCount: bidCount, Bidder: s.maskedBidder(userID)},
+			ActorID: userID,
+		}
+	})
+}
+
+func (s *auctionService) publishStatusTx(tx *gorm.DB, itemID uint, status model.AuctionStatus, outcome *model.AuctionOutcome) {
+	s.publishTx(tx, func() AuctionEvent {
+		return AuctionEvent{
+			Type:   AuctionEventStatusChanged,
+			ItemID: itemID,
+			Data:   StatusChangedEvent{Status: status, Outcome: outcome},
+		}
+	})
+}
+
+func (s *auctionService) publishCancellationTx(tx *gorm.DB, item *model.AuctionItem, cancellation *model.BidCancellation) {
+	data := BidCancelledEvent{
+		BidID:      cancellation.BidID,
+		HighestBid: cancellation.NewHighestBid,
+		BidCount:   item.BidCount,
+	}
+	s.publishTx(tx, func() AuctionEvent {
+		return AuctionEvent{Type: AuctionEventBidCancelled, ItemID: item.ID, Data: data}
+	})
+}
+
+// GetCountdowns lists the published and running lots with the time they have
+// left, soonest ending first
+func (s *auctionService) GetCountdowns(now time.Time) ([]Countdown, error) {
+	items, err := s.itemRepo.FindLive()
+	if err != nil {
+		return nil, err
+	}
+
+	countdowns := make([]Countdown, 0, len(items))
+	for _, item := range items {
+		if item.Schedule == nil {
+			continue
+		}
+		countdown := Countdown{
+			ItemID:       item.ID,
+			Status:       item.Status,
+			AuctionStart: item.Schedule.AuctionStart,
+			AuctionEnd:   item.Schedule.AuctionEnd,
+		}
+		countdown.Tick(now)
+		countdowns = append(countdowns, countdown)
+	}
+	return countdowns, nil
+}
+
+// maskedBidder returns the bidder's name with all but the first and last
+// letter hidden, e.g. "B***i"
+func (s *auctionService) maskedBidder(userID string) string {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "***"
+	}
+	return maskName(user.FullName)
+}
+
+func maskName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+	switch len(runes) {
+	case 0:
+		return "***"
+	case 1, 2:
+		return string(runes[0]) + "***"
+	}
+	return string(runes[0]) + "***" + string(runes[len(runes)-1])
+}
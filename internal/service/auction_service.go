@@ -3,14 +3,20 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"yourapp/internal/model"
 	"yourapp/internal/repository"
 
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
+// ErrBidConflict is returned when a bid lost a race for the auction item row
+// (lock timeout, deadlock or serialization failure). The client may retry.
+var ErrBidConflict = errors.New("auction item is busy with another bid, please retry")
+
 type AuctionService interface {
 	// Seller
 	CreateSeller(req CreateSellerRequest) (*model.Seller, error)
@@ -20,6 +26,7 @@ type AuctionService interface {
 	// Organizer
 	CreateOrganizer(req CreateOrganizerRequest) (*model.Organizer, error)
 	GetOrganizer(id uint) (*model.Organizer, error)
+	UpdateOrganizer(id uint, req UpdateOrganizerRequest) (*model.Organizer, error)
 	GetAllOrganizers() ([]model.Organizer, error)
 
 	// Category
@@ -30,15 +37,52 @@ type AuctionService interface {
 	// Auction Item
 	CreateAuctionItem(req CreateAuctionItemRequest) (*model.AuctionItem, error)
 	GetAuctionItem(id uint) (*model.AuctionItem, error)
+	AuctionItemExists(id uint) bool
 	GetPublishedAuctions(filters repository.AuctionItemFilters) ([]model.AuctionItem, int64, error)
 	UpdateAuctionItem(id uint, req UpdateAuctionItemRequest) (*model.AuctionItem, error)
 	PublishAuctionItem(id uint) error
+	CancelAuction(req CancelAuctionRequest) (*model.AuctionItem, error)
 	DeleteAuctionItem(id uint) error
 
 	// Bidding
 	PlaceBid(req PlaceBidRequest) (*model.Bid, error)
 	GetItemBids(itemID uint) ([]model.Bid, error)
 	GetUserBids(userID string) ([]model.Bid, error)
+	CancelBid(req CancelBidRequest) (*model.BidCancellation, error)
+	GetBidCancellations(itemID uint) ([]model.BidCancellation, error)
+
+	// Proxy bidding
+	SetProxyBid(req ProxyBidRequest) (*model.ProxyBid, error)
+	RaiseProxyBid(req ProxyBidRequest) (*model.ProxyBid, error)
+	WithdrawProxyBid(itemID uint, userID string) error
+	GetUserProxyBids(userID string) ([]model.ProxyBid, error)
+
+	// Bid increments
+	SaveIncrementTable(req IncrementTableRequest) (*model.IncrementTable, error)
+	GetIncrementTables() ([]model.IncrementTable, error)
+	DeleteIncrementTable(id uint) error
+	GetNextBids(itemID uint, count int) (*NextBids, error)
+
+	// Soft close
+	SaveSoftCloseRule(req SoftCloseRuleRequest) (*model.SoftCloseRule, error)
+	GetSoftCloseRules() ([]model.SoftCloseRule, error)
+	DeleteSoftCloseRule(id uint) error
+	GetAuctionExtensions(itemID uint) ([]model.AuctionExtension, error)
+
+	// Descending-price bidding
+	AcceptClockPrice(req AcceptClockPriceRequest) (*model.Bid, error)
+
+	// Sealed bidding
+	RevealSealedBids(itemID uint) (*SealedBidResult, error)
+	GetSealedBidResult(itemID uint) (*SealedBidResult, error)
+
+	// Live feed
+	GetCountdowns(now time.Time) ([]Countdown, error)
+
+	// Lifecycle (background scheduler)
+	StartDueAuctions(now time.Time) (int, error)
+	CloseDueAuctions(now time.Time) (int, error)
+	CloseTenderTx(tx *gorm.DB, item *model.AuctionItem, bids []model.Bid, now time.Time) error
 }
 
 // ========== REQUEST/RESPONSE STRUCTS ==========
@@ -61,6 +105,21 @@ type CreateOrganizerRequest struct {
 	Province      string              `json:"province"`
 	Phone         string              `json:"phone"`
 	Email         string              `json:"email"`
+
+	RequireStartAtLimit bool `json:"require_start_at_limit"`
+	HideLimitPrice      bool `json:"hide_limit_price"`
+}
+
+type UpdateOrganizerRequest struct {
+	OrganizerName string `json:"organizer_name"`
+	Address       string `json:"address"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	Phone         string `json:"phone"`
+	Email         string `json:"email"`
+
+	RequireStartAtLimit *bool `json:"require_start_at_limit"`
+	HideLimitPrice      *bool `json:"hide_limit_price"`
 }
 
 type CreateCategoryRequest struct {
@@ -83,6 +142,8 @@ type CreateAuctionItemRequest struct {
 	DepositAmount       float64             `json:"deposit_amount" binding:"required"`
 	StartingPrice       float64             `json:"starting_price"`
 	IncrementAmount     float64             `json:"increment_amount"`
+	DecrementAmount     float64             `json:"decrement_amount"`
+	DecrementInterval   int                 `json:"decrement_interval_seconds"`
 	AuctionMethod       model.AuctionMethod `json:"auction_method"`
 	Images              []ImageRequest      `json:"images"`
 	Schedule            *ScheduleRequest    `json:"schedule"`
@@ -99,6 +160,8 @@ type UpdateAuctionItemRequest struct {
 	DepositAmount       float64             `json:"deposit_amount"`
 	StartingPrice       float64             `json:"starting_price"`
 	IncrementAmount     float64             `json:"increment_amount"`
+	DecrementAmount     float64             `json:"decrement_amount"`
+	DecrementInterval   int                 `json:"decrement_interval_seconds"`
 	AuctionMethod       model.AuctionMethod `json:"auction_method"`
 	Status              model.AuctionStatus `json:"status"`
 	Images              []ImageRequest      `json:"images"`
@@ -129,9 +192,58 @@ type PlaceBidRequest struct {
 	UserAgent string  `json:"user_agent"`
 }
 
+type AcceptClockPriceRequest struct {
+	ItemID    uint   `json:"-"`
+	UserID    string `json:"-"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+type CancelAuctionRequest struct {
+	ItemID  uint   `json:"-"`
+	AdminID string `json:"-"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+type CancelBidRequest struct {
+	BidID   uint   `json:"-"`
+	AdminID string `json:"-"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+type ProxyBidRequest struct {
+	ItemID    uint    `json:"item_id"`
+	UserID    string  `json:"-"`
+	MaxAmount float64 `json:"max_amount" binding:"required"`
+}
+
+type SoftCloseRuleRequest struct {
+	ItemID           *uint `json:"item_id"`
+	OrganizerID      *uint `json:"organizer_id"`
+	WindowMinutes    int   `json:"window_minutes" binding:"required"`
+	ExtensionMinutes int   `json:"extension_minutes" binding:"required"`
+	MaxExtensions    int   `json:"max_extensions" binding:"required"`
+	IsActive         *bool `json:"is_active"`
+}
+
+type IncrementTableRequest struct {
+	Name        string                 `json:"name" binding:"required"`
+	ItemID      *uint                  `json:"item_id"`
+	CategoryID  *uint                  `json:"category_id"`
+	OrganizerID *uint                  `json:"organizer_id"`
+	IsActive    *bool                  `json:"is_active"`
+	Bands       []IncrementBandRequest `json:"bands" binding:"required"`
+}
+
+type IncrementBandRequest struct {
+	MinPrice  float64 `json:"min_price"`
+	Increment float64 `json:"increment" binding:"required"`
+}
+
 // ========== SERVICE IMPLEMENTATION ==========
 
 type auctionService struct {
+	transactor    repository.Transactor
 	sellerRepo    repository.SellerRepository
 	organizerRepo repository.OrganizerRepository
 	categoryRepo  repository.CategoryRepository
@@ -139,10 +251,21 @@ type auctionService struct {
 	imageRepo     repository.ItemImageRepository
 	scheduleRepo  repository.AuctionScheduleRepository
 	bidRepo       repository.BidRepository
+	proxyBidRepo  repository.ProxyBidRepository
+	softCloseRepo repository.SoftCloseRepository
+	sealedBidRepo repository.SealedBidRepository
+	incrementRepo repository.IncrementTableRepository
 	userRepo      repository.UserRepository
+	fundsService  FundsService
+	participation ParticipationService
+	settlement    SettlementService
+	notifications NotificationService
+	savedSearches SavedSearchService
+	events        AuctionEventPublisher
 }
 
 func NewAuctionService(
+	transactor repository.Transactor,
 	sellerRepo repository.SellerRepository,
 	organizerRepo repository.OrganizerRepository,
 	categoryRepo repository.CategoryRepository,
@@ -150,9 +273,20 @@ func NewAuctionService(
 	imageRepo repository.ItemImageRepository,
 	scheduleRepo repository.AuctionScheduleRepository,
 	bidRepo repository.BidRepository,
+	proxyBidRepo repository.ProxyBidRepository,
+	softCloseRepo repository.SoftCloseRepository,
+	sealedBidRepo repository.SealedBidRepository,
+	incrementRepo repository.IncrementTableRepository,
 	userRepo repository.UserRepository,
+	fundsService FundsService,
+	participation ParticipationService,
+	settlement SettlementService,
+	notifications NotificationService,
+	savedSearches SavedSearchService,
+	events AuctionEventPublisher,
 ) AuctionService {
 	return &auctionService{
+		transactor:    transactor,
 		sellerRepo:    sellerRepo,
 		organizerRepo: organizerRepo,
 		categoryRepo:  categoryRepo,
@@ -160,7 +294,17 @@ func NewAuctionService(
 		imageRepo:     imageRepo,
 		scheduleRepo:  scheduleRepo,
 		bidRepo:       bidRepo,
+		proxyBidRepo:  proxyBidRepo,
+		softCloseRepo: softCloseRepo,
+		sealedBidRepo: sealedBidRepo,
+		incrementRepo: incrementRepo,
 		userRepo:      userRepo,
+		fundsService:  fundsService,
+		participation: participation,
+		settlement:    settlement,
+		notifications: notifications,
+		savedSearches: savedSearches,
+		events:        events,
 	}
 }
 
@@ -203,6 +347,9 @@ func (s *auctionService) CreateOrganizer(req CreateOrganizerRequest) (*model.Org
 		Province:      stringPtr(req.Province),
 		Phone:         stringPtr(req.Phone),
 		Email:         stringPtr(req.Email),
+
+		RequireStartAtLimit: req.RequireStartAtLimit,
+		HideLimitPrice:      req.HideLimitPrice,
 	}
 
 	if err := s.organizerRepo.Create(organizer); err != nil {
@@ -216,6 +363,44 @@ func (s *auctionService) GetOrganizer(id uint) (*model.Organizer, error) {
 	return s.organizerRepo.FindByID(id)
 }
 
+func (s *auctionService) UpdateOrganizer(id uint, req UpdateOrganizerRequest) (*model.Organizer, error) {
+	organizer, err := s.organizerRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("organizer not found")
+	}
+
+	if req.OrganizerName != "" {
+		organizer.OrganizerName = req.OrganizerName
+	}
+	if req.Address != "" {
+		organizer.Address = stringPtr(req.Address)
+	}
+	if req.City != "" {
+		organizer.City = stringPtr(req.City)
+	}
+	if req.Province != "" {
+		organizer.Province = stringPtr(req.Province)
+	}
+	if req.Phone != "" {
+		organizer.Phone = stringPtr(req.Phone)
+	}
+	if req.Email != "" {
+		organizer.Email = stringPtr(req.Email)
+	}
+	if req.RequireStartAtLimit != nil {
+		organizer.RequireStartAtLimit = *req.RequireStartAtLimit
+	}
+	if req.HideLimitPrice != nil {
+		organizer.HideLimitPrice = *req.HideLimitPrice
+	}
+
+	if err := s.organizerRepo.Update(organizer); err != nil {
+		return nil, err
+	}
+
+	return organizer, nil
+}
+
 func (s *auctionService) GetAllOrganizers() ([]model.Organizer, error) {
 	return s.organizerRepo.FindAll()
 }
@@ -258,10 +443,15 @@ func (s *auctionService) CreateAuctionItem(req CreateAuctionItemRequest) (*model
 	}
 
 	// Verify organizer exists
-	if _, err := s.organizerRepo.FindByID(req.OrganizerID); err != nil {
+	organizer, err := s.organizerRepo.FindByID(req.OrganizerID)
+	if err != nil {
 		return nil, errors.New("organizer not found")
 	}
 
+	if err := validateStartingPrice(organizer, decimal.NewFromFloat(req.StartingPrice), decimal.NewFromFloat(req.LimitPrice)); err != nil {
+		return nil, err
+	}
+
 	item := &model.AuctionItem{
 		LotCode:             req.LotCode,
 		ItemName:            req.ItemName,
@@ -277,9 +467,14 @@ func (s *auctionService) CreateAuctionItem(req CreateAuctionItemRequest) (*model
 		StartingPrice:       decimal.NewFromFloat(req.StartingPrice),
 		CurrentHighestBid:   decimal.NewFromFloat(req.StartingPrice),
 		IncrementAmount:     decimal.NewFromFloat(req.IncrementAmount),
+		DecrementAmount:     decimal.NewFromFloat(req.DecrementAmount),
+		DecrementInterval:   req.DecrementInterval,
 		AuctionMethod:       req.AuctionMethod,
 		Status:              model.AuctionStatusDraft,
 	}
+	if err := validateDescending(item); err != nil {
+		return nil, err
+	}
 
 	if err := s.itemRepo.Create(item); err != nil {
 		return nil, err
@@ -333,6 +528,13 @@ func (s *auctionService) GetAuctionItem(id uint) (*model.AuctionItem, error) {
 	return item, nil
 }
 
+// AuctionItemExists looks the item up without counting a view, for callers
+// that only check the id, like the live feed
+func (s *auctionService) AuctionItemExists(id uint) bool {
+	_, err := s.itemRepo.FindByID(id)
+	return err == nil
+}
+
 func (s *auctionService) GetPublishedAuctions(filters repository.AuctionItemFilters) ([]model.AuctionItem, int64, error) {
 	return s.itemRepo.FindPublished(filters)
 }
@@ -379,10 +581,25 @@ func (s *auctionService) UpdateAuctionItem(id uint, req UpdateAuctionItemRequest
 	if req.IncrementAmount > 0 {
 		item.IncrementAmount = decimal.NewFromFloat(req.IncrementAmount)
 	}
+	if req.DecrementAmount > 0 {
+		item.DecrementAmount = decimal.NewFromFloat(req.DecrementAmount)
+	}
+	if req.DecrementInterval > 0 {
+		item.DecrementInterval = req.DecrementInterval
+	}
 	if req.AuctionMethod != "" {
 		item.AuctionMethod = req.AuctionMethod
 	}
 
+	if item.Organizer != nil {
+		if err := validateStartingPrice(item.Organizer, item.StartingPrice, item.LimitPrice); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateDescending(item); err != nil {
+		return nil, err
+	}
+
 	if err := s.itemRepo.Update(item); err != nil {
 		return nil, err
 	}
@@ -456,7 +673,27 @@ func (s *auctionService) PublishAuctionItem(id uint) error {
 		return errors.New("auction schedule is required before publishing")
 	}
 
-	return s.itemRepo.UpdateStatus(id, model.AuctionStatusPublished)
+	// The organizer's reserve settings may have changed since the item was drafted
+	if item.Organizer != nil {
+		if err := validateStartingPrice(item.Organizer, item.StartingPrice, item.LimitPrice); err != nil {
+			return err
+		}
+	}
+	if err := validateDescending(item); err != nil {
+		return err
+	}
+
+	if err := s.itemRepo.UpdateStatus(id, model.AuctionStatusPublished); err != nil {
+		return err
+	}
+	item.Status = model.AuctionStatusPublished
+	s.publishStatusTx(nil, id, model.AuctionStatusPublished, nil)
+
+	// Alerts are best effort; a failure never takes the lot back off the listing
+	if _, err := s.savedSearches.MatchPublishedItem(item); err != nil {
+		log.Printf("Saved searches: failed to match item %d: %v", id, err)
+	}
+	return nil
 }
 
 func (s *auctionService) DeleteAuctionItem(id uint) error {
@@ -479,86 +716,250 @@ func (s *auctionService) DeleteAuctionItem(id uint) error {
 // ========== BIDDING ==========
 
 func (s *auctionService) PlaceBid(req PlaceBidRequest) (*model.Bid, error) {
-	// Get item
-	item, err := s.itemRepo.FindByID(req.ItemID)
-	if err != nil {
-		return nil, errors.New("auction item not found")
+	// Get user
+	if _, err := s.userRepo.FindByID(req.UserID); err != nil {
+		return nil, errors.New("user not found")
 	}
 
-	// Check if auction is ongoing
-	if item.Status != model.AuctionStatusOngoing && item.Status != model.AuctionStatusPublished {
-		return nil, errors.New("auction is not active")
-	}
+	bidAmount := decimal.NewFromFloat(req.BidAmount)
+	var bid *model.Bid
+	sealed := false
 
-	// Check auction schedule
-	if item.Schedule != nil {
-		now := time.Now()
-		if now.Before(item.Schedule.AuctionStart) {
-			return nil, errors.New("auction has not started yet")
+	err := s.withBiddableItem(req.ItemID, func(tx *gorm.DB, item *model.AuctionItem) error {
+		if isDescending(item) {
+			return errors.New("descending-price lots are bought by accepting the current price")
 		}
-		if now.After(item.Schedule.AuctionEnd) {
-			return nil, errors.New("auction has ended")
+		if err := s.participation.RequireEligibleTx(tx, item.ID, req.UserID); err != nil {
+			return err
 		}
-	}
 
-	// Check minimum bid
-	bidAmount := decimal.NewFromFloat(req.BidAmount)
-	minBid := item.CurrentHighestBid.Add(item.IncrementAmount)
+		if isSealed(item) {
+			sealed = true
+			var err error
+			bid, err = s.placeSealedBidTx(tx, item, req, bidAmount)
+			return err
+		}
 
-	if item.BidCount == 0 {
-		// First bid must be at least starting price
-		if bidAmount.LessThan(item.StartingPrice) {
-			return nil, fmt.Errorf("bid must be at least the starting price: %s", item.StartingPrice.String())
+		// Check minimum bid against the increment band of the current price
+		ladder, err := s.incrementsTx(tx, item)
+		if err != nil {
+			return err
 		}
-	} else {
+		minBid := ladder.nextMinimumBid(item)
 		if bidAmount.LessThan(minBid) {
-			return nil, fmt.Errorf("bid must be at least %s", minBid.String())
+			if item.BidCount == 0 {
+				// First bid must be at least starting price
+				return fmt.Errorf("bid must be at least the starting price: %s", item.StartingPrice.String())
+			}
+			return fmt.Errorf("bid must be at least %s (increment %s)", minBid.String(), minBid.Sub(item.CurrentHighestBid).String())
 		}
-	}
 
-	// Get user
-	user, err := s.userRepo.FindByID(req.UserID)
+		bid, err = s.placeBidTx(tx, item, req.UserID, bidAmount, model.BidTypeManual, req.IPAddress, req.UserAgent)
+		if err != nil {
+			return err
+		}
+
+		// Let registered maximums respond to the new price
+		return s.resolveProxyBids(tx, item)
+	})
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
-	// Check user balance
-	if user.Balance.LessThan(bidAmount) {
-		return nil, errors.New("insufficient balance")
+	if sealed {
+		return bid, nil
+	}
+
+	// The proxy engine may already have outbid this bid
+	if current, err := s.bidRepo.FindByID(bid.ID); err == nil {
+		current.User = nil
+		current.Item = nil
+		return current, nil
+	}
+	return bid, nil
+}
+
+// placeBidTx records a new leading bid and moves the item's bid info along.
+// The bidder's funds are held for the amount first. The caller must hold the
+// item row lock; item is updated in place.
+func (s *auctionService) placeBidTx(tx *gorm.DB, item *model.AuctionItem, userID string, amount decimal.Decimal, bidType model.BidType, ipAddress, userAgent string) (*model.Bid, error) {
+	bidRepo := s.bidRepo.WithTx(tx)
+
+	if err := s.fundsService.EnsureHoldTx(tx, userID, item.ID, model.HoldKindBid, amount); err != nil {
+		return nil, err
 	}
 
-	// Create bid
 	bid := &model.Bid{
-		ItemID:    req.ItemID,
-		UserID:    req.UserID,
-		BidAmount: bidAmount,
-		BidType:   model.BidTypeManual,
+		ItemID:    item.ID,
+		UserID:    userID,
+		BidAmount: amount,
+		BidType:   bidType,
 		BidStatus: model.BidStatusWinning,
 		IsHighest: true,
-		IPAddress: stringPtr(req.IPAddress),
-		UserAgent: stringPtr(req.UserAgent),
+		IPAddress: stringPtr(ipAddress),
+		UserAgent: stringPtr(userAgent),
 	}
 
-	if err := s.bidRepo.Create(bid); err != nil {
+	if err := bidRepo.Create(bid); err != nil {
 		return nil, err
 	}
 
 	// Mark previous bids as outbid
-	_ = s.bidRepo.MarkAllAsOutbid(req.ItemID, bid.ID)
+	if err := bidRepo.MarkAllAsOutbid(item.ID, bid.ID); err != nil {
+		return nil, err
+	}
 
 	// Update item with new highest bid
-	bidAmountFloat, _ := bidAmount.Float64()
-	_ = s.itemRepo.UpdateBidInfo(req.ItemID, bidAmountFloat, item.BidCount+1)
-
-	// Update item status to ongoing if it was published
-	if item.Status == model.AuctionStatusPublished {
-		_ = s.itemRepo.UpdateStatus(req.ItemID, model.AuctionStatusOngoing)
+	amountFloat, _ := amount.Float64()
+	if err := s.itemRepo.WithTx(tx).UpdateBidInfo(item.ID, amountFloat, item.BidCount+1); err != nil {
+		return nil, err
 	}
 
+	item.CurrentHighestBid = amount
+	item.BidCount++
+	s.publishBidTx(tx, item, bid)
+
 	return bid, nil
 }
 
+// withBiddableItem locks an item that currently accepts bids and runs fn in
+// the same transaction.
+func (s *auctionService) withBiddableItem(itemID uint, fn func(tx *gorm.DB, item *model.AuctionItem) error) error {
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+
+		item, err := itemRepo.FindByIDForUpdate(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := validateBiddable(item, now); err != nil {
+			return err
+		}
+
+		bidsBefore := item.BidCount
+		var previous *model.Bid
+		if !isSealed(item) {
+			previous, err = s.bidRepo.WithTx(tx).FindWinningBid(item.ID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if err := fn(tx, item); err != nil {
+			return err
+		}
+
+		// Outbid bidders get their funds back
+		if err := s.syncBidHoldsTx(tx, item); err != nil {
+			return err
+		}
+
+		// Bids accepted near the end may extend it
+		if item.BidCount > bidsBefore && !isSealed(item) {
+			leader, err := s.bidRepo.WithTx(tx).FindWinningBid(item.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.applySoftClose(tx, item, leader.ID, now); err != nil {
+				return err
+			}
+			// Only the final leader counts, so a proxy war settled within
+			// this bid doesn't notify anyone who ends up in front
+			if previous != nil && previous.UserID != leader.UserID {
+				if err := s.notifications.NotifyOutbidTx(tx, item, previous); err != nil {
+					return err
+				}
+			}
+		}
+
+		if item.Status == model.AuctionStatusPublished && item.BidCount > 0 {
+			s.publishStatusTx(tx, item.ID, model.AuctionStatusOngoing, nil)
+			return itemRepo.UpdateStatus(item.ID, model.AuctionStatusOngoing)
+		}
+		return nil
+	})
+	if err != nil && isRetryableTxError(err) {
+		return ErrBidConflict
+	}
+	return err
+}
+
+// syncBidHoldsTx brings every bidder's hold on the item in line with what they
+// can still be charged: the leader's bid and any active maximum. On sealed
+// lots each bidder's sealed amount stays held. Everyone else is released.
+func (s *auctionService) syncBidHoldsTx(tx *gorm.DB, item *model.AuctionItem) error {
+	bidRepo := s.bidRepo.WithTx(tx)
+	amounts := make(map[string]decimal.Decimal)
+
+	if isSealed(item) {
+		bids, err := bidRepo.FindValidByItemID(item.ID)
+		if err != nil {
+			return err
+		}
+		for _, b := range bids {
+			amounts[b.UserID] = decimal.Max(amounts[b.UserID], b.BidAmount)
+		}
+		return s.fundsService.SyncItemHoldsTx(tx, item.ID, model.HoldKindBid, amounts)
+	}
+
+	proxies, err := s.proxyBidRepo.WithTx(tx).FindActiveByItem(item.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range proxies {
+		amounts[p.UserID] = p.MaxAmount
+	}
+	if leader, err := bidRepo.FindWinningBid(item.ID); err == nil {
+		amounts[leader.UserID] = decimal.Max(amounts[leader.UserID], leader.BidAmount)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.fundsService.SyncItemHoldsTx(tx, item.ID, model.HoldKindBid, amounts)
+}
+
+// validateBiddable checks that an item accepts bids at the given time
+func validateBiddable(item *model.AuctionItem, now time.Time) error {
+	if item.AuctionMethod == model.AuctionMethodTender {
+		return errors.New("tender lots accept offers, not bids")
+	}
+
+	// Check if auction is ongoing
+	if item.Status != model.AuctionStatusOngoing && item.Status != model.AuctionStatusPublished {
+		return errors.New("auction is not active")
+	}
+
+	// Check auction schedule
+	if item.Schedule != nil {
+		if now.Before(item.Schedule.AuctionStart) {
+			return errors.New("auction has not started yet")
+		}
+		if now.After(item.Schedule.AuctionEnd) {
+			return errors.New("auction has ended")
+		}
+	}
+
+	return nil
+}
+
 func (s *auctionService) GetItemBids(itemID uint) ([]model.Bid, error) {
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("auction item not found")
+	}
+
+	// Sealed amounts are only visible once opened
+	if isSealed(item) {
+		if _, err := s.sealedBidRepo.FindRevealByItemID(itemID); err != nil {
+			return nil, ErrBidsSealed
+		}
+	}
+
 	return s.bidRepo.FindByItemID(itemID)
 }
 
@@ -568,6 +969,31 @@ func (s *auctionService) GetUserBids(userID string) ([]model.Bid, error) {
 
 // ========== HELPER FUNCTIONS ==========
 
+// validateStartingPrice enforces the organizer's choice of whether bidding
+// may open below the limit price
+func validateStartingPrice(organizer *model.Organizer, startingPrice, limitPrice decimal.Decimal) error {
+	if organizer.RequireStartAtLimit && startingPrice.LessThan(limitPrice) {
+		return errors.New("starting price may not be below the limit price for this organizer")
+	}
+	return nil
+}
+
+// isRetryableTxError reports whether err is a Postgres lock/serialization
+// failure that a client can safely retry.
+func isRetryableTxError(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.SQLState() {
+	case "40001", // serialization_failure
+		"40P01", // deadlock_detected
+		"55P03": // lock_not_available
+		return true
+	}
+	return false
+}
+
 func stringPtr(s string) *string {
 	if s == "" {
 		return nil
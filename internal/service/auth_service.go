@@ -151,7 +151,10 @@ func (s *authService) Register(req RegisterRequest) (*RegisterResponse, error) {
 
 	userType := req.UserType
 	if userType == "" {
-		userType = "member"
+		userType = model.UserTypeMember
+	}
+	if userType == model.UserTypeAdmin {
+		return nil, errors.New("cannot register as an admin")
 	}
 
 	// Create user
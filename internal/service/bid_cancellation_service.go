@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ========== BID CANCELLATION ==========
+
+// CancelBid voids a bid and rebuilds the item's bid state from the bids that
+// remain. The bidder's active maximum on the item is withdrawn as well, so the
+// proxy engine does not re-bid for them, and the other maximums then answer
+// the recomputed price. After close only the winning bid can be cancelled,
+// which voids its settlement and flags the lot for re-auction. The bidder is
+// told their bid was voided, and a leader displaced by the recomputation is
+// told they were outbid.
+func (s *auctionService) CancelBid(req CancelBidRequest) (*model.BidCancellation, error) {
+	bid, err := s.bidRepo.FindByID(req.BidID)
+	if err != nil {
+		return nil, errors.New("bid not found")
+	}
+
+	var cancellation *model.BidCancellation
+	err = s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		bidRepo := s.bidRepo.WithTx(tx)
+
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(bid.ItemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		closed := item.Status == model.AuctionStatusClosed
+		if !closed && item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return errors.New("bids can only be cancelled while the auction is running or after it closed")
+		}
+
+		// Re-read under the item lock; the bid may have changed meanwhile
+		bid, err = bidRepo.FindByID(req.BidID)
+		if err != nil {
+			return errors.New("bid not found")
+		}
+		if bid.BidStatus == model.BidStatusCancelled {
+			return errors.New("bid is already cancelled")
+		}
+		if closed && bid.BidStatus != model.BidStatusWon {
+			return errors.New("only the winning bid can be cancelled after close")
+		}
+
+		cancellation = &model.BidCancellation{
+			BidID:              bid.ID,
+			ItemID:             item.ID,
+			BidderID:           bid.UserID,
+			CancelledBy:        req.AdminID,
+			Reason:             req.Reason,
+			BidAmount:          bid.BidAmount,
+			PreviousHighestBid: item.CurrentHighestBid,
+		}
+		var previous *model.Bid
+		if leader, err := bidRepo.FindWinningBid(item.ID); err == nil {
+			previous = leader
+			cancellation.PreviousLeaderBidID = &leader.ID
+		}
+
+		if err := bidRepo.MarkCancelled(bid.ID); err != nil {
+			return err
+		}
+
+		if closed {
+			cancellation.PreviousLeaderBidID = &bid.ID
+			cancellation.NewHighestBid = item.CurrentHighestBid
+			if err := s.settlement.CancelSettlementTx(tx, item.ID, "winning bid cancelled: "+req.Reason, time.Now()); err != nil {
+				return err
+			}
+			s.publishCancellationTx(tx, item, cancellation)
+			if err := bidRepo.CreateCancellation(cancellation); err != nil {
+				return err
+			}
+			return s.notifications.NotifyBidCancelledTx(tx, item, cancellation)
+		}
+
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+		if proxy, err := proxyRepo.FindActiveByItemAndUser(item.ID, bid.UserID); err == nil {
+			if err := proxyRepo.UpdateStatus(proxy.ID, model.ProxyBidStatusWithdrawn); err != nil {
+				return err
+			}
+		}
+
+		if err := s.recomputeBidStateTx(tx, item); err != nil {
+			return err
+		}
+		if !isSealed(item) {
+			if err := s.resolveProxyBids(tx, item); err != nil {
+				return err
+			}
+		}
+		if err := s.syncBidHoldsTx(tx, item); err != nil {
+			return err
+		}
+
+		cancellation.NewHighestBid = item.CurrentHighestBid
+		if !isSealed(item) {
+			if leader, err := bidRepo.FindWinningBid(item.ID); err == nil {
+				cancellation.NewLeaderBidID = &leader.ID
+
+				// Maximums answering the lower price may overtake a leader
+				// other than the cancelled bidder
+				if previous != nil && previous.UserID != bid.UserID && previous.UserID != leader.UserID {
+					if err := s.notifications.NotifyOutbidTx(tx, item, previous); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		s.publishCancellationTx(tx, item, cancellation)
+		if err := bidRepo.CreateCancellation(cancellation); err != nil {
+			return err
+		}
+		return s.notifications.NotifyBidCancelledTx(tx, item, cancellation)
+	})
+	if err != nil {
+		if isRetryableTxError(err) {
+			return nil, ErrBidConflict
+		}
+		return nil, err
+	}
+
+	return cancellation, nil
+}
+
+func (s *auctionService) GetBidCancellations(itemID uint) ([]model.BidCancellation, error) {
+	return s.bidRepo.FindCancellationsByItemID(itemID)
+}
+
+// recomputeBidStateTx rebuilds CurrentHighestBid, BidCount and the leading bid
+// from the item's valid bids. The leader is the highest amount, earliest bid
+// on ties; it is restored to winning and every other bid left outbid. Sealed
+// lots only have their count refreshed. The caller must hold the item row lock.
+func (s *auctionService) recomputeBidStateTx(tx *gorm.DB, item *model.AuctionItem) error {
+	bidRepo := s.bidRepo.WithTx(tx)
+
+	bids, err := bidRepo.FindValidByItemID(item.ID)
+	if err != nil {
+		return err
+	}
+
+	highest := item.StartingPrice
+	if isSealed(item) {
+		highest = item.CurrentHighestBid
+	} else if len(bids) > 0 {
+		sort.SliceStable(bids, func(i, j int) bool {
+			if !bids[i].BidAmount.Equal(bids[j].BidAmount) {
+				return bids[i].BidAmount.GreaterThan(bids[j].BidAmount)
+			}
+			if !bids[i].BidTime.Equal(bids[j].BidTime) {
+				return bids[i].BidTime.Before(bids[j].BidTime)
+			}
+			return bids[i].ID < bids[j].ID
+		})
+
+		leader := bids[0]
+		if leader.BidStatus != model.BidStatusWinning || !leader.IsHighest {
+			leader.BidStatus = model.BidStatusWinning
+			leader.IsHighest = true
+			if err := bidRepo.Update(&leader); err != nil {
+				return err
+			}
+		}
+		if err := bidRepo.MarkAllAsOutbid(item.ID, leader.ID); err != nil {
+			return err
+		}
+		highest = leader.BidAmount
+	}
+
+	highestFloat, _ := highest.Float64()
+	if err := s.itemRepo.WithTx(tx).UpdateBidInfo(item.ID, highestFloat, len(bids)); err != nil {
+		return err
+	}
+
+	item.CurrentHighestBid = highest
+	item.BidCount = len(bids)
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ClockPrice is the asking price of a running descending-price lot
+type ClockPrice struct {
+	Price    decimal.Decimal `json:"price"`
+	NextDrop *time.Time      `json:"next_drop,omitempty"` // nil once the price rests at the limit
+}
+
+// ========== DESCENDING-PRICE BIDDING ==========
+
+// isDescending reports whether the item is sold by a falling clock price
+func isDescending(item *model.AuctionItem) bool {
+	return item.AuctionMethod == model.AuctionMethodDescending
+}
+
+// validateDescending checks the price clock of a descending-price lot
+func validateDescending(item *model.AuctionItem) error {
+	if !isDescending(item) {
+		return nil
+	}
+	if !item.DecrementAmount.IsPositive() || item.DecrementInterval <= 0 {
+		return errors.New("descending-price lots need a decrement amount and interval")
+	}
+	if !item.StartingPrice.GreaterThan(item.LimitPrice) {
+		return errors.New("descending-price lots must start above the limit price")
+	}
+	return nil
+}
+
+// AcceptClockPrice buys a descending-price lot at the current clock price.
+// The item row lock makes acceptance atomic: the first bidder closes the lot
+// and everyone queued behind them finds it no longer active.
+func (s *auctionService) AcceptClockPrice(req AcceptClockPriceRequest) (*model.Bid, error) {
+	if _, err := s.userRepo.FindByID(req.UserID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	var bid *model.Bid
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(req.ItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+		if !isDescending(item) {
+			return errors.New("auction item is not a descending-price lot")
+		}
+
+		now := time.Now()
+		if err := validateBiddable(item, now); err != nil {
+			return err
+		}
+		if err := s.participation.RequireEligibleTx(tx, item.ID, req.UserID); err != nil {
+			return err
+		}
+
+		price, _ := clockPriceAt(item, now)
+		bid, err = s.placeBidTx(tx, item, req.UserID, price, model.BidTypeManual, req.IPAddress, req.UserAgent)
+		if err != nil {
+			return err
+		}
+
+		// The price only ever falls to the limit, so acceptance always sells
+		if err := s.bidRepo.WithTx(tx).UpdateStatus(bid.ID, model.BidStatusWon); err != nil {
+			return err
+		}
+		bid.BidStatus = model.BidStatusWon
+		if err := s.settleBidHoldsTx(tx, item, bid); err != nil {
+			return err
+		}
+		return s.closeItemTx(tx, item, model.AuctionOutcomeSold, "", now)
+	})
+	if err != nil {
+		if isRetryableTxError(err) {
+			return nil, ErrBidConflict
+		}
+		return nil, err
+	}
+	return bid, nil
+}
+
+// CurrentClockPrice returns the asking price of a descending-price lot that is
+// published or running, and nil for any other lot
+func CurrentClockPrice(item *model.AuctionItem, now time.Time) *ClockPrice {
+	if !isDescending(item) {
+		return nil
+	}
+	if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+		return nil
+	}
+	price, next := clockPriceAt(item, now)
+	return &ClockPrice{Price: price, NextDrop: next}
+}
+
+// clockPriceAt steps the price down from the starting price once per interval
+// since AuctionStart, never below the limit price. It also returns when the
+// next drop happens, or nil if none will before the auction ends.
+func clockPriceAt(item *model.AuctionItem, now time.Time) (decimal.Decimal, *time.Time) {
+	if item.Schedule == nil || item.DecrementInterval <= 0 || !item.DecrementAmount.IsPositive() {
+		return item.StartingPrice, nil
+	}
+
+	interval := time.Duration(item.DecrementInterval) * time.Second
+	start := item.Schedule.AuctionStart
+	steps := int64(0)
+	if now.After(start) {
+		steps = int64(now.Sub(start) / interval)
+	}
+
+	price := item.StartingPrice.Sub(item.DecrementAmount.Mul(decimal.NewFromInt(steps)))
+	if !price.GreaterThan(item.LimitPrice) {
+		return item.LimitPrice, nil
+	}
+
+	next := start.Add(time.Duration(steps+1) * interval)
+	if !next.Before(item.Schedule.AuctionEnd) {
+		return price, nil
+	}
+	return price, &next
+}
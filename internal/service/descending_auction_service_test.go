@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClockPriceAt(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	item := &model.AuctionItem{
+		StartingPrice:     decimal.RequireFromString("1000000"),
+		LimitPrice:        decimal.RequireFromString("700000"),
+		DecrementAmount:   decimal.RequireFromString("100000"),
+		DecrementInterval: 600,
+		Schedule: &model.AuctionSchedule{
+			AuctionStart: start,
+			AuctionEnd:   start.Add(time.Hour),
+		},
+	}
+	at := func(d time.Duration) *time.Time {
+		t := start.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		want     string
+		wantNext *time.Time
+	}{
+		{"before the start", start.Add(-time.Minute), "1000000", at(10 * time.Minute)},
+		{"at the start", start, "1000000", at(10 * time.Minute)},
+		{"within the second interval", start.Add(15 * time.Minute), "900000", at(20 * time.Minute)},
+		{"on a drop", start.Add(20 * time.Minute), "800000", at(30 * time.Minute)},
+		{"floored at the limit price", start.Add(50 * time.Minute), "700000", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, next := clockPriceAt(item, tt.now)
+			if !price.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("price = %s, want %s", price, tt.want)
+			}
+			switch {
+			case tt.wantNext == nil && next != nil:
+				t.Errorf("next drop = %s, want none", next)
+			case tt.wantNext != nil && (next == nil || !next.Equal(*tt.wantNext)):
+				t.Errorf("next drop = %v, want %s", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestClockPriceAtNoDropAfterEnd(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	item := &model.AuctionItem{
+		StartingPrice:     decimal.RequireFromString("1000000"),
+		LimitPrice:        decimal.RequireFromString("100000"),
+		DecrementAmount:   decimal.RequireFromString("100000"),
+		DecrementInterval: 600,
+		Schedule: &model.AuctionSchedule{
+			AuctionStart: start,
+			AuctionEnd:   start.Add(25 * time.Minute),
+		},
+	}
+
+	price, next := clockPriceAt(item, start.Add(21*time.Minute))
+	if !price.Equal(decimal.RequireFromString("800000")) {
+		t.Errorf("price = %s, want 800000", price)
+	}
+	if next != nil {
+		t.Errorf("next drop = %s, want none after the auction ends", next)
+	}
+}
+
+func TestClockPriceAtWithoutDecrement(t *testing.T) {
+	item := &model.AuctionItem{StartingPrice: decimal.RequireFromString("1000000")}
+
+	price, next := clockPriceAt(item, time.Now())
+	if !price.Equal(item.StartingPrice) || next != nil {
+		t.Errorf("clockPriceAt = %s, %v; want the starting price and no drop", price, next)
+	}
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+// documentTimeFormat is how dates and times are printed on documents
+const documentTimeFormat = "02-01-2006 15:04:05"
+
+// documentFilename turns the document number into a safe file name
+func documentFilename(document *model.Document) string {
+	return strings.NewReplacer("/", "-", " ", "_").Replace(document.DocumentNumber)
+}
+
+// formatRupiah prints an amount the Indonesian way: Rp 1.250.000,00
+func formatRupiah(amount decimal.Decimal) string {
+	fixed := amount.Abs().StringFixed(2)
+	whole, frac := fixed[:len(fixed)-3], fixed[len(fixed)-2:]
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+
+	sign := ""
+	if amount.IsNegative() {
+		sign = "-"
+	}
+	return sign + "Rp " + b.String() + "," + frac
+}
+
+func formatDocumentTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Format(documentTimeFormat)
+}
+
+var documentFuncs = template.FuncMap{
+	"rupiah": formatRupiah,
+	"datetime": func(t time.Time) string {
+		return formatDocumentTime(&t)
+	},
+	"datetimePtr": formatDocumentTime,
+}
+
+// ========== AUCTION MINUTES ==========
+
+var minutesHTMLTemplate = template.Must(template.New("minutes").Funcs(documentFuncs).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Risalah Lelang {{.Minutes.DocumentNumber}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; margin: 32px; }
+h1 { font-size: 20px; text-align: center; margin-bottom: 0; }
+.number { text-align: center; margin-top: 4px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 16px; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; }
+th { background: #eee; }
+.fields td:first-child { width: 30%; font-weight: bold; }
+.hash { font-family: monospace; font-size: 11px; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>RISALAH LELANG</h1>
+<p class="number">Nomor: {{.Minutes.DocumentNumber}}{{if gt .Minutes.Revision 1}} (revisi {{.Minutes.Revision}}){{end}}</p>
+
+<h2>Objek Lelang</h2>
+<table class="fields">
+<tr><td>Kode Lot</td><td>{{.Minutes.Lot.LotCode}}</td></tr>
+<tr><td>Nama Barang</td><td>{{.Minutes.Lot.ItemName}}</td></tr>
+<tr><td>Jenis Barang</td><td>{{.Minutes.Lot.ItemType}}</td></tr>
+<tr><td>Kategori</td><td>{{.Minutes.Lot.Category}}</td></tr>
+{{if .Minutes.Lot.Description}}<tr><td>Uraian</td><td>{{.Minutes.Lot.Description}}</td></tr>{{end}}
+<tr><td>Metode Lelang</td><td>{{.Minutes.Lot.AuctionMethod}}</td></tr>
+<tr><td>Nilai Limit</td><td>{{rupiah .Minutes.Lot.LimitPrice}}</td></tr>
+<tr><td>Harga Awal</td><td>{{rupiah .Minutes.Lot.StartingPrice}}</td></tr>
+<tr><td>Uang Jaminan</td><td>{{rupiah .Minutes.Lot.DepositAmount}}</td></tr>
+</table>
+
+<h2>Para Pihak</h2>
+<table class="fields">
+<tr><td>Penjual</td><td>{{.Minutes.Seller.Name}} ({{.Minutes.Seller.Type}}){{if .Minutes.Seller.Address}}, {{.Minutes.Seller.Address}}{{end}}</td></tr>
+<tr><td>Penyelenggara</td><td>{{.Minutes.Organizer.Name}} ({{.Minutes.Organizer.Type}}){{if .Minutes.Organizer.Code}}, {{.Minutes.Organizer.Code}}{{end}}</td></tr>
+</table>
+
+<h2>Jadwal</h2>
+<table class="fields">
+<tr><td>Mulai</td><td>{{datetime .Minutes.Schedule.AuctionStart}}</td></tr>
+<tr><td>Selesai</td><td>{{datetime .Minutes.Schedule.AuctionEnd}}</td></tr>
+{{if .Minutes.Schedule.OriginalEnd}}<tr><td>Jadwal Selesai Semula</td><td>{{datetimePtr .Minutes.Schedule.OriginalEnd}} ({{.Minutes.Schedule.ExtensionCount}} kali perpanjangan)</td></tr>{{end}}
+<tr><td>Ditutup</td><td>{{datetimePtr .Minutes.ClosedAt}}</td></tr>
+</table>
+
+<h2>Daftar Penawaran</h2>
+<table>
+<tr><th>No</th><th>Waktu</th><th>NUP</th><th>Penawaran</th><th>Jenis</th><th>Status</th></tr>
+{{range .Minutes.Bids}}<tr><td>{{.Number}}</td><td>{{datetime .BidTime}}</td><td>{{.ParticipantNumber}}</td><td>{{rupiah .Amount}}</td><td>{{.BidType}}</td><td>{{.Status}}</td></tr>
+{{else}}<tr><td colspan="6">Tidak ada penawaran</td></tr>
+{{end}}</table>
+
+<h2>Hasil Lelang</h2>
+<table class="fields">
+<tr><td>Hasil</td><td>{{.Minutes.Outcome}}{{if .Minutes.OutcomeReason}} ({{.Minutes.OutcomeReason}}){{end}}</td></tr>
+{{if .Minutes.Winner}}<tr><td>Pemenang</td><td>{{.Minutes.Winner.Name}} (NUP {{.Minutes.Winner.ParticipantNumber}})</td></tr>
+<tr><td>Harga Lelang</td><td>{{rupiah .Minutes.FinalPrice}}</td></tr>{{end}}
+</table>
+
+<p>Diterbitkan {{datetime .Minutes.IssuedAt}}</p>
+<p class="hash">SHA-256: {{.Hash}}</p>
+</body>
+</html>
+`))
+
+func renderMinutesHTML(minutes *AuctionMinutes, hash string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := minutesHTMLTemplate.Execute(&buf, struct {
+		Minutes *AuctionMinutes
+		Hash    string
+	}{minutes, hash})
+	return buf.Bytes(), err
+}
+
+func renderMinutesPDF(minutes *AuctionMinutes, hash string) ([]byte, error) {
+	doc := newDocumentPDF("Risalah Lelang "+minutes.DocumentNumber, hash)
+
+	doc.title("RISALAH LELANG")
+	number := "Nomor: " + minutes.DocumentNumber
+	if minutes.Revision > 1 {
+		number += " (revisi " + strconv.Itoa(minutes.Revision) + ")"
+	}
+	doc.centered(number)
+
+	doc.heading("Objek Lelang")
+	doc.field("Kode Lot", minutes.Lot.LotCode)
+	doc.field("Nama Barang", minutes.Lot.ItemName)
+	doc.field("Jenis Barang", minutes.Lot.ItemType)
+	doc.field("Kategori", minutes.Lot.Category)
+	if minutes.Lot.Description != "" {
+		doc.field("Uraian", minutes.Lot.Description)
+	}
+	doc.field("Metode Lelang", minutes.Lot.AuctionMethod)
+	doc.field("Nilai Limit", formatRupiah(minutes.Lot.LimitPrice))
+	doc.field("Harga Awal", formatRupiah(minutes.Lot.StartingPrice))
+	doc.field("Uang Jaminan", formatRupiah(minutes.Lot.DepositAmount))
+
+	doc.heading("Para Pihak")
+	doc.field("Penjual", minutes.Seller.Name+" ("+minutes.Seller.Type+")")
+	doc.field("Penyelenggara", minutes.Organizer.Name+" ("+minutes.Organizer.Type+")")
+
+	doc.heading("Jadwal")
+	doc.field("Mulai", formatDocumentTime(&minutes.Schedule.AuctionStart))
+	doc.field("Selesai", formatDocumentTime(&minutes.Schedule.AuctionEnd))
+	if minutes.Schedule.OriginalEnd != nil {
+		doc.field("Jadwal Selesai Semula", formatDocumentTime(minutes.Schedule.OriginalEnd)+
+			" ("+strconv.Itoa(minutes.Schedule.ExtensionCount)+" kali perpanjangan)")
+	}
+	doc.field("Ditutup", formatDocumentTime(minutes.ClosedAt))
+
+	doc.heading("Daftar Penawaran")
+	widths := []float64{10, 40, 35, 45, 25, 25}
+	rows := make([][]string, 0, len(minutes.Bids))
+	for _, bid := range minutes.Bids {
+		rows = append(rows, []string{
+			strconv.Itoa(bid.Number),
+			formatDocumentTime(&bid.BidTime),
+			bid.ParticipantNumber,
+			formatRupiah(bid.Amount),
+			bid.BidType,
+			bid.Status,
+		})
+	}
+	if len(rows) == 0 {
+		doc.text("Tidak ada penawaran")
+	} else {
+		doc.table([]string{"No", "Waktu", "NUP", "Penawaran", "Jenis", "Status"}, widths, rows)
+	}
+
+	doc.heading("Hasil Lelang")
+	outcome := minutes.Outcome
+	if minutes.OutcomeReason != "" {
+		outcome += " (" + minutes.OutcomeReason + ")"
+	}
+	doc.field("Hasil", outcome)
+	if minutes.Winner != nil && minutes.FinalPrice != nil {
+		doc.field("Pemenang", minutes.Winner.Name+" (NUP "+minutes.Winner.ParticipantNumber+")")
+		doc.field("Harga Lelang", formatRupiah(*minutes.FinalPrice))
+	}
+
+	doc.text("")
+	doc.text("Diterbitkan " + formatDocumentTime(&minutes.IssuedAt))
+	return doc.output()
+}
+
+// ========== INVOICES & RECEIPTS ==========
+
+func renderInvoicePDF(invoice *Invoice, hash string) ([]byte, error) {
+	doc := newDocumentPDF("Tagihan "+invoice.DocumentNumber, hash)
+
+	doc.title("TAGIHAN")
+	doc.centered("Nomor: " + invoice.DocumentNumber)
+
+	doc.heading("Penerbit")
+	doc.party(invoice.Issuer)
+
+	doc.heading("Ditagihkan Kepada")
+	doc.party(invoice.BilledTo)
+
+	doc.heading("Rincian")
+	doc.field("Lot", invoice.LotCode+" - "+invoice.ItemName)
+	doc.field("Tanggal Terbit", formatDocumentTime(&invoice.IssuedAt))
+	doc.field("Jatuh Tempo", formatDocumentTime(&invoice.DueDate))
+	doc.amounts(invoice.Lines, []DocumentLine{
+		{Description: "Jumlah", Amount: invoice.Total},
+		{Description: "Dikurangi uang jaminan", Amount: invoice.DepositCredit.Neg()},
+		{Description: "Jumlah yang harus dibayar", Amount: invoice.AmountDue},
+	})
+	return doc.output()
+}
+
+func renderReceiptPDF(receipt *Receipt, hash string) ([]byte, error) {
+	doc := newDocumentPDF("Kuitansi "+receipt.DocumentNumber, hash)
+
+	doc.title("KUITANSI")
+	doc.centered("Nomor: " + receipt.DocumentNumber)
+
+	doc.heading("Penerbit")
+	doc.party(receipt.Issuer)
+
+	doc.heading("Telah Diterima Dari")
+	doc.party(receipt.ReceivedFrom)
+
+	doc.heading("Untuk Pembayaran")
+	doc.field("Uraian", receipt.Description)
+	doc.field("Referensi", receipt.Reference)
+	doc.field("Tanggal Bayar", formatDocumentTime(&receipt.PaidAt))
+	doc.amounts(receipt.Lines, []DocumentLine{{Description: "Jumlah", Amount: receipt.Total}})
+
+	if len(receipt.Payments) > 0 {
+		doc.heading("Cara Pembayaran")
+		doc.amounts(receipt.Payments, nil)
+	}
+
+	doc.text("")
+	doc.text("Diterbitkan " + formatDocumentTime(&receipt.IssuedAt))
+	return doc.output()
+}
+
+// ========== PDF LAYOUT ==========
+
+// documentPDF wraps fpdf with the few building blocks official documents use.
+// Every page footer carries the content hash.
+type documentPDF struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func newDocumentPDF(title, hash string) *documentPDF {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(0, 4, tr("SHA-256: "+hash), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 4, tr("Halaman "+strconv.Itoa(pdf.PageNo())), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	return &documentPDF{pdf: pdf, tr: tr}
+}
+
+func (d *documentPDF) title(text string) {
+	d.pdf.SetFont("Helvetica", "B", 16)
+	d.pdf.CellFormat(0, 9, d.tr(text), "", 1, "C", false, 0, "")
+}
+
+func (d *documentPDF) centered(text string) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.CellFormat(0, 6, d.tr(text), "", 1, "C", false, 0, "")
+}
+
+func (d *documentPDF) heading(text string) {
+	d.pdf.Ln(3)
+	d.pdf.SetFont("Helvetica", "B", 12)
+	d.pdf.CellFormat(0, 8, d.tr(text), "B", 1, "L", false, 0, "")
+	d.pdf.Ln(1)
+}
+
+func (d *documentPDF) field(label, value string) {
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.CellFormat(50, 6, d.tr(label), "", 0, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(0, 6, d.tr(value), "", "L", false)
+}
+
+func (d *documentPDF) party(party DocumentParty) {
+	d.field("Nama", party.Name)
+	if party.Address != "" {
+		d.field("Alamat", party.Address)
+	}
+	if party.Email != "" {
+		d.field("Email", party.Email)
+	}
+}
+
+// amounts prints a two-column list of amounts followed by bold totals
+func (d *documentPDF) amounts(lines, totals []DocumentLine) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	for _, line := range lines {
+		d.pdf.CellFormat(130, 7, d.tr(line.Description), "1", 0, "L", false, 0, "")
+		d.pdf.CellFormat(50, 7, d.tr(formatRupiah(line.Amount)), "1", 1, "R", false, 0, "")
+	}
+	d.pdf.SetFont("Helvetica", "B", 10)
+	for _, total := range totals {
+		d.pdf.CellFormat(130, 7, d.tr(total.Description), "1", 0, "R", false, 0, "")
+		d.pdf.CellFormat(50, 7, d.tr(formatRupiah(total.Amount)), "1", 1, "R", false, 0, "")
+	}
+}
+
+func (d *documentPDF) text(value string) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(0, 6, d.tr(value), "", "L", false)
+}
+
+func (d *documentPDF) table(header []string, widths []float64, rows [][]string) {
+	d.pdf.SetFont("Helvetica", "B", 9)
+	d.pdf.SetFillColor(235, 235, 235)
+	for i, h := range header {
+		d.pdf.CellFormat(widths[i], 7, d.tr(h), "1", 0, "L", true, 0, "")
+	}
+	d.pdf.Ln(-1)
+
+	d.pdf.SetFont("Helvetica", "", 9)
+	for _, row := range rows {
+		for i, cell := range row {
+			d.pdf.CellFormat(widths[i], 6, d.tr(cell), "1", 0, "L", false, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+}
+
+func (d *documentPDF) output() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,849 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+	"yourapp/internal/util"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	DocumentFormatPDF  = "pdf"
+	DocumentFormatHTML = "html"
+)
+
+// ErrDocumentForbidden is returned when a user may not download a document
+var ErrDocumentForbidden = errors.New("you are not allowed to access this document")
+
+// DocumentService issues official documents and renders them to PDF or HTML.
+// Every revision stores the snapshot it was rendered from, so downloads of
+// the same revision are identical and carry the same content hash.
+type DocumentService interface {
+	IssueAuctionMinutes(itemID uint, adminID string) (*model.Document, error)
+	GetAuctionMinutes(itemID uint, format string) (*RenderedDocument, error)
+	GetAuctionMinutesForUser(itemID uint, userID, format string) (*RenderedDocument, error)
+	GetAuctionMinutesRevisions(itemID uint) ([]model.Document, error)
+
+	IssueInvoiceTx(tx *gorm.DB, settlement *model.Settlement) (*model.Document, error)
+	IssueSettlementReceiptTx(tx *gorm.DB, settlement *model.Settlement) (*model.Document, error)
+	IssueTopUpReceiptTx(tx *gorm.DB, order *model.TopUpOrder) (*model.Document, error)
+	GetMyDocuments(userID string) ([]model.Document, error)
+	GetMyDocument(id uint, userID string) (*RenderedDocument, error)
+	GetDocument(id uint) (*RenderedDocument, error)
+	EmailPendingDocuments() (int, error)
+	AttachRabbitMQ(rabbitMQ *util.RabbitMQClient)
+}
+
+// RenderedDocument is a document ready to be sent as a download
+type RenderedDocument struct {
+	Document    *model.Document
+	Filename    string
+	ContentType string
+	Body        []byte
+}
+
+// AuctionMinutes is the content of a Risalah Lelang
+type AuctionMinutes struct {
+	DocumentNumber string           `json:"document_number"`
+	Revision       int              `json:"revision"`
+	IssuedAt       time.Time        `json:"issued_at"`
+	Lot            MinutesLot       `json:"lot"`
+	Seller         DocumentParty    `json:"seller"`
+	Organizer      DocumentParty    `json:"organizer"`
+	Schedule       MinutesSchedule  `json:"schedule"`
+	Bids           []MinutesBid     `json:"bids"`
+	Outcome        string           `json:"outcome"`
+	OutcomeReason  string           `json:"outcome_reason,omitempty"`
+	ClosedAt       *time.Time       `json:"closed_at,omitempty"`
+	Winner         *MinutesWinner   `json:"winner,omitempty"`
+	FinalPrice     *decimal.Decimal `json:"final_price,omitempty"`
+}
+
+type MinutesLot struct {
+	LotCode       string          `json:"lot_code"`
+	ItemName      string          `json:"item_name"`
+	ItemType      string          `json:"item_type"`
+	Category      string          `json:"category"`
+	Description   string          `json:"description,omitempty"`
+	AuctionMethod string          `json:"auction_method"`
+	LimitPrice    decimal.Decimal `json:"limit_price"`
+	StartingPrice decimal.Decimal `json:"starting_price"`
+	DepositAmount decimal.Decimal `json:"deposit_amount"`
+}
+
+type DocumentParty struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Address string `json:"address,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+type MinutesSchedule struct {
+	AuctionStart   time.Time  `json:"auction_start"`
+	AuctionEnd     time.Time  `json:"auction_end"`
+	OriginalEnd    *time.Time `json:"original_end,omitempty"`
+	ExtensionCount int        `json:"extension_count"`
+}
+
+type MinutesBid struct {
+	Number            int             `json:"number"`
+	ParticipantNumber string          `json:"participant_number"`
+	Amount            decimal.Decimal `json:"amount"`
+	BidTime           time.Time       `json:"bid_time"`
+	BidType           string          `json:"bid_type"`
+	Status            string          `json:"status"`
+}
+
+type MinutesWinner struct {
+	ParticipantNumber string `json:"participant_number"`
+	Name              string `json:"name"`
+}
+
+// Invoice is the winner's bill for a lot: the hammer price plus the buyer's
+// fees and taxes, less the deposit already held
+type Invoice struct {
+	DocumentNumber string          `json:"document_number"`
+	IssuedAt       time.Time       `json:"issued_at"`
+	DueDate        time.Time       `json:"due_date"`
+	SettlementID   uint            `json:"settlement_id"`
+	Issuer         DocumentParty   `json:"issuer"`
+	BilledTo       DocumentParty   `json:"billed_to"`
+	LotCode        string          `json:"lot_code"`
+	ItemName       string          `json:"item_name"`
+	Lines          []DocumentLine  `json:"lines"`
+	Total          decimal.Decimal `json:"total"`
+	DepositCredit  decimal.Decimal `json:"deposit_credit"`
+	AmountDue      decimal.Decimal `json:"amount_due"`
+}
+
+// Receipt (kuitansi) acknowledges money received from a user
+type Receipt struct {
+	DocumentNumber string          `json:"document_number"`
+	IssuedAt       time.Time       `json:"issued_at"`
+	PaidAt         time.Time       `json:"paid_at"`
+	Reference      string          `json:"reference"` // invoice number or top-up order code
+	Issuer         DocumentParty   `json:"issuer"`
+	ReceivedFrom   DocumentParty   `json:"received_from"`
+	Description    string          `json:"description"`
+	Lines          []DocumentLine  `json:"lines"`
+	Total          decimal.Decimal `json:"total"`
+	Payments       []DocumentLine  `json:"payments"`
+}
+
+type DocumentLine struct {
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+type documentService struct {
+	transactor      repository.Transactor
+	documentRepo    repository.DocumentRepository
+	itemRepo        repository.AuctionItemRepository
+	bidRepo         repository.BidRepository
+	participantRepo repository.ParticipantRepository
+	settlementRepo  repository.SettlementRepository
+	tenderRepo      repository.TenderRepository
+	userRepo        repository.UserRepository
+	notifications   NotificationService
+	platformName    string
+
+	rabbitMu sync.RWMutex
+	rabbitMQ *util.RabbitMQClient
+}
+
+func NewDocumentService(
+	transactor repository.Transactor,
+	documentRepo repository.DocumentRepository,
+	itemRepo repository.AuctionItemRepository,
+	bidRepo repository.BidRepository,
+	participantRepo repository.ParticipantRepository,
+	settlementRepo repository.SettlementRepository,
+	tenderRepo repository.TenderRepository,
+	userRepo repository.UserRepository,
+	rabbitMQ *util.RabbitMQClient,
+	notifications NotificationService,
+	platformName string,
+) DocumentService {
+	return &documentService{
+		transactor:      transactor,
+		documentRepo:    documentRepo,
+		itemRepo:        itemRepo,
+		bidRepo:         bidRepo,
+		participantRepo: participantRepo,
+		settlementRepo:  settlementRepo,
+		tenderRepo:      tenderRepo,
+		userRepo:        userRepo,
+		rabbitMQ:        rabbitMQ,
+		notifications:   notifications,
+		platformName:    platformName,
+	}
+}
+
+// ========== AUCTION MINUTES ==========
+
+// IssueAuctionMinutes issues a new revision of the item's minutes, e.g.
+// after a winning bid was cancelled and the result changed
+func (s *documentService) IssueAuctionMinutes(itemID uint, adminID string) (*model.Document, error) {
+	return s.issueMinutes(itemID, &adminID, false)
+}
+
+// GetAuctionMinutes renders the current minutes, issuing the first revision
+// on the first request after close
+func (s *documentService) GetAuctionMinutes(itemID uint, format string) (*RenderedDocument, error) {
+	document, err := s.issueMinutes(itemID, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return renderMinutes(document, format)
+}
+
+// GetAuctionMinutesForUser renders the minutes for the lot's winner or seller.
+// Winners include a first winner who later defaulted and the winner of a
+// tender, neither of whom holds the lot's current settlement.
+func (s *documentService) GetAuctionMinutesForUser(itemID uint, userID, format string) (*RenderedDocument, error) {
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("auction item not found")
+	}
+
+	if !s.isWinner(item, userID) && !s.isSeller(item, userID) {
+		return nil, ErrDocumentForbidden
+	}
+
+	return s.GetAuctionMinutes(itemID, format)
+}
+
+func (s *documentService) isWinner(item *model.AuctionItem, userID string) bool {
+	if settlement, err := s.settlementRepo.FindByItemID(item.ID); err == nil && settlement.WinnerID == userID {
+		return true
+	}
+	if bids, err := s.bidRepo.FindByItemAndUser(item.ID, userID); err == nil {
+		for _, bid := range bids {
+			if bid.BidStatus == model.BidStatusWon {
+				return true
+			}
+		}
+	}
+	if item.AuctionMethod == model.AuctionMethodTender {
+		tender, err := s.tenderRepo.FindByItemID(item.ID)
+		if err != nil {
+			return false
+		}
+		offer, err := s.tenderRepo.FindOfferByUser(tender.ID, userID)
+		return err == nil && offer.Status == model.TenderOfferStatusWinner
+	}
+	return false
+}
+
+func (s *documentService) isSeller(item *model.AuctionItem, userID string) bool {
+	if item.Seller == nil || item.Seller.Email == nil {
+		return false
+	}
+	user, err := s.userRepo.FindByID(userID)
+	return err == nil && user.Email == *item.Seller.Email
+}
+
+func (s *documentService) GetAuctionMinutesRevisions(itemID uint) ([]model.Document, error) {
+	return s.documentRepo.FindByItem(model.DocumentTypeAuctionMinutes, itemID)
+}
+
+// issueMinutes snapshots the item under its row lock, which also serialises
+// revision numbers. With onlyIfMissing an existing revision is returned as is.
+func (s *documentService) issueMinutes(itemID uint, issuedBy *string, onlyIfMissing bool) (*model.Document, error) {
+	var document *model.Document
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+		if _, err := itemRepo.FindByIDForUpdate(itemID); err != nil {
+			return errors.New("auction item not found")
+		}
+
+		documentRepo := s.documentRepo.WithTx(tx)
+		revision := 1
+		latest, err := documentRepo.FindLatest(model.DocumentTypeAuctionMinutes, itemID)
+		switch {
+		case err == nil:
+			if onlyIfMissing {
+				document = latest
+				return nil
+			}
+			revision = latest.Revision + 1
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		item, err := itemRepo.FindByID(itemID)
+		if err != nil {
+			return err
+		}
+		if item.Status != model.AuctionStatusClosed {
+			return errors.New("auction minutes are available once the auction has closed")
+		}
+
+		minutes, err := s.buildMinutesTx(tx, item)
+		if err != nil {
+			return err
+		}
+		minutes.Revision = revision
+		minutes.IssuedAt = time.Now()
+		minutes.DocumentNumber = fmt.Sprintf("RL/%s/%d", item.LotCode, minutes.IssuedAt.Year())
+		if revision > 1 {
+			minutes.DocumentNumber = fmt.Sprintf("%s/R%d", minutes.DocumentNumber, revision)
+		}
+
+		content, err := json.Marshal(minutes)
+		if err != nil {
+			return err
+		}
+		document = &model.Document{
+			DocumentType:   model.DocumentTypeAuctionMinutes,
+			DocumentNumber: minutes.DocumentNumber,
+			ItemID:         &itemID,
+			Revision:       revision,
+			ContentHash:    contentHash(content),
+			Content:        string(content),
+			IssuedBy:       issuedBy,
+			IssuedAt:       minutes.IssuedAt,
+		}
+		return documentRepo.Create(document)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func (s *documentService) buildMinutesTx(tx *gorm.DB, item *model.AuctionItem) (*AuctionMinutes, error) {
+	minutes := &AuctionMinutes{
+		Lot: MinutesLot{
+			LotCode:       item.LotCode,
+			ItemName:      item.ItemName,
+			ItemType:      string(item.ItemType),
+			Description:   derefString(item.Description),
+			AuctionMethod: string(item.AuctionMethod),
+			LimitPrice:    item.LimitPrice,
+			StartingPrice: item.StartingPrice,
+			DepositAmount: item.DepositAmount,
+		},
+		Bids:          []MinutesBid{},
+		OutcomeReason: derefString(item.OutcomeReason),
+		ClosedAt:      item.ClosedAt,
+	}
+	if item.Category != nil {
+		minutes.Lot.Category = item.Category.CategoryName
+	}
+	if item.Seller != nil {
+		minutes.Seller = DocumentParty{
+			Name:    item.Seller.SellerName,
+			Type:    string(item.Seller.SellerType),
+			Address: derefString(item.Seller.Address),
+		}
+	}
+	if item.Organizer != nil {
+		minutes.Organizer = DocumentParty{
+			Name:    item.Organizer.OrganizerName,
+			Type:    string(item.Organizer.OrganizerType),
+			Code:    derefString(item.Organizer.OrganizerCode),
+			Address: derefString(item.Organizer.Address),
+		}
+	}
+	if item.Schedule != nil {
+		minutes.Schedule = MinutesSchedule{
+			AuctionStart:   item.Schedule.AuctionStart,
+			AuctionEnd:     item.Schedule.AuctionEnd,
+			OriginalEnd:    item.Schedule.OriginalEnd,
+			ExtensionCount: item.Schedule.ExtensionCount,
+		}
+	}
+	if item.Outcome != nil {
+		minutes.Outcome = string(*item.Outcome)
+	}
+
+	participants, err := s.participantRepo.WithTx(tx).FindByItemID(item.ID)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make(map[string]string, len(participants))
+	for _, p := range participants {
+		if p.NUP != nil {
+			numbers[p.UserID] = *p.NUP
+		}
+	}
+
+	bids, err := s.bidRepo.WithTx(tx).FindByItemID(item.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bids, func(i, j int) bool {
+		if !bids[i].BidTime.Equal(bids[j].BidTime) {
+			return bids[i].BidTime.Before(bids[j].BidTime)
+		}
+		return bids[i].ID < bids[j].ID
+	})
+	for i, bid := range bids {
+		minutes.Bids = append(minutes.Bids, MinutesBid{
+			Number:            i + 1,
+			ParticipantNumber: participantNumber(numbers, bid.UserID),
+			Amount:            bid.BidAmount,
+			BidTime:           bid.BidTime,
+			BidType:           string(bid.BidType),
+			Status:            string(bid.BidStatus),
+		})
+	}
+
+	// The current settlement names the buyer, including a second-chance buyer
+	settlement, err := s.settlementRepo.WithTx(tx).FindByItemID(item.ID)
+	switch {
+	case err == nil:
+		if settlement.Status == model.SettlementStatusPending || settlement.Status == model.SettlementStatusPaid {
+			winner := &MinutesWinner{ParticipantNumber: participantNumber(numbers, settlement.WinnerID)}
+			if user, err := s.userRepo.FindByID(settlement.WinnerID); err == nil {
+				winner.Name = user.FullName
+			}
+			price := settlement.BidAmount
+			minutes.Winner = winner
+			minutes.FinalPrice = &price
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	return minutes, nil
+}
+
+// ========== INVOICES & RECEIPTS ==========
+
+// documentMailBatchSize bounds how many documents one mailer run sends
+const documentMailBatchSize = 50
+
+// IssueInvoiceTx bills the settlement's winner. It runs in the transaction
+// that opens the settlement, so a settlement never exists without its invoice.
+func (s *documentService) IssueInvoiceTx(tx *gorm.DB, settlement *model.Settlement) (*model.Document, error) {
+	sourceKey := fmt.Sprintf("invoice:settlement:%d", settlement.ID)
+	return s.issueOnceTx(tx, sourceKey, func(now time.Time) (*model.Document, error) {
+		item, err := s.itemRepo.WithTx(tx).FindByID(settlement.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		billedTo, err := s.userPartyTx(tx, settlement.WinnerID)
+		if err != nil {
+			return nil, err
+		}
+		issuer, series := organizerParty(item)
+
+		number, err := s.documentRepo.WithTx(tx).NextSequence(fmt.Sprintf("invoice:%s:%d", series, now.Year()))
+		if err != nil {
+			return nil, err
+		}
+		lines, err := s.settlementLinesTx(tx, settlement)
+		if err != nil {
+			return nil, err
+		}
+		invoice := &Invoice{
+			DocumentNumber: fmt.Sprintf("INV/%s/%d/%06d", series, now.Year(), number),
+			IssuedAt:       now,
+			DueDate:        settlement.PaymentDeadline,
+			SettlementID:   settlement.ID,
+			Issuer:         issuer,
+			BilledTo:       billedTo,
+			LotCode:        item.LotCode,
+			ItemName:       item.ItemName,
+			Lines:          lines,
+			Total:          settlement.BidAmount.Add(settlement.BuyerCharges),
+			DepositCredit:  settlement.DepositCredit,
+			AmountDue:      settlement.OutstandingAmount,
+		}
+		return newOwnedDocument(model.DocumentTypeInvoice, invoice.DocumentNumber, &item.ID, settlement.WinnerID, invoice, now)
+	})
+}
+
+// IssueSettlementReceiptTx acknowledges the winner's payment for a lot, with
+// the deposit and wallet parts listed separately
+func (s *documentService) IssueSettlementReceiptTx(tx *gorm.DB, settlement *model.Settlement) (*model.Document, error) {
+	sourceKey := fmt.Sprintf("receipt:settlement:%d", settlement.ID)
+	return s.issueOnceTx(tx, sourceKey, func(now time.Time) (*model.Document, error) {
+		item, err := s.itemRepo.WithTx(tx).FindByID(settlement.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		receivedFrom, err := s.userPartyTx(tx, settlement.WinnerID)
+		if err != nil {
+			return nil, err
+		}
+		issuer, series := organizerParty(item)
+
+		number, err := s.documentRepo.WithTx(tx).NextSequence(fmt.Sprintf("receipt:%s:%d", series, now.Year()))
+		if err != nil {
+			return nil, err
+		}
+		lines, err := s.settlementLinesTx(tx, settlement)
+		if err != nil {
+			return nil, err
+		}
+
+		// The invoice is issued with the settlement; older settlements may
+		// not have one
+		reference := fmt.Sprintf("settlement #%d", settlement.ID)
+		if invoice, err := s.documentRepo.WithTx(tx).FindBySourceKey(fmt.Sprintf("invoice:settlement:%d", settlement.ID)); err == nil {
+			reference = invoice.DocumentNumber
+		}
+
+		receipt := &Receipt{
+			DocumentNumber: fmt.Sprintf("KW/%s/%d/%06d", series, now.Year(), number),
+			IssuedAt:       now,
+			PaidAt:         now,
+			Reference:      reference,
+			Issuer:         issuer,
+			ReceivedFrom:   receivedFrom,
+			Description:    fmt.Sprintf("Pelunasan lot %s - %s", item.LotCode, item.ItemName),
+			Lines:          lines,
+			Total:          settlement.BidAmount.Add(settlement.BuyerCharges),
+			Payments:       []DocumentLine{},
+		}
+		if settlement.PaidAt != nil {
+			receipt.PaidAt = *settlement.PaidAt
+		}
+		if settlement.DepositCredit.IsPositive() {
+			receipt.Payments = append(receipt.Payments, DocumentLine{Description: "Uang jaminan", Amount: settlement.DepositCredit})
+		}
+		if settlement.OutstandingAmount.IsPositive() {
+			receipt.Payments = append(receipt.Payments, DocumentLine{Description: "Saldo dompet", Amount: settlement.OutstandingAmount})
+		}
+		return newOwnedDocument(model.DocumentTypeReceipt, receipt.DocumentNumber, &item.ID, settlement.WinnerID, receipt, now)
+	})
+}
+
+// IssueTopUpReceiptTx acknowledges a paid wallet top-up. The platform, not an
+// organizer, received the money, so these run in their own series.
+func (s *documentService) IssueTopUpReceiptTx(tx *gorm.DB, order *model.TopUpOrder) (*model.Document, error) {
+	sourceKey := "receipt:topup:" + order.OrderCode
+	return s.issueOnceTx(tx, sourceKey, func(now time.Time) (*model.Document, error) {
+		receivedFrom, err := s.userPartyTx(tx, order.UserID)
+		if err != nil {
+			return nil, err
+		}
+		number, err := s.documentRepo.WithTx(tx).NextSequence(fmt.Sprintf("receipt:TOPUP:%d", now.Year()))
+		if err != nil {
+			return nil, err
+		}
+
+		description := fmt.Sprintf("Top up saldo melalui virtual account %s", order.Bank)
+		if order.VANumber != nil {
+			description += " " + *order.VANumber
+		}
+		receipt := &Receipt{
+			DocumentNumber: fmt.Sprintf("KW/TOPUP/%d/%06d", now.Year(), number),
+			IssuedAt:       now,
+			PaidAt:         now,
+			Reference:      order.OrderCode,
+			Issuer:         DocumentParty{Name: s.platformName, Type: "platform"},
+			ReceivedFrom:   receivedFrom,
+			Description:    description,
+			Lines:          []DocumentLine{{Description: "Top up saldo", Amount: order.Amount}},
+			Total:          order.Amount,
+			Payments:       []DocumentLine{{Description: "Virtual account " + order.Bank, Amount: order.Amount}},
+		}
+		if order.PaidAt != nil {
+			receipt.PaidAt = *order.PaidAt
+		}
+		return newOwnedDocument(model.DocumentTypeReceipt, receipt.DocumentNumber, nil, order.UserID, receipt, now)
+	})
+}
+
+func (s *documentService) GetMyDocuments(userID string) ([]model.Document, error) {
+	return s.documentRepo.FindByOwner(userID)
+}
+
+// GetMyDocument renders one of the user's own invoices or receipts
+func (s *documentService) GetMyDocument(id uint, userID string) (*RenderedDocument, error) {
+	document, err := s.documentRepo.FindByID(id)
+	if err != nil || document.OwnerID == nil || *document.OwnerID != userID {
+		return nil, errors.New("document not found")
+	}
+	return renderOwnedDocument(document)
+}
+
+// GetDocument renders any invoice or receipt for an admin
+func (s *documentService) GetDocument(id uint) (*RenderedDocument, error) {
+	document, err := s.documentRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("document not found")
+	}
+	if document.DocumentType == model.DocumentTypeAuctionMinutes {
+		return renderMinutes(document, DocumentFormatPDF)
+	}
+	return renderOwnedDocument(document)
+}
+
+// AttachRabbitMQ starts emailing documents, e.g. once a connection that
+// failed at startup comes up
+func (s *documentService) AttachRabbitMQ(rabbitMQ *util.RabbitMQClient) {
+	s.rabbitMu.Lock()
+	s.rabbitMQ = rabbitMQ
+	s.rabbitMu.Unlock()
+}
+
+func (s *documentService) mailQueue() *util.RabbitMQClient {
+	s.rabbitMu.RLock()
+	defer s.rabbitMu.RUnlock()
+	return s.rabbitMQ
+}
+
+// EmailPendingDocuments queues a copy of every newly issued invoice and
+// receipt to its owner. Issuing only records the document, so a failed or
+// slow mail server never rolls back a payment; this job retries until the
+// message is queued.
+func (s *documentService) EmailPendingDocuments() (int, error) {
+	rabbitMQ := s.mailQueue()
+	if rabbitMQ == nil {
+		return 0, nil
+	}
+	pending, err := s.documentRepo.FindUnemailed(documentMailBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, candidate := range pending {
+		id := candidate.ID
+		err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			documentRepo := s.documentRepo.WithTx(tx)
+			document, err := documentRepo.FindByIDForUpdateSkipLocked(id)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if document.EmailedAt != nil || document.OwnerID == nil {
+				return nil
+			}
+
+			owner, err := s.userRepo.WithTx(tx).FindByID(*document.OwnerID)
+			if err != nil {
+				return err
+			}
+			rendered, err := renderOwnedDocument(document)
+			if err != nil {
+				return err
+			}
+
+			subject, body := documentEmailText(document)
+			if err := rabbitMQ.PublishEmail(util.EmailMessage{
+				To:      owner.Email,
+				Subject: subject,
+				Body:    body,
+				Type:    "document",
+				Attachments: []util.EmailAttachment{{
+					Filename:    rendered.Filename,
+					ContentType: rendered.ContentType,
+					Content:     rendered.Body,
+				}},
+			}); err != nil {
+				return err
+			}
+			if err := documentRepo.MarkEmailed(document.ID, time.Now()); err != nil {
+				return err
+			}
+			sent++
+			return nil
+		})
+		if err != nil {
+			log.Printf("Documents: failed to email document #%d: %v", id, err)
+		}
+	}
+	return sent, nil
+}
+
+// issueOnceTx returns the document already issued for sourceKey, or builds
+// and stores a new one. The source is locked by the caller's transaction, so
+// the unique source key only guards against programming errors.
+func (s *documentService) issueOnceTx(tx *gorm.DB, sourceKey string, build func(now time.Time) (*model.Document, error)) (*model.Document, error) {
+	documentRepo := s.documentRepo.WithTx(tx)
+	existing, err := documentRepo.FindBySourceKey(sourceKey)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	document, err := build(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	document.SourceKey = &sourceKey
+	if err := documentRepo.Create(document); err != nil {
+		return nil, err
+	}
+	if err := s.notifications.NotifyDocumentIssuedTx(tx, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// settlementLinesTx lists the hammer price and the buyer's frozen charges
+func (s *documentService) settlementLinesTx(tx *gorm.DB, settlement *model.Settlement) ([]DocumentLine, error) {
+	charges, err := s.settlementRepo.WithTx(tx).FindCharges(settlement.ID)
+	if err != nil {
+		return nil, err
+	}
+	lines := []DocumentLine{{Description: "Harga lelang", Amount: settlement.BidAmount}}
+	for _, charge := range charges {
+		if charge.Party != model.FeePartyBuyer {
+			continue
+		}
+		description := charge.Name
+		if charge.Rate.IsPositive() {
+			description = fmt.Sprintf("%s (%s%%)", charge.Name, charge.Rate.String())
+		}
+		lines = append(lines, DocumentLine{Description: description, Amount: charge.Amount})
+	}
+	return lines, nil
+}
+
+func (s *documentService) userPartyTx(tx *gorm.DB, userID string) (DocumentParty, error) {
+	user, err := s.userRepo.WithTx(tx).FindByID(userID)
+	if err != nil {
+		return DocumentParty{}, err
+	}
+	return DocumentParty{
+		Name:    user.FullName,
+		Type:    "user",
+		Address: derefString(user.Address),
+		Email:   user.Email,
+	}, nil
+}
+
+// organizerParty returns the organizer issuing the lot's invoices and the
+// code its number series runs under
+func organizerParty(item *model.AuctionItem) (DocumentParty, string) {
+	if item.Organizer == nil {
+		return DocumentParty{Type: "organizer"}, fmt.Sprintf("ORG%d", item.OrganizerID)
+	}
+	series := derefString(item.Organizer.OrganizerCode)
+	if series == "" {
+		series = fmt.Sprintf("ORG%d", item.Organizer.ID)
+	}
+	return DocumentParty{
+		Name:    item.Organizer.OrganizerName,
+		Type:    string(item.Organizer.OrganizerType),
+		Code:    derefString(item.Organizer.OrganizerCode),
+		Address: derefString(item.Organizer.Address),
+		Email:   derefString(item.Organizer.Email),
+	}, series
+}
+
+func newOwnedDocument(documentType model.DocumentType, number string, itemID *uint, ownerID string, content interface{}, now time.Time) (*model.Document, error) {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Document{
+		DocumentType:   documentType,
+		DocumentNumber: number,
+		ItemID:         itemID,
+		OwnerID:        &ownerID,
+		Revision:       1,
+		ContentHash:    contentHash(raw),
+		Content:        string(raw),
+		IssuedAt:       now,
+	}, nil
+}
+
+// renderOwnedDocument renders an invoice or receipt; both are PDF only
+func renderOwnedDocument(document *model.Document) (*RenderedDocument, error) {
+	var body []byte
+	var err error
+	switch document.DocumentType {
+	case model.DocumentTypeInvoice:
+		var invoice Invoice
+		if err := json.Unmarshal([]byte(document.Content), &invoice); err != nil {
+			return nil, err
+		}
+		body, err = renderInvoicePDF(&invoice, document.ContentHash)
+	case model.DocumentTypeReceipt:
+		var receipt Receipt
+		if err := json.Unmarshal([]byte(document.Content), &receipt); err != nil {
+			return nil, err
+		}
+		body, err = renderReceiptPDF(&receipt, document.ContentHash)
+	default:
+		return nil, fmt.Errorf("unsupported document type %q", document.DocumentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedDocument{
+		Document:    document,
+		Filename:    documentFilename(document) + ".pdf",
+		ContentType: "application/pdf",
+		Body:        body,
+	}, nil
+}
+
+func documentEmailText(document *model.Document) (string, string) {
+	if document.DocumentType == model.DocumentTypeInvoice {
+		return "Tagihan " + document.DocumentNumber,
+			"Terlampir tagihan " + document.DocumentNumber + " untuk lot yang Anda menangkan.\nMohon lakukan pembayaran sebelum batas waktu yang tertera."
+	}
+	return "Kuitansi " + document.DocumentNumber,
+		"Terlampir kuitansi " + document.DocumentNumber + " sebagai bukti pembayaran Anda.\nTerima kasih."
+}
+
+func renderMinutes(document *model.Document, format string) (*RenderedDocument, error) {
+	var minutes AuctionMinutes
+	if err := json.Unmarshal([]byte(document.Content), &minutes); err != nil {
+		return nil, err
+	}
+
+	rendered := &RenderedDocument{Document: document}
+	filename := documentFilename(document)
+	var err error
+	switch format {
+	case "", DocumentFormatPDF:
+		rendered.Body, err = renderMinutesPDF(&minutes, document.ContentHash)
+		rendered.Filename = filename + ".pdf"
+		rendered.ContentType = "application/pdf"
+	case DocumentFormatHTML:
+		rendered.Body, err = renderMinutesHTML(&minutes, document.ContentHash)
+		rendered.Filename = filename + ".html"
+		rendered.ContentType = "text/html; charset=utf-8"
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func participantNumber(numbers map[string]string, userID string) string {
+	if nup, ok := numbers[userID]; ok {
+		return nup
+	}
+	return "-"
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"encoding/base64"
 	"fmt"
+	"html"
 	"net/smtp"
+	"net/url"
 	"strings"
 	"time"
 
 	"yourapp/internal/config"
+	"yourapp/internal/util"
 )
 
 // EmailService mendefinisikan antarmuka untuk layanan pengiriman email.
@@ -15,6 +19,8 @@ type EmailService interface {
 	SendResetPasswordEmail(to, resetLink string) error
 	SendVerificationEmail(to, token string) error
 	SendWelcomeEmail(to, name string) error
+	SendDocumentEmail(to, subject, body string, attachments []util.EmailAttachment) error
+	SendAuctionEmail(to, notificationType string, data util.AuctionEmail) error
 }
 
 type emailService struct {
@@ -35,9 +41,18 @@ func (s *emailService) sendEmail(to, subject, body string) error {
 
 // sendEmailHTML mengirim email multipart dengan versi HTML dan plain text.
 func (s *emailService) sendEmailHTML(to, subject, htmlBody, textBody string) error {
+	return s.sendEmailWithAttachments(to, subject, htmlBody, textBody, nil)
+}
+
+// sendEmailWithAttachments mengirim email HTML dan plain text beserta lampiran.
+// Tanpa lampiran pesannya sama dengan sendEmailHTML (multipart/alternative).
+func (s *emailService) sendEmailWithAttachments(to, subject, htmlBody, textBody string, attachments []util.EmailAttachment) error {
 	if s.config.SMTPUsername == "" || s.config.SMTPPassword == "" {
 		// In development, just log the email
 		fmt.Printf("[EMAIL] To: %s, Subject: %s\nBody: %s\n", to, subject, textBody)
+		for _, a := range attachments {
+			fmt.Printf("[EMAIL] Attachment: %s (%s, %d bytes)\n", a.Filename, a.ContentType, len(a.Content))
+		}
 		return nil
 	}
 
@@ -70,6 +85,9 @@ func (s *emailService) sendEmailHTML(to, subject, htmlBody, textBody string) err
 	endBoundary := fmt.Sprintf("--%s--\r\n", boundary)
 
 	msg := []byte(headers + textPart + htmlPart + endBoundary)
+	if len(attachments) > 0 {
+		msg = buildMixedMessage(fromHeader, to, subject, textPart+htmlPart+endBoundary, boundary, attachments)
+	}
 	addr := fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort)
 
 	err := smtp.SendMail(addr, auth, from, []string{to}, msg)
@@ -672,3 +690,168 @@ Tim %s
 
 	return s.sendEmailHTML(to, subject, htmlBody, textBody)
 }
+
+// SendDocumentEmail mengirim dokumen resmi (invoice, kuitansi) sebagai lampiran.
+// Body berisi teks pesan; versi HTML dibuat dari teks tersebut.
+func (s *emailService) SendDocumentEmail(to, subject, body string, attachments []util.EmailAttachment) error {
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="id">
+<head>
+    <meta charset="UTF-8">
+</head>
+<body style="font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; color: #1f2937; font-size: 15px; line-height: 1.7;">
+    <p>%s</p>
+    <p>Terima kasih,<br>Tim %s</p>
+</body>
+</html>`, strings.ReplaceAll(html.EscapeString(body), "\n", "<br>"), s.config.EmailName)
+
+	textBody := fmt.Sprintf("%s\n\nTerima kasih,\nTim %s\n", body, s.config.EmailName)
+
+	return s.sendEmailWithAttachments(to, subject, htmlBody, textBody, attachments)
+}
+
+// SendAuctionEmail mengirim notifikasi lelang (dilampaui, segera berakhir,
+// menang, kalah, dibatalkan, pencarian tersimpan) dengan tautan ke halaman
+// lot, atau ke daftar lelang untuk ringkasan harian. Notifikasi pencarian
+// tersimpan menyertakan tautan berhenti berlangganan.
+func (s *emailService) SendAuctionEmail(to, notificationType string, data util.AuctionEmail) error {
+	subject, paragraphs, err := auctionEmailText(notificationType, data)
+	if err != nil {
+		return err
+	}
+	link := s.config.ClientURL + "/auctions"
+	if data.ItemID != 0 {
+		link = fmt.Sprintf("%s/auctions/%d", s.config.ClientURL, data.ItemID)
+	}
+	if data.UnsubscribeToken != "" {
+		unsubscribe := fmt.Sprintf("%s/saved-searches/unsubscribe?token=%s", s.config.ClientURL, url.QueryEscape(data.UnsubscribeToken))
+		paragraphs = append(paragraphs, fmt.Sprintf("Anda menerima email ini karena menyimpan pencarian \"%s\". Untuk berhenti berlangganan, buka %s", data.SearchName, unsubscribe))
+	}
+
+	var htmlParagraphs, textParagraphs strings.Builder
+	for _, p := range paragraphs {
+		htmlParagraphs.WriteString("    <p>" + html.EscapeString(p) + "</p>\n")
+		textParagraphs.WriteString(p + "\n\n")
+	}
+
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="id">
+<head>
+    <meta charset="UTF-8">
+</head>
+<body style="font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; color: #1f2937; font-size: 15px; line-height: 1.7;">
+    <p>Yth. %s,</p>
+%s    <p><a href="%s" style="display: inline-block; padding: 10px 20px; background-color: #1e3a8a; color: #ffffff; text-decoration: none; border-radius: 4px;">Lihat Lot</a></p>
+    <p>Terima kasih,<br>Tim %s</p>
+</body>
+</html>`, html.EscapeString(data.Name), htmlParagraphs.String(), html.EscapeString(link), s.config.EmailName)
+
+	textBody := fmt.Sprintf("Yth. %s,\n\n%sLihat lot: %s\n\nTerima kasih,\nTim %s\n",
+		data.Name, textParagraphs.String(), link, s.config.EmailName)
+
+	return s.sendEmailHTML(to, subject, htmlBody, textBody)
+}
+
+// auctionEmailText menyusun subjek dan isi notifikasi lelang
+func auctionEmailText(notificationType string, data util.AuctionEmail) (string, []string, error) {
+	lot := fmt.Sprintf("%s (%s)", data.ItemName, data.LotCode)
+	start, end := "-", "-"
+	if data.AuctionStart != nil {
+		start = data.AuctionStart.Format("02-01-2006 15:04")
+	}
+	if data.AuctionEnd != nil {
+		end = data.AuctionEnd.Format("02-01-2006 15:04")
+	}
+
+	switch notificationType {
+	case "outbid":
+		return "Penawaran Anda telah dilampaui - " + data.ItemName, []string{
+			fmt.Sprintf("Penawaran Anda untuk lot %s telah dilampaui oleh peserta lain. Penawaran tertinggi saat ini %s.", lot, data.Amount),
+			fmt.Sprintf("Lelang berakhir pada %s. Ajukan penawaran baru sebelum lelang ditutup.", end),
+		}, nil
+	case "ending_soon":
+		return "Lelang segera berakhir - " + data.ItemName, []string{
+			fmt.Sprintf("Lelang lot %s yang Anda ikuti akan berakhir dalam 1 jam, pada %s.", lot, end),
+			fmt.Sprintf("Penawaran tertinggi saat ini %s.", data.Amount),
+		}, nil
+	case "won":
+		return "Selamat, Anda memenangkan lelang - " + data.ItemName, []string{
+			fmt.Sprintf("Anda memenangkan lot %s dengan penawaran %s.", lot, data.Amount),
+			"Tagihan pelunasan akan kami kirimkan melalui email terpisah. Mohon selesaikan pembayaran sebelum batas waktu.",
+		}, nil
+	case "lost":
+		paragraphs := []string{fmt.Sprintf("Lelang lot %s telah ditutup dan penawaran Anda tidak menang.", lot)}
+		if data.Reason != "" {
+			paragraphs = append(paragraphs, data.Reason)
+		}
+		paragraphs = append(paragraphs, "Dana penawaran dan uang jaminan Anda telah dikembalikan ke saldo.")
+		return "Hasil lelang - " + data.ItemName, paragraphs, nil
+	case "auction_cancelled":
+		return "Lelang dibatalkan - " + data.ItemName, []string{
+			fmt.Sprintf("Lelang lot %s dibatalkan oleh penyelenggara. Alasan: %s.", lot, data.Reason),
+			"Dana penawaran dan uang jaminan Anda telah dikembalikan ke saldo.",
+		}, nil
+	case "bid_cancelled":
+		return "Penawaran dibatalkan - " + data.ItemName, []string{
+			fmt.Sprintf("Penawaran Anda sebesar %s untuk lot %s dibatalkan oleh penyelenggara. Alasan: %s.", data.Amount, lot, data.Reason),
+			"Apabila penawaran tersebut merupakan penawaran pemenang, kemenangan Anda beserta tagihan pelunasannya turut dibatalkan.",
+		}, nil
+	case "watch_starting":
+		return "Lelang segera dimulai - " + data.ItemName, []string{
+			fmt.Sprintf("Lot %s yang Anda pantau akan mulai dilelang pada %s.", lot, start),
+			"Pastikan Anda telah mendaftar dan membayar uang jaminan agar dapat mengajukan penawaran.",
+		}, nil
+	case "watch_ending":
+		paragraphs := []string{fmt.Sprintf("Lelang lot %s yang Anda pantau akan berakhir pada %s.", lot, end)}
+		if data.Amount != "" {
+			paragraphs = append(paragraphs, fmt.Sprintf("Penawaran tertinggi saat ini %s.", data.Amount))
+		}
+		return "Lelang yang Anda pantau segera berakhir - " + data.ItemName, paragraphs, nil
+	case "saved_search_match":
+		paragraphs := []string{fmt.Sprintf("Lot baru %s sesuai dengan pencarian tersimpan \"%s\" dan mulai dilelang pada %s.", lot, data.SearchName, start)}
+		if data.Amount != "" {
+			paragraphs = append(paragraphs, fmt.Sprintf("Harga awal %s.", data.Amount))
+		}
+		return "Lot baru sesuai pencarian Anda - " + data.ItemName, paragraphs, nil
+	case "saved_search_digest":
+		paragraphs := []string{fmt.Sprintf("Berikut %d lot baru yang sesuai dengan pencarian tersimpan \"%s\":", len(data.Lots), data.SearchName)}
+		for _, l := range data.Lots {
+			lotStart := "-"
+			if l.AuctionStart != nil {
+				lotStart = l.AuctionStart.Format("02-01-2006 15:04")
+			}
+			paragraphs = append(paragraphs, fmt.Sprintf("- %s (%s), mulai %s", l.ItemName, l.LotCode, lotStart))
+		}
+		return "Ringkasan lot baru - " + data.SearchName, paragraphs, nil
+	}
+	return "", nil, fmt.Errorf("unknown auction notification type %q", notificationType)
+}
+
+// buildMixedMessage membungkus bagian alternative (teks dan HTML) dalam
+// multipart/mixed dan menambahkan lampiran dengan encoding base64.
+func buildMixedMessage(fromHeader, to, subject, alternativeParts, alternativeBoundary string, attachments []util.EmailAttachment) []byte {
+	boundary := "----=_MixedPart_" + fmt.Sprintf("%d", time.Now().UnixNano())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=\"%s\"\r\n\r\n",
+		fromHeader, to, subject, boundary)
+
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n%s\r\n",
+		boundary, alternativeBoundary, alternativeParts)
+
+	for _, a := range attachments {
+		fmt.Fprintf(&b, "--%s\r\nContent-Type: %s; name=\"%s\"\r\nContent-Transfer-Encoding: base64\r\nContent-Disposition: attachment; filename=\"%s\"\r\n\r\n",
+			boundary, a.ContentType, a.Filename, a.Filename)
+
+		// RFC 2045: base64 lines are at most 76 characters
+		encoded := base64.StdEncoding.EncodeToString(a.Content)
+		for len(encoded) > 76 {
+			b.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		b.WriteString(encoded + "\r\n")
+	}
+
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"yourapp/internal/util"
 
@@ -76,6 +77,14 @@ func (w *EmailWorker) processEmailMessage(msg amqp.Delivery) error {
 		return w.emailService.SendVerificationEmail(emailMsg.To, emailMsg.Body)
 	case "welcome":
 		return w.emailService.SendWelcomeEmail(emailMsg.To, emailMsg.Subject) // Using Subject as name
+	case "document":
+		return w.emailService.SendDocumentEmail(emailMsg.To, emailMsg.Subject, emailMsg.Body, emailMsg.Attachments)
+	case "outbid", "ending_soon", "won", "lost", "auction_cancelled", "bid_cancelled", "watch_starting", "watch_ending",
+		"saved_search_match", "saved_search_digest":
+		if emailMsg.Auction == nil {
+			return fmt.Errorf("auction email %s without auction details", emailMsg.Type)
+		}
+		return w.emailService.SendAuctionEmail(emailMsg.To, emailMsg.Type, *emailMsg.Auction)
 	default:
 		// Generic email
 		return w.emailService.SendOTPEmail(emailMsg.To, emailMsg.Body)
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// FeeService keeps the fee schedule and prices a hammer price for an item:
+// the duties, premium and taxes on top of the bid that the buyer owes and
+// the ones deducted from what the seller receives
+type FeeService interface {
+	Quote(itemID uint, amount decimal.Decimal) (*FeeQuote, error)
+	QuoteTx(tx *gorm.DB, item *model.AuctionItem, amount decimal.Decimal) (*FeeQuote, error)
+
+	CreateRule(req FeeRuleRequest) (*model.FeeRule, error)
+	UpdateRule(id uint, req FeeRuleRequest) (*model.FeeRule, error)
+	GetRules() ([]model.FeeRule, error)
+	DeleteRule(id uint) error
+	SeedDefaultRules() (int, error)
+}
+
+type FeeRuleRequest struct {
+	Code          string               `json:"code" binding:"required"`
+	Name          string               `json:"name" binding:"required"`
+	Party         model.FeeParty       `json:"party" binding:"required"`
+	Kind          model.FeeKind        `json:"kind" binding:"required"`
+	ItemType      *model.ItemType      `json:"item_type"`
+	OrganizerType *model.OrganizerType `json:"organizer_type"`
+	CategoryID    *uint                `json:"category_id"`
+	Rate          float64              `json:"rate"` // percent
+	FixedAmount   float64              `json:"fixed_amount"`
+	ExemptAmount  float64              `json:"exempt_amount"`
+	MinAmount     float64              `json:"min_amount"`
+	MaxAmount     *float64             `json:"max_amount"`
+	IsActive      *bool                `json:"is_active"`
+}
+
+// FeeLine is one charge of a quote
+type FeeLine struct {
+	RuleID uint            `json:"rule_id"`
+	Code   string          `json:"code"`
+	Name   string          `json:"name"`
+	Party  model.FeeParty  `json:"party"`
+	Kind   model.FeeKind   `json:"kind"`
+	Rate   decimal.Decimal `json:"rate"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// FeeQuote breaks a hammer price down into what the buyer pays in total and
+// what the seller nets
+type FeeQuote struct {
+	ItemID             uint            `json:"item_id"`
+	HammerPrice        decimal.Decimal `json:"hammer_price"`
+	BuyerCharges       []FeeLine       `json:"buyer_charges"`
+	BuyerChargesTotal  decimal.Decimal `json:"buyer_charges_total"`
+	BuyerTotal         decimal.Decimal `json:"buyer_total"`
+	SellerCharges      []FeeLine       `json:"seller_charges"`
+	SellerChargesTotal decimal.Decimal `json:"seller_charges_total"`
+	SellerNet          decimal.Decimal `json:"seller_net"`
+}
+
+type feeService struct {
+	feeRuleRepo   repository.FeeRuleRepository
+	itemRepo      repository.AuctionItemRepository
+	organizerRepo repository.OrganizerRepository
+	categoryRepo  repository.CategoryRepository
+}
+
+func NewFeeService(
+	feeRuleRepo repository.FeeRuleRepository,
+	itemRepo repository.AuctionItemRepository,
+	organizerRepo repository.OrganizerRepository,
+	categoryRepo repository.CategoryRepository,
+) FeeService {
+	return &feeService{
+		feeRuleRepo:   feeRuleRepo,
+		itemRepo:      itemRepo,
+		organizerRepo: organizerRepo,
+		categoryRepo:  categoryRepo,
+	}
+}
+
+// ========== QUOTES ==========
+
+func (s *feeService) Quote(itemID uint, amount decimal.Decimal) (*FeeQuote, error) {
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be positive")
+	}
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("auction item not found")
+	}
+	if item.Status == model.AuctionStatusDraft {
+		return nil, errors.New("auction item not found")
+	}
+	return s.quote(s.feeRuleRepo, item, amount)
+}
+
+// QuoteTx prices amount inside the caller's transaction, for settlement
+func (s *feeService) QuoteTx(tx *gorm.DB, item *model.AuctionItem, amount decimal.Decimal) (*FeeQuote, error) {
+	return s.quote(s.feeRuleRepo.WithTx(tx), item, amount)
+}
+
+func (s *feeService) quote(feeRuleRepo repository.FeeRuleRepository, item *model.AuctionItem, amount decimal.Decimal) (*FeeQuote, error) {
+	organizer := item.Organizer
+	if organizer == nil {
+		var err error
+		organizer, err = s.organizerRepo.FindByID(item.OrganizerID)
+		if err != nil {
+			return nil, errors.New("organizer not found")
+		}
+	}
+
+	var parentCategoryID *uint
+	if category, err := s.categoryRepo.FindByID(item.CategoryID); err == nil {
+		parentCategoryID = category.ParentCategoryID
+	}
+
+	rules, err := feeRuleRepo.FindActive()
+	if err != nil {
+		return nil, err
+	}
+	return quoteRules(rules, item, organizer.OrganizerType, parentCategoryID, amount), nil
+}
+
+// quoteRules prices amount with the active rules that apply to the item
+func quoteRules(rules []model.FeeRule, item *model.AuctionItem, organizerType model.OrganizerType, parentCategoryID *uint, amount decimal.Decimal) *FeeQuote {
+	// Keep the most specific matching rule per code; ties go to the older rule
+	best := make(map[string]*model.FeeRule)
+	bestScore := make(map[string]int)
+	for i := range rules {
+		rule := &rules[i]
+		score, ok := matchFeeRule(rule, item, organizerType, parentCategoryID)
+		if !ok {
+			continue
+		}
+		if current, seen := bestScore[rule.Code]; !seen || score > current {
+			best[rule.Code] = rule
+			bestScore[rule.Code] = score
+		}
+	}
+
+	codes := make([]string, 0, len(best))
+	for code := range best {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	quote := &FeeQuote{
+		ItemID:             item.ID,
+		HammerPrice:        amount,
+		BuyerCharges:       []FeeLine{},
+		BuyerChargesTotal:  decimal.Zero,
+		SellerCharges:      []FeeLine{},
+		SellerChargesTotal: decimal.Zero,
+	}
+	for _, code := range codes {
+		rule := best[code]
+		charge := feeRuleCharge(rule, amount)
+		if !charge.IsPositive() {
+			continue
+		}
+		line := FeeLine{
+			RuleID: rule.ID,
+			Code:   rule.Code,
+			Name:   rule.Name,
+			Party:  rule.Party,
+			Kind:   rule.Kind,
+			Rate:   rule.Rate,
+			Amount: charge,
+		}
+		if rule.Party == model.FeePartyBuyer {
+			quote.BuyerCharges = append(quote.BuyerCharges, line)
+			quote.BuyerChargesTotal = quote.BuyerChargesTotal.Add(charge)
+		} else {
+			quote.SellerCharges = append(quote.SellerCharges, line)
+			quote.SellerChargesTotal = quote.SellerChargesTotal.Add(charge)
+		}
+	}
+	quote.BuyerTotal = amount.Add(quote.BuyerChargesTotal)
+	quote.SellerNet = amount.Sub(quote.SellerChargesTotal)
+	return quote
+}
+
+// matchFeeRule reports whether the rule applies to the item and how specific
+// it is. A category match outweighs an organizer type match, which outweighs
+// an item type match.
+func matchFeeRule(rule *model.FeeRule, item *model.AuctionItem, organizerType model.OrganizerType, parentCategoryID *uint) (int, bool) {
+	score := 0
+	if rule.CategoryID != nil {
+		switch {
+		case *rule.CategoryID == item.CategoryID:
+			score += 8
+		case parentCategoryID != nil && *rule.CategoryID == *parentCategoryID:
+			score += 4
+		default:
+			return 0, false
+		}
+	}
+	if rule.OrganizerType != nil {
+		if *rule.OrganizerType != organizerType {
+			return 0, false
+		}
+		score += 2
+	}
+	if rule.ItemType != nil {
+		if *rule.ItemType != item.ItemType {
+			return 0, false
+		}
+		score++
+	}
+	return score, true
+}
+
+// feeRuleCharge applies the rule to the hammer price, rounded to the sen
+func feeRuleCharge(rule *model.FeeRule, amount decimal.Decimal) decimal.Decimal {
+	base := amount.Sub(rule.ExemptAmount)
+	if base.IsNegative() {
+		base = decimal.Zero
+	}
+
+	charge := base.Mul(rule.Rate).Div(hundred).Add(rule.FixedAmount)
+	if !charge.IsPositive() {
+		return decimal.Zero
+	}
+	if charge.LessThan(rule.MinAmount) {
+		charge = rule.MinAmount
+	}
+	if rule.MaxAmount != nil && charge.GreaterThan(*rule.MaxAmount) {
+		charge = *rule.MaxAmount
+	}
+	return charge.Round(2)
+}
+
+// ========== FEE SCHEDULE ==========
+
+func (s *feeService) CreateRule(req FeeRuleRequest) (*model.FeeRule, error) {
+	rule := &model.FeeRule{IsActive: true}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.feeRuleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *feeService) UpdateRule(id uint, req FeeRuleRequest) (*model.FeeRule, error) {
+	rule, err := s.feeRuleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("fee rule not found")
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.feeRuleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *feeService) GetRules() ([]model.FeeRule, error) {
+	return s.feeRuleRepo.FindAll()
+}
+
+func (s *feeService) DeleteRule(id uint) error {
+	if _, err := s.feeRuleRepo.FindByID(id); err != nil {
+		return errors.New("fee rule not found")
+	}
+	return s.feeRuleRepo.Delete(id)
+}
+
+func (s *feeService) applyRuleRequest(rule *model.FeeRule, req FeeRuleRequest) error {
+	switch req.Party {
+	case model.FeePartyBuyer, model.FeePartySeller:
+	default:
+		return errors.New("party must be buyer or seller")
+	}
+	switch req.Kind {
+	case model.FeeKindDuty, model.FeeKindPremium, model.FeeKindTax:
+	default:
+		return errors.New("kind must be duty, premium or tax")
+	}
+	if req.ItemType != nil && *req.ItemType != model.ItemTypeMovable && *req.ItemType != model.ItemTypeImmovable {
+		return errors.New("invalid item_type")
+	}
+	if req.OrganizerType != nil {
+		switch *req.OrganizerType {
+		case model.OrganizerTypeKPKNL, model.OrganizerTypeBank, model.OrganizerTypePrivate:
+		default:
+			return errors.New("invalid organizer_type")
+		}
+	}
+	if req.CategoryID != nil {
+		if _, err := s.categoryRepo.FindByID(*req.CategoryID); err != nil {
+			return errors.New("category not found")
+		}
+	}
+	if req.Rate < 0 || req.Rate > 100 {
+		return errors.New("rate must be a percentage between 0 and 100")
+	}
+	if req.FixedAmount < 0 || req.ExemptAmount < 0 || req.MinAmount < 0 {
+		return errors.New("amounts cannot be negative")
+	}
+	if req.MaxAmount != nil && *req.MaxAmount < req.MinAmount {
+		return errors.New("max_amount cannot be below min_amount")
+	}
+
+	rule.Code = req.Code
+	rule.Name = req.Name
+	rule.Party = req.Party
+	rule.Kind = req.Kind
+	rule.ItemType = req.ItemType
+	rule.OrganizerType = req.OrganizerType
+	rule.CategoryID = req.CategoryID
+	rule.Rate = decimal.NewFromFloat(req.Rate)
+	rule.FixedAmount = decimal.NewFromFloat(req.FixedAmount)
+	rule.ExemptAmount = decimal.NewFromFloat(req.ExemptAmount)
+	rule.MinAmount = decimal.NewFromFloat(req.MinAmount)
+	rule.MaxAmount = nil
+	if req.MaxAmount != nil {
+		max := decimal.NewFromFloat(*req.MaxAmount)
+		rule.MaxAmount = &max
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// SeedDefaultRules installs a starting schedule the first time the service
+// runs. The rates follow the usual KPKNL schedule and the standard PPh and
+// BPHTB rates; admins adjust them per region and organizer afterwards.
+func (s *feeService) SeedDefaultRules() (int, error) {
+	count, err := s.feeRuleRepo.Count()
+	if err != nil || count > 0 {
+		return 0, err
+	}
+
+	movable := model.ItemTypeMovable
+	immovable := model.ItemTypeImmovable
+	private := model.OrganizerTypePrivate
+	defaults := []model.FeeRule{
+		{Code: "buyer_duty", Name: "Bea Lelang Pembeli", Party: model.FeePartyBuyer, Kind: model.FeeKindDuty, ItemType: &movable, Rate: decimal.NewFromInt(3)},
+		{Code: "buyer_duty", Name: "Bea Lelang Pembeli", Party: model.FeePartyBuyer, Kind: model.FeeKindDuty, ItemType: &immovable, Rate: decimal.NewFromInt(2)},
+		{Code: "seller_duty", Name: "Bea Lelang Penjual", Party: model.FeePartySeller, Kind: model.FeeKindDuty, Rate: decimal.NewFromInt(1)},
+		{Code: "buyer_premium", Name: "Buyer's Premium", Party: model.FeePartyBuyer, Kind: model.FeeKindPremium, OrganizerType: &private, Rate: decimal.NewFromInt(2)},
+		{Code: "pph", Name: "PPh Final Pengalihan Hak atas Tanah/Bangunan", Party: model.FeePartySeller, Kind: model.FeeKindTax, ItemType: &immovable, Rate: decimal.RequireFromString("2.5")},
+		{Code: "bphtb", Name: "BPHTB", Party: model.FeePartyBuyer, Kind: model.FeeKindTax, ItemType: &immovable, Rate: decimal.NewFromInt(5), ExemptAmount: decimal.NewFromInt(80000000)},
+	}
+	for i := range defaults {
+		defaults[i].IsActive = true
+		if err := s.feeRuleRepo.Create(&defaults[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(defaults), nil
+}
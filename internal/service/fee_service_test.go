@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestQuoteRulesMatching(t *testing.T) {
+	categoryID, parentID, otherID := uint(5), uint(1), uint(9)
+	immovable := model.ItemTypeImmovable
+	bank := model.OrganizerTypeBank
+	private := model.OrganizerTypePrivate
+	item := &model.AuctionItem{ID: 3, CategoryID: categoryID, ItemType: model.ItemTypeImmovable}
+
+	rules := []model.FeeRule{
+		{ID: 1, Code: "duty", Party: model.FeePartyBuyer, Rate: decimal.RequireFromString("2")},
+		{ID: 2, Code: "duty", Party: model.FeePartyBuyer, Rate: decimal.RequireFromString("3"), ItemType: &immovable},
+		{ID: 3, Code: "duty", Party: model.FeePartyBuyer, Rate: decimal.RequireFromString("4"), OrganizerType: &bank},
+		{ID: 4, Code: "duty", Party: model.FeePartyBuyer, Rate: decimal.RequireFromString("5"), CategoryID: &parentID},
+		{ID: 5, Code: "duty", Party: model.FeePartyBuyer, Rate: decimal.RequireFromString("6"), CategoryID: &otherID},
+		{ID: 6, Code: "seller_duty", Party: model.FeePartySeller, Rate: decimal.RequireFromString("1"), OrganizerType: &private},
+		{ID: 7, Code: "seller_duty", Party: model.FeePartySeller, Rate: decimal.RequireFromString("0.5")},
+		{ID: 8, Code: "seller_duty", Party: model.FeePartySeller, Rate: decimal.RequireFromString("0.75")},
+	}
+
+	quote := quoteRules(rules, item, model.OrganizerTypeBank, &parentID, decimal.RequireFromString("1000000"))
+
+	// The parent category outweighs organizer and item type; the other
+	// category doesn't apply at all
+	if len(quote.BuyerCharges) != 1 || quote.BuyerCharges[0].RuleID != 4 {
+		t.Fatalf("buyer charges = %+v, want rule 4 only", quote.BuyerCharges)
+	}
+	// The private-organizer rule doesn't apply; ties go to the older rule
+	if len(quote.SellerCharges) != 1 || quote.SellerCharges[0].RuleID != 7 {
+		t.Fatalf("seller charges = %+v, want rule 7 only", quote.SellerCharges)
+	}
+
+	assertDecimal(t, "buyer charges total", quote.BuyerChargesTotal, "50000")
+	assertDecimal(t, "buyer total", quote.BuyerTotal, "1050000")
+	assertDecimal(t, "seller charges total", quote.SellerChargesTotal, "5000")
+	assertDecimal(t, "seller net", quote.SellerNet, "995000")
+}
+
+func TestMatchFeeRuleSpecificity(t *testing.T) {
+	categoryID, parentID := uint(5), uint(1)
+	movable := model.ItemTypeMovable
+	immovable := model.ItemTypeImmovable
+	kpknl := model.OrganizerTypeKPKNL
+	item := &model.AuctionItem{CategoryID: categoryID, ItemType: model.ItemTypeImmovable}
+
+	tests := []struct {
+		name      string
+		rule      model.FeeRule
+		wantScore int
+		wantOK    bool
+	}{
+		{"catch-all", model.FeeRule{}, 0, true},
+		{"item type", model.FeeRule{ItemType: &immovable}, 1, true},
+		{"other item type", model.FeeRule{ItemType: &movable}, 0, false},
+		{"organizer type", model.FeeRule{OrganizerType: &kpknl}, 2, true},
+		{"parent category", model.FeeRule{CategoryID: &parentID}, 4, true},
+		{"category", model.FeeRule{CategoryID: &categoryID}, 8, true},
+		{"category and item type", model.FeeRule{CategoryID: &categoryID, ItemType: &immovable}, 9, true},
+		{"category with other item type", model.FeeRule{CategoryID: &categoryID, ItemType: &movable}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, ok := matchFeeRule(&tt.rule, item, model.OrganizerTypeKPKNL, &parentID)
+			if ok != tt.wantOK || (ok && score != tt.wantScore) {
+				t.Errorf("matchFeeRule = %d, %v; want %d, %v", score, ok, tt.wantScore, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFeeRuleCharge(t *testing.T) {
+	max := decimal.RequireFromString("30000")
+
+	tests := []struct {
+		name   string
+		rule   model.FeeRule
+		amount string
+		want   string
+	}{
+		{"percentage", model.FeeRule{Rate: decimal.RequireFromString("2.5")}, "1000000", "25000"},
+		{"rounded to the sen", model.FeeRule{Rate: decimal.RequireFromString("1")}, "1234.56", "12.35"},
+		{"fixed on top", model.FeeRule{Rate: decimal.RequireFromString("1"), FixedAmount: decimal.RequireFromString("5000")}, "1000000", "15000"},
+		{"exempt part not charged", model.FeeRule{Rate: decimal.RequireFromString("5"), ExemptAmount: decimal.RequireFromString("600000")}, "1000000", "20000"},
+		{"below the exempt amount", model.FeeRule{Rate: decimal.RequireFromString("5"), ExemptAmount: decimal.RequireFromString("600000")}, "500000", "0"},
+		{"raised to the minimum", model.FeeRule{Rate: decimal.RequireFromString("1"), MinAmount: decimal.RequireFromString("20000")}, "1000000", "20000"},
+		{"capped at the maximum", model.FeeRule{Rate: decimal.RequireFromString("5"), MaxAmount: &max}, "1000000", "30000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDecimal(t, "charge", feeRuleCharge(&tt.rule, decimal.RequireFromString(tt.amount)), tt.want)
+		})
+	}
+}
+
+func assertDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
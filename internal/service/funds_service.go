@@ -0,0 +1,323 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientFunds is returned when a hold needs more than the available balance
+var ErrInsufficientFunds = errors.New("insufficient available balance")
+
+// FundsService reserves, releases and charges user funds. The *Tx methods run
+// inside the caller's transaction so a hold always moves together with the
+// bid that caused it; they lock the user row. Every change to a hold amount is
+// posted to the ledger as a transfer between the user's available and held
+// wallet accounts.
+type FundsService interface {
+	SetHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error
+	EnsureHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error
+	ReleaseHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind) error
+	CapHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, max decimal.Decimal) error
+	ReleaseItemHoldsTx(tx *gorm.DB, itemID uint, kind model.HoldKind, keepUserID string) error
+	SyncItemHoldsTx(tx *gorm.DB, itemID uint, kind model.HoldKind, amounts map[string]decimal.Decimal) error
+	CaptureHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error
+	ForfeitHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind) error
+}
+
+type fundsService struct {
+	holdRepo      repository.FundsHoldRepository
+	userRepo      repository.UserRepository
+	ledgerService LedgerService
+}
+
+func NewFundsService(
+	holdRepo repository.FundsHoldRepository,
+	userRepo repository.UserRepository,
+	ledgerService LedgerService,
+) FundsService {
+	return &fundsService{
+		holdRepo:      holdRepo,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+	}
+}
+
+// SetHoldTx makes the user's active hold on the item exactly amount, creating
+// it if needed. Raising a hold needs the difference in available funds;
+// lowering it always succeeds. A zero amount releases the hold.
+func (s *fundsService) SetHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return s.ReleaseHoldTx(tx, userID, itemID, kind)
+	}
+
+	if _, err := s.userRepo.WithTx(tx).FindByIDForUpdate(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	holdRepo := s.holdRepo.WithTx(tx)
+	hold, err := holdRepo.FindActive(userID, itemID, kind)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	exists := err == nil
+
+	current := decimal.Zero
+	if exists {
+		current = hold.Amount
+	}
+	delta := amount.Sub(current)
+	if delta.IsZero() {
+		return nil
+	}
+	if delta.IsPositive() {
+		balance, err := s.ledgerService.UserBalanceTx(tx, userID)
+		if err != nil {
+			return err
+		}
+		if balance.Available.LessThan(delta) {
+			return ErrInsufficientFunds
+		}
+	}
+
+	if exists {
+		hold.Amount = amount
+		if err := holdRepo.Update(hold); err != nil {
+			return err
+		}
+	} else {
+		hold = &model.FundsHold{
+			UserID: userID,
+			ItemID: itemID,
+			Kind:   kind,
+			Amount: amount,
+			Status: model.HoldStatusActive,
+		}
+		if err := holdRepo.Create(hold); err != nil {
+			return err
+		}
+	}
+
+	if delta.IsPositive() {
+		return s.postHoldTx(tx, userID, itemID, kind, delta)
+	}
+	return s.postReleaseTx(tx, userID, itemID, kind, delta.Neg())
+}
+
+// EnsureHoldTx raises the hold to at least amount and never lowers it
+func (s *fundsService) EnsureHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error {
+	hold, err := s.holdRepo.WithTx(tx).FindActive(userID, itemID, kind)
+	if err == nil && hold.Amount.GreaterThanOrEqual(amount) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.SetHoldTx(tx, userID, itemID, kind, amount)
+}
+
+func (s *fundsService) ReleaseHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind) error {
+	holdRepo := s.holdRepo.WithTx(tx)
+	hold, err := holdRepo.FindActive(userID, itemID, kind)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.releaseTx(tx, hold)
+}
+
+// CapHoldTx lowers the hold to max if it is above it and never raises it
+func (s *fundsService) CapHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, max decimal.Decimal) error {
+	hold, err := s.holdRepo.WithTx(tx).FindActive(userID, itemID, kind)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if hold.Amount.LessThanOrEqual(max) {
+		return nil
+	}
+	return s.SetHoldTx(tx, userID, itemID, kind, max)
+}
+
+// ReleaseItemHoldsTx frees every remaining active hold of a kind on the item,
+// except keepUserID's when it is set
+func (s *fundsService) ReleaseItemHoldsTx(tx *gorm.DB, itemID uint, kind model.HoldKind, keepUserID string) error {
+	holds, err := s.holdRepo.WithTx(tx).FindActiveByItem(itemID, kind)
+	if err != nil {
+		return err
+	}
+	for i := range holds {
+		if holds[i].UserID == keepUserID {
+			continue
+		}
+		if err := s.releaseTx(tx, &holds[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncItemHoldsTx sets each listed user's hold on the item to their amount and
+// releases the holds of everyone else. Users are visited in a fixed order so
+// concurrent syncs lock user rows consistently.
+func (s *fundsService) SyncItemHoldsTx(tx *gorm.DB, itemID uint, kind model.HoldKind, amounts map[string]decimal.Decimal) error {
+	holds, err := s.holdRepo.WithTx(tx).FindActiveByItem(itemID, kind)
+	if err != nil {
+		return err
+	}
+
+	targets := make(map[string]decimal.Decimal, len(amounts)+len(holds))
+	for _, h := range holds {
+		targets[h.UserID] = decimal.Zero
+	}
+	for userID, amount := range amounts {
+		targets[userID] = amount
+	}
+
+	userIDs := make([]string, 0, len(targets))
+	for userID := range targets {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	for _, userID := range userIDs {
+		if err := s.SetHoldTx(tx, userID, itemID, kind, targets[userID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CaptureHoldTx charges amount from the user's balance against their hold.
+// Any part of the hold above amount is released. Without a hold (bids placed
+// before holds existed) the charge is taken from the available balance
+// directly. The charge is credited to the platform proceeds account.
+func (s *fundsService) CaptureHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error {
+	return s.captureTx(tx, userID, itemID, kind, &amount, PlatformProceedsAccount, model.JournalEntryCharge,
+		fmt.Sprintf("Charge for item #%d", itemID))
+}
+
+// ForfeitHoldTx takes the whole active hold into the forfeited deposits account
+func (s *fundsService) ForfeitHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind) error {
+	return s.captureTx(tx, userID, itemID, kind, nil, PlatformForfeitAccount, model.JournalEntryForfeit,
+		fmt.Sprintf("%s forfeited for item #%d", kind, itemID))
+}
+
+// captureTx marks the hold captured and posts one entry: the whole hold
+// leaves the held account, any excess goes back to available and any
+// shortfall comes out of available. A nil amount captures the hold as is.
+func (s *fundsService) captureTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount *decimal.Decimal, to LedgerAccountRef, entryType model.JournalEntryType, description string) error {
+	holdRepo := s.holdRepo.WithTx(tx)
+
+	if _, err := s.userRepo.WithTx(tx).FindByIDForUpdate(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	now := time.Now()
+	held := decimal.Zero
+	hold, err := holdRepo.FindActive(userID, itemID, kind)
+	switch {
+	case err == nil:
+		held = hold.Amount
+		if amount == nil {
+			amount = &held
+		}
+		hold.Amount = *amount
+		hold.Status = model.HoldStatusCaptured
+		hold.CapturedAt = &now
+		if err := holdRepo.Update(hold); err != nil {
+			return err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if amount == nil {
+			return nil
+		}
+		if err := holdRepo.Create(&model.FundsHold{
+			UserID:     userID,
+			ItemID:     itemID,
+			Kind:       kind,
+			Amount:     *amount,
+			Status:     model.HoldStatusCaptured,
+			CapturedAt: &now,
+		}); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	if !amount.IsPositive() {
+		if held.IsPositive() {
+			return s.postReleaseTx(tx, userID, itemID, kind, held)
+		}
+		return nil
+	}
+
+	lines := []JournalLineRequest{{Account: to, Credit: *amount}}
+	if held.IsPositive() {
+		lines = append(lines, JournalLineRequest{Account: UserHeldAccount(userID), Debit: held})
+	}
+	switch {
+	case held.GreaterThan(*amount):
+		lines = append(lines, JournalLineRequest{Account: UserAvailableAccount(userID), Credit: held.Sub(*amount)})
+	case held.LessThan(*amount):
+		lines = append(lines, JournalLineRequest{Account: UserAvailableAccount(userID), Debit: amount.Sub(held)})
+	}
+
+	_, err = s.ledgerService.PostTx(tx, JournalRequest{
+		Type:        entryType,
+		Description: description,
+		ItemID:      &itemID,
+		Lines:       lines,
+	})
+	return err
+}
+
+func (s *fundsService) releaseTx(tx *gorm.DB, hold *model.FundsHold) error {
+	now := time.Now()
+	hold.Status = model.HoldStatusReleased
+	hold.ReleasedAt = &now
+	if err := s.holdRepo.WithTx(tx).Update(hold); err != nil {
+		return err
+	}
+
+	if !hold.Amount.IsPositive() {
+		return nil
+	}
+	return s.postReleaseTx(tx, hold.UserID, hold.ItemID, hold.Kind, hold.Amount)
+}
+
+func (s *fundsService) postHoldTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error {
+	_, err := s.ledgerService.TransferTx(tx, TransferRequest{
+		Type:        model.JournalEntryHold,
+		From:        UserAvailableAccount(userID),
+		To:          UserHeldAccount(userID),
+		Amount:      amount,
+		Description: fmt.Sprintf("%s hold for item #%d", kind, itemID),
+		ItemID:      &itemID,
+	})
+	return err
+}
+
+func (s *fundsService) postReleaseTx(tx *gorm.DB, userID string, itemID uint, kind model.HoldKind, amount decimal.Decimal) error {
+	_, err := s.ledgerService.TransferTx(tx, TransferRequest{
+		Type:        model.JournalEntryRelease,
+		From:        UserHeldAccount(userID),
+		To:          UserAvailableAccount(userID),
+		Amount:      amount,
+		Description: fmt.Sprintf("%s hold released for item #%d", kind, itemID),
+		ItemID:      &itemID,
+	})
+	return err
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNextBidCount = 5
+	maxNextBidCount     = 20
+)
+
+// NextBids lists the next valid bid amounts of an open-bidding lot
+type NextBids struct {
+	ItemID       uint              `json:"item_id"`
+	CurrentPrice decimal.Decimal   `json:"current_price"`
+	Increment    decimal.Decimal   `json:"increment"` // increment at the current price
+	Amounts      []decimal.Decimal `json:"next_bids"`
+}
+
+// ========== BID INCREMENTS ==========
+
+// incrementLadder is the bands of the increment table that applies to an
+// item, lowest first. An empty ladder means the item's fixed increment.
+type incrementLadder []model.IncrementBand
+
+// incrementAt returns the increment for the next bid above price
+func (l incrementLadder) incrementAt(item *model.AuctionItem, price decimal.Decimal) decimal.Decimal {
+	if len(l) == 0 {
+		return item.IncrementAmount
+	}
+	increment := l[0].Increment
+	for _, band := range l {
+		if price.GreaterThanOrEqual(band.MinPrice) {
+			increment = band.Increment
+		}
+	}
+	return increment
+}
+
+// nextMinimumBid returns the lowest amount the next bid may have
+func (l incrementLadder) nextMinimumBid(item *model.AuctionItem) decimal.Decimal {
+	if item.BidCount == 0 {
+		return item.StartingPrice
+	}
+	return item.CurrentHighestBid.Add(l.incrementAt(item, item.CurrentHighestBid))
+}
+
+// incrementsTx resolves the increment table for an item. The most specific
+// active table wins: item, category, parent category, then organizer. tx may
+// be nil outside a transaction.
+func (s *auctionService) incrementsTx(tx *gorm.DB, item *model.AuctionItem) (incrementLadder, error) {
+	categoryIDs := []uint{item.CategoryID}
+	var parentCategoryID *uint
+	if category, err := s.categoryRepo.FindByID(item.CategoryID); err == nil && category.ParentCategoryID != nil {
+		parentCategoryID = category.ParentCategoryID
+		categoryIDs = append(categoryIDs, *parentCategoryID)
+	}
+
+	incrementRepo := s.incrementRepo
+	if tx != nil {
+		incrementRepo = incrementRepo.WithTx(tx)
+	}
+	tables, err := incrementRepo.FindActiveFor(item.ID, categoryIDs, item.OrganizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *model.IncrementTable
+	bestScore := 0
+	for i := range tables {
+		table := &tables[i]
+		score := 0
+		switch {
+		case table.ItemID != nil && *table.ItemID == item.ID:
+			score = 4
+		case table.CategoryID != nil && *table.CategoryID == item.CategoryID:
+			score = 3
+		case table.CategoryID != nil && parentCategoryID != nil && *table.CategoryID == *parentCategoryID:
+			score = 2
+		case table.OrganizerID != nil && *table.OrganizerID == item.OrganizerID:
+			score = 1
+		}
+		if score > bestScore {
+			best, bestScore = table, score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return incrementLadder(best.Bands), nil
+}
+
+// GetNextBids lists the next count valid bids, each one increment above the
+// one before, with the increment taken from the band each amount falls in
+func (s *auctionService) GetNextBids(itemID uint, count int) (*NextBids, error) {
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("auction item not found")
+	}
+	if item.AuctionMethod != "" && item.AuctionMethod != model.AuctionMethodOpenBidding {
+		return nil, errors.New("next bid amounts are only available for open bidding")
+	}
+
+	if count <= 0 {
+		count = defaultNextBidCount
+	}
+	if count > maxNextBidCount {
+		count = maxNextBidCount
+	}
+
+	ladder, err := s.incrementsTx(nil, item)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &NextBids{
+		ItemID:       item.ID,
+		CurrentPrice: item.CurrentHighestBid,
+		Increment:    ladder.incrementAt(item, item.CurrentHighestBid),
+		Amounts:      make([]decimal.Decimal, 0, count),
+	}
+	amount := ladder.nextMinimumBid(item)
+	for i := 0; i < count; i++ {
+		next.Amounts = append(next.Amounts, amount)
+		increment := ladder.incrementAt(item, amount)
+		if !increment.IsPositive() {
+			break
+		}
+		amount = amount.Add(increment)
+	}
+	return next, nil
+}
+
+// SaveIncrementTable creates the table for an item, category or organizer,
+// or replaces the existing one for the same target
+func (s *auctionService) SaveIncrementTable(req IncrementTableRequest) (*model.IncrementTable, error) {
+	if err := s.validateIncrementTarget(req); err != nil {
+		return nil, err
+	}
+	bands, err := incrementBands(req.Bands)
+	if err != nil {
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	var table *model.IncrementTable
+	err = s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		incrementRepo := s.incrementRepo.WithTx(tx)
+
+		existing, err := incrementRepo.FindByTarget(req.OrganizerID, req.CategoryID, req.ItemID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			table = &model.IncrementTable{
+				Name:        req.Name,
+				OrganizerID: req.OrganizerID,
+				CategoryID:  req.CategoryID,
+				ItemID:      req.ItemID,
+				IsActive:    isActive,
+				Bands:       bands,
+			}
+			return incrementRepo.Create(table)
+		}
+		if err != nil {
+			return err
+		}
+
+		table = existing
+		table.Name = req.Name
+		table.IsActive = isActive
+		if err := incrementRepo.Update(table); err != nil {
+			return err
+		}
+		if err := incrementRepo.ReplaceBands(table.ID, bands); err != nil {
+			return err
+		}
+		table.Bands = bands
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *auctionService) GetIncrementTables() ([]model.IncrementTable, error) {
+	return s.incrementRepo.FindAll()
+}
+
+func (s *auctionService) DeleteIncrementTable(id uint) error {
+	if _, err := s.incrementRepo.FindByID(id); err != nil {
+		return errors.New("increment table not found")
+	}
+	return s.incrementRepo.Delete(id)
+}
+
+func (s *auctionService) validateIncrementTarget(req IncrementTableRequest) error {
+	targets := 0
+	for _, id := range []*uint{req.OrganizerID, req.CategoryID, req.ItemID} {
+		if id != nil {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return errors.New("exactly one of item_id, category_id or organizer_id is required")
+	}
+
+	switch {
+	case req.ItemID != nil:
+		if _, err := s.itemRepo.FindByID(*req.ItemID); err != nil {
+			return errors.New("auction item not found")
+		}
+	case req.CategoryID != nil:
+		if _, err := s.categoryRepo.FindByID(*req.CategoryID); err != nil {
+			return errors.New("category not found")
+		}
+	default:
+		if _, err := s.organizerRepo.FindByID(*req.OrganizerID); err != nil {
+			return errors.New("organizer not found")
+		}
+	}
+	return nil
+}
+
+// incrementBands validates the requested bands and orders them by price. The
+// lowest band must start at zero so every price has an increment.
+func incrementBands(req []IncrementBandRequest) ([]model.IncrementBand, error) {
+	if len(req) == 0 {
+		return nil, errors.New("at least one band is required")
+	}
+
+	bands := make([]model.IncrementBand, 0, len(req))
+	for _, b := range req {
+		band := model.IncrementBand{
+			MinPrice:  decimal.NewFromFloat(b.MinPrice).Round(2),
+			Increment: decimal.NewFromFloat(b.Increment).Round(2),
+		}
+		if band.MinPrice.IsNegative() || !band.Increment.IsPositive() {
+			return nil, errors.New("bands need a non-negative min_price and a positive increment")
+		}
+		bands = append(bands, band)
+	}
+	sort.Slice(bands, func(i, j int) bool {
+		return bands[i].MinPrice.LessThan(bands[j].MinPrice)
+	})
+
+	if !bands[0].MinPrice.IsZero() {
+		return nil, errors.New("the lowest band must start at a min_price of 0")
+	}
+	for i := 1; i < len(bands); i++ {
+		if bands[i].MinPrice.Equal(bands[i-1].MinPrice) {
+			return nil, errors.New("bands must have distinct min_price values")
+		}
+	}
+	return bands, nil
+}
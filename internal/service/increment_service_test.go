@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestIncrementLadder(t *testing.T) {
+	item := &model.AuctionItem{
+		StartingPrice:   decimal.RequireFromString("500000"),
+		IncrementAmount: decimal.RequireFromString("25000"),
+	}
+	ladder := incrementLadder{
+		{MinPrice: decimal.RequireFromString("0"), Increment: decimal.RequireFromString("50000")},
+		{MinPrice: decimal.RequireFromString("1000000"), Increment: decimal.RequireFromString("100000")},
+		{MinPrice: decimal.RequireFromString("10000000"), Increment: decimal.RequireFromString("500000")},
+	}
+
+	tests := []struct {
+		name   string
+		ladder incrementLadder
+		price  string
+		want   string
+	}{
+		{"no table uses the item's increment", nil, "2000000", "25000"},
+		{"lowest band", ladder, "500000", "50000"},
+		{"just below a band", ladder, "999999.99", "50000"},
+		{"on a band boundary", ladder, "1000000", "100000"},
+		{"top band", ladder, "25000000", "500000"},
+		{"below the first band uses it", incrementLadder{
+			{MinPrice: decimal.RequireFromString("1000000"), Increment: decimal.RequireFromString("100000")},
+		}, "500000", "100000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.ladder.incrementAt(item, decimal.RequireFromString(tt.price))
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("incrementAt(%s) = %s, want %s", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextMinimumBid(t *testing.T) {
+	ladder := incrementLadder{
+		{MinPrice: decimal.RequireFromString("0"), Increment: decimal.RequireFromString("50000")},
+		{MinPrice: decimal.RequireFromString("1000000"), Increment: decimal.RequireFromString("100000")},
+	}
+
+	tests := []struct {
+		name     string
+		bidCount int
+		current  string
+		want     string
+	}{
+		{"first bid may be the starting price", 0, "500000", "500000"},
+		{"increment of the current band", 3, "950000", "1000000"},
+		{"increment of the band the price reached", 4, "1000000", "1100000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &model.AuctionItem{
+				StartingPrice:     decimal.RequireFromString("500000"),
+				CurrentHighestBid: decimal.RequireFromString(tt.current),
+				BidCount:          tt.bidCount,
+			}
+			got := ladder.nextMinimumBid(item)
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("nextMinimumBid = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrUnbalancedEntry is returned when a journal entry's debits and credits differ
+var ErrUnbalancedEntry = errors.New("journal entry debits and credits must be equal")
+
+// LedgerService is the only writer of money. Every movement is an append-only,
+// balanced journal entry; User.Balance and User.HeldBalance are projections
+// refreshed from the ledger in the same transaction as the entry.
+type LedgerService interface {
+	PostTx(tx *gorm.DB, req JournalRequest) (*model.JournalEntry, error)
+	TransferTx(tx *gorm.DB, req TransferRequest) (*model.JournalEntry, error)
+	UserBalanceTx(tx *gorm.DB, userID string) (*WalletBalance, error)
+
+	GetBalance(userID string) (*WalletBalance, error)
+	GetTransactions(userID string, page, limit int) ([]WalletTransaction, int64, error)
+	PostAdjustment(req AdjustmentRequest) (*model.JournalEntry, error)
+	CheckConsistency() (*LedgerConsistencyReport, error)
+	MigrateLegacyBalances() (int, error)
+}
+
+// ========== ACCOUNTS ==========
+
+// LedgerAccountRef names an account; it is created on first use
+type LedgerAccountRef struct {
+	Code   string
+	Name   string
+	Type   model.LedgerAccountType
+	UserID *string
+}
+
+var (
+	// PlatformCashAccount is money the platform actually holds: top-ups
+	// debit it, withdrawals credit it
+	PlatformCashAccount = LedgerAccountRef{Code: "platform:cash", Name: "Platform cash", Type: model.LedgerAccountAsset}
+	// PlatformProceedsAccount collects winning charges until they are paid out
+	PlatformProceedsAccount = LedgerAccountRef{Code: "platform:proceeds", Name: "Auction proceeds", Type: model.LedgerAccountLiability}
+	// PlatformFeeAccount is the platform's fee income
+	PlatformFeeAccount = LedgerAccountRef{Code: "platform:fees", Name: "Fee revenue", Type: model.LedgerAccountRevenue}
+	// PlatformTaxAccount holds taxes collected on sales until they are remitted
+	PlatformTaxAccount = LedgerAccountRef{Code: "platform:taxes", Name: "Taxes payable", Type: model.LedgerAccountLiability}
+	// PlatformForfeitAccount receives deposits forfeited by defaulting winners
+	PlatformForfeitAccount = LedgerAccountRef{Code: "platform:forfeits", Name: "Forfeited deposits", Type: model.LedgerAccountRevenue}
+	// PlatformEquityAccount offsets opening balances and manual adjustments
+	PlatformEquityAccount = LedgerAccountRef{Code: "platform:equity", Name: "Platform equity", Type: model.LedgerAccountEquity}
+)
+
+const (
+	walletAvailable = "available"
+	walletHeld      = "held"
+)
+
+// UserAvailableAccount is the spendable part of a user's wallet
+func UserAvailableAccount(userID string) LedgerAccountRef {
+	return userWalletAccount(userID, walletAvailable)
+}
+
+// UserHeldAccount is the part of a user's wallet reserved by holds
+func UserHeldAccount(userID string) LedgerAccountRef {
+	return userWalletAccount(userID, walletHeld)
+}
+
+func userWalletAccount(userID, purpose string) LedgerAccountRef {
+	id := userID
+	return LedgerAccountRef{
+		Code:   fmt.Sprintf("user:%s:%s", userID, purpose),
+		Name:   fmt.Sprintf("Wallet %s (%s)", purpose, userID),
+		Type:   model.LedgerAccountLiability,
+		UserID: &id,
+	}
+}
+
+// walletPurpose returns "available" or "held" for a user wallet account code
+func walletPurpose(account *model.LedgerAccount) string {
+	if account.UserID == nil {
+		return ""
+	}
+	if strings.HasSuffix(account.Code, ":"+walletHeld) {
+		return walletHeld
+	}
+	return walletAvailable
+}
+
+// accountBalance is the balance in the account's normal direction
+func accountBalance(accountType model.LedgerAccountType, debits, credits decimal.Decimal) decimal.Decimal {
+	if accountType == model.LedgerAccountAsset {
+		return debits.Sub(credits)
+	}
+	return credits.Sub(debits)
+}
+
+// ========== REQUEST/RESPONSE STRUCTS ==========
+
+type JournalRequest struct {
+	Type        model.JournalEntryType
+	Description string
+	Reference   *string // optional idempotency key
+	ItemID      *uint
+	CreatedBy   *string
+	Lines       []JournalLineRequest
+}
+
+type JournalLineRequest struct {
+	Account LedgerAccountRef
+	Debit   decimal.Decimal
+	Credit  decimal.Decimal
+}
+
+// TransferRequest moves Amount by debiting From and crediting To
+type TransferRequest struct {
+	Type        model.JournalEntryType
+	From        LedgerAccountRef
+	To          LedgerAccountRef
+	Amount      decimal.Decimal
+	Description string
+	Reference   *string
+	ItemID      *uint
+	CreatedBy   *string
+}
+
+type AdjustmentRequest struct {
+	UserID  string          `json:"user_id" binding:"required"`
+	Amount  decimal.Decimal `json:"amount"` // positive credits the wallet, negative debits it
+	Reason  string          `json:"reason" binding:"required"`
+	AdminID string          `json:"-"`
+}
+
+type WalletBalance struct {
+	Available decimal.Decimal `json:"available"`
+	Held      decimal.Decimal `json:"held"`
+	Total     decimal.Decimal `json:"total"`
+}
+
+// WalletTransaction is one journal line on a user's wallet, signed so that
+// positive amounts increase the account
+type WalletTransaction struct {
+	EntryID     uint                   `json:"entry_id"`
+	LineID      uint                   `json:"line_id"`
+	Type        model.JournalEntryType `json:"type"`
+	Account     string                 `json:"account"`
+	Amount      decimal.Decimal        `json:"amount"`
+	Description string                 `json:"description"`
+	ItemID      *uint                  `json:"item_id,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type LedgerConsistencyReport struct {
+	Consistent         bool                `json:"consistent"`
+	TotalDebits        decimal.Decimal     `json:"total_debits"`
+	TotalCredits       decimal.Decimal     `json:"total_credits"`
+	UnbalancedEntryIDs []uint              `json:"unbalanced_entry_ids"`
+	NegativeAccounts   []string            `json:"negative_accounts"`
+	ProjectionDrift    []BalanceProjection `json:"projection_drift"`
+}
+
+// BalanceProjection compares a user's cached balance columns with the ledger
+type BalanceProjection struct {
+	UserID      string          `json:"user_id"`
+	CachedTotal decimal.Decimal `json:"cached_total"`
+	CachedHeld  decimal.Decimal `json:"cached_held"`
+	LedgerTotal decimal.Decimal `json:"ledger_total"`
+	LedgerHeld  decimal.Decimal `json:"ledger_held"`
+}
+
+// ========== SERVICE ==========
+
+type ledgerService struct {
+	transactor repository.Transactor
+	ledgerRepo repository.LedgerRepository
+	userRepo   repository.UserRepository
+}
+
+func NewLedgerService(
+	transactor repository.Transactor,
+	ledgerRepo repository.LedgerRepository,
+	userRepo repository.UserRepository,
+) LedgerService {
+	return &ledgerService{
+		transactor: transactor,
+		ledgerRepo: ledgerRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// PostTx appends a balanced entry inside the caller's transaction. Users whose
+// wallets it touches are locked in a fixed order and have their balance
+// projection refreshed before it returns.
+func (s *ledgerService) PostTx(tx *gorm.DB, req JournalRequest) (*model.JournalEntry, error) {
+	if req.Description == "" {
+		return nil, errors.New("journal entry needs a description")
+	}
+	if err := checkBalanced(req.Lines); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, 2)
+	seen := make(map[string]bool)
+	for _, line := range req.Lines {
+		if id := line.Account.UserID; id != nil && !seen[*id] {
+			seen[*id] = true
+			userIDs = append(userIDs, *id)
+		}
+	}
+	sort.Strings(userIDs)
+
+	userRepo := s.userRepo.WithTx(tx)
+	for _, userID := range userIDs {
+		if _, err := userRepo.FindByIDForUpdate(userID); err != nil {
+			return nil, errors.New("user not found")
+		}
+	}
+
+	ledgerRepo := s.ledgerRepo.WithTx(tx)
+	entry := &model.JournalEntry{
+		EntryType:   req.Type,
+		Description: req.Description,
+		Reference:   req.Reference,
+		ItemID:      req.ItemID,
+		CreatedBy:   req.CreatedBy,
+	}
+	for _, line := range req.Lines {
+		account, err := ledgerRepo.EnsureAccount(&model.LedgerAccount{
+			Code:   line.Account.Code,
+			Name:   line.Account.Name,
+			Type:   line.Account.Type,
+			UserID: line.Account.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entry.Lines = append(entry.Lines, model.JournalLine{
+			AccountID: account.ID,
+			Debit:     line.Debit,
+			Credit:    line.Credit,
+		})
+	}
+
+	if err := ledgerRepo.CreateEntry(entry); err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.refreshProjectionTx(tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// checkBalanced enforces the double-entry invariant: at least two lines, each
+// either a debit or a credit, and debits equal to credits
+func checkBalanced(lines []JournalLineRequest) error {
+	if len(lines) < 2 {
+		return errors.New("journal entry needs at least two lines")
+	}
+
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, line := range lines {
+		if line.Debit.IsNegative() || line.Credit.IsNegative() {
+			return errors.New("journal line amounts cannot be negative")
+		}
+		if line.Debit.IsPositive() == line.Credit.IsPositive() {
+			return errors.New("journal line must either debit or credit")
+		}
+		debits = debits.Add(line.Debit)
+		credits = credits.Add(line.Credit)
+	}
+	if !debits.Equal(credits) {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+func (s *ledgerService) TransferTx(tx *gorm.DB, req TransferRequest) (*model.JournalEntry, error) {
+	if !req.Amount.IsPositive() {
+		return nil, errors.New("transfer amount must be positive")
+	}
+	return s.PostTx(tx, JournalRequest{
+		Type:        req.Type,
+		Description: req.Description,
+		Reference:   req.Reference,
+		ItemID:      req.ItemID,
+		CreatedBy:   req.CreatedBy,
+		Lines: []JournalLineRequest{
+			{Account: req.From, Debit: req.Amount},
+			{Account: req.To, Credit: req.Amount},
+		},
+	})
+}
+
+// UserBalanceTx derives a user's wallet balance from their journal lines
+func (s *ledgerService) UserBalanceTx(tx *gorm.DB, userID string) (*WalletBalance, error) {
+	return s.userBalance(s.ledgerRepo.WithTx(tx), userID)
+}
+
+func (s *ledgerService) userBalance(ledgerRepo repository.LedgerRepository, userID string) (*WalletBalance, error) {
+	accounts, err := ledgerRepo.FindAccountsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &WalletBalance{}
+	if len(accounts) == 0 {
+		return balance, nil
+	}
+
+	ids := make([]uint, len(accounts))
+	for i, a := range accounts {
+		ids[i] = a.ID
+	}
+	balances, err := ledgerRepo.AccountBalances(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range balances {
+		amount := accountBalance(b.Type, b.Debits, b.Credits)
+		if walletPurpose(&model.LedgerAccount{Code: b.Code, UserID: b.UserID}) == walletHeld {
+			balance.Held = balance.Held.Add(amount)
+		} else {
+			balance.Available = balance.Available.Add(amount)
+		}
+	}
+	balance.Total = balance.Available.Add(balance.Held)
+	return balance, nil
+}
+
+func (s *ledgerService) refreshProjectionTx(tx *gorm.DB, userID string) error {
+	balance, err := s.UserBalanceTx(tx, userID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.WithTx(tx).UpdateBalances(userID, balance.Total, balance.Held)
+}
+
+func (s *ledgerService) GetBalance(userID string) (*WalletBalance, error) {
+	return s.userBalance(s.ledgerRepo, userID)
+}
+
+func (s *ledgerService) GetTransactions(userID string, page, limit int) ([]WalletTransaction, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	accounts, err := s.ledgerRepo.FindAccountsByUser(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(accounts) == 0 {
+		return []WalletTransaction{}, 0, nil
+	}
+
+	ids := make([]uint, len(accounts))
+	for i, a := range accounts {
+		ids[i] = a.ID
+	}
+	lines, total, err := s.ledgerRepo.FindLinesByAccounts(ids, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions := make([]WalletTransaction, 0, len(lines))
+	for _, line := range lines {
+		tx := WalletTransaction{
+			EntryID: line.EntryID,
+			LineID:  line.ID,
+			Amount:  line.Credit.Sub(line.Debit),
+		}
+		if line.Account != nil {
+			tx.Account = walletPurpose(line.Account)
+		}
+		if line.Entry != nil {
+			tx.Type = line.Entry.EntryType
+			tx.Description = line.Entry.Description
+			tx.ItemID = line.Entry.ItemID
+			tx.CreatedAt = line.Entry.CreatedAt
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, total, nil
+}
+
+// PostAdjustment credits or debits a user's available balance against
+// platform equity. Debits cannot take the available balance below zero.
+func (s *ledgerService) PostAdjustment(req AdjustmentRequest) (*model.JournalEntry, error) {
+	if req.Amount.IsZero() {
+		return nil, errors.New("adjustment amount cannot be zero")
+	}
+	if req.Reason == "" {
+		return nil, errors.New("adjustment reason is required")
+	}
+
+	var entry *model.JournalEntry
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		if _, err := s.userRepo.WithTx(tx).FindByIDForUpdate(req.UserID); err != nil {
+			return errors.New("user not found")
+		}
+
+		transfer := TransferRequest{
+			Type:        model.JournalEntryAdjustment,
+			From:        PlatformEquityAccount,
+			To:          UserAvailableAccount(req.UserID),
+			Amount:      req.Amount,
+			Description: "Manual adjustment: " + req.Reason,
+			CreatedBy:   &req.AdminID,
+		}
+		if req.Amount.IsNegative() {
+			balance, err := s.UserBalanceTx(tx, req.UserID)
+			if err != nil {
+				return err
+			}
+			if balance.Available.LessThan(req.Amount.Neg()) {
+				return ErrInsufficientFunds
+			}
+			transfer.From, transfer.To = transfer.To, transfer.From
+			transfer.Amount = req.Amount.Neg()
+		}
+
+		var err error
+		entry, err = s.TransferTx(tx, transfer)
+		return err
+	})
+	return entry, err
+}
+
+// CheckConsistency verifies that every entry balances, that no user wallet
+// account is negative and that the cached balance columns match the ledger
+func (s *ledgerService) CheckConsistency() (*LedgerConsistencyReport, error) {
+	report := &LedgerConsistencyReport{
+		UnbalancedEntryIDs: []uint{},
+		NegativeAccounts:   []string{},
+		ProjectionDrift:    []BalanceProjection{},
+	}
+
+	debits, credits, err := s.ledgerRepo.Totals()
+	if err != nil {
+		return nil, err
+	}
+	report.TotalDebits = debits
+	report.TotalCredits = credits
+
+	unbalanced, err := s.ledgerRepo.FindUnbalancedEntryIDs()
+	if err != nil {
+		return nil, err
+	}
+	report.UnbalancedEntryIDs = append(report.UnbalancedEntryIDs, unbalanced...)
+
+	balances, err := s.ledgerRepo.UserAccountBalances()
+	if err != nil {
+		return nil, err
+	}
+	ledgerByUser := make(map[string]*WalletBalance)
+	for _, b := range balances {
+		amount := accountBalance(b.Type, b.Debits, b.Credits)
+		if amount.IsNegative() {
+			report.NegativeAccounts = append(report.NegativeAccounts, b.Code)
+		}
+		wallet, ok := ledgerByUser[*b.UserID]
+		if !ok {
+			wallet = &WalletBalance{}
+			ledgerByUser[*b.UserID] = wallet
+		}
+		if walletPurpose(&model.LedgerAccount{Code: b.Code, UserID: b.UserID}) == walletHeld {
+			wallet.Held = wallet.Held.Add(amount)
+		}
+		wallet.Total = wallet.Total.Add(amount)
+	}
+
+	users, err := s.userRepo.FindWithBalances()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if _, ok := ledgerByUser[u.ID]; !ok {
+			ledgerByUser[u.ID] = &WalletBalance{}
+		}
+	}
+	cached := make(map[string]model.User, len(users))
+	for _, u := range users {
+		cached[u.ID] = u
+	}
+
+	userIDs := make([]string, 0, len(ledgerByUser))
+	for userID := range ledgerByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	for _, userID := range userIDs {
+		wallet := ledgerByUser[userID]
+		user := cached[userID]
+		if !user.Balance.Equal(wallet.Total) || !user.HeldBalance.Equal(wallet.Held) {
+			report.ProjectionDrift = append(report.ProjectionDrift, BalanceProjection{
+				UserID:      userID,
+				CachedTotal: user.Balance,
+				CachedHeld:  user.HeldBalance,
+				LedgerTotal: wallet.Total,
+				LedgerHeld:  wallet.Held,
+			})
+		}
+	}
+
+	report.Consistent = debits.Equal(credits) &&
+		len(report.UnbalancedEntryIDs) == 0 &&
+		len(report.NegativeAccounts) == 0 &&
+		len(report.ProjectionDrift) == 0
+	return report, nil
+}
+
+// MigrateLegacyBalances posts an opening entry for every user whose balance
+// columns predate the ledger. It is idempotent: the entry reference is keyed
+// by user, and users who already have ledger activity are skipped.
+func (s *ledgerService) MigrateLegacyBalances() (int, error) {
+	users, err := s.userRepo.FindWithBalances()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, u := range users {
+		count, err := s.ledgerRepo.CountEntriesByUser(u.ID)
+		if err != nil {
+			return migrated, err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err = s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			user, err := s.userRepo.WithTx(tx).FindByIDForUpdate(u.ID)
+			if err != nil {
+				return err
+			}
+
+			available := user.AvailableBalance()
+			total := user.Balance
+			if available.IsNegative() || user.HeldBalance.IsNegative() {
+				return fmt.Errorf("user %s has a negative legacy balance", user.ID)
+			}
+
+			lines := []JournalLineRequest{{Account: PlatformEquityAccount, Debit: total}}
+			if available.IsPositive() {
+				lines = append(lines, JournalLineRequest{Account: UserAvailableAccount(user.ID), Credit: available})
+			}
+			if user.HeldBalance.IsPositive() {
+				lines = append(lines, JournalLineRequest{Account: UserHeldAccount(user.ID), Credit: user.HeldBalance})
+			}
+
+			reference := "opening:" + user.ID
+			_, err = s.PostTx(tx, JournalRequest{
+				Type:        model.JournalEntryOpening,
+				Description: "Opening balance carried over from the legacy balance column",
+				Reference:   &reference,
+				Lines:       lines,
+			})
+			return err
+		})
+		if err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
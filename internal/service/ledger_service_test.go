@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckBalanced(t *testing.T) {
+	userID := "user-1"
+	cash := LedgerAccountRef{Code: "platform:cash", Type: model.LedgerAccountAsset}
+	available := UserAvailableAccount(userID)
+	held := UserHeldAccount(userID)
+	amount := func(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+	tests := []struct {
+		name    string
+		lines   []JournalLineRequest
+		wantErr bool
+		wantIs  error
+	}{
+		{"top-up", []JournalLineRequest{
+			{Account: cash, Debit: amount("100000")},
+			{Account: available, Credit: amount("100000")},
+		}, false, nil},
+		{"split across several lines", []JournalLineRequest{
+			{Account: available, Debit: amount("100000")},
+			{Account: held, Credit: amount("60000")},
+			{Account: cash, Credit: amount("40000")},
+		}, false, nil},
+		{"single line", []JournalLineRequest{
+			{Account: cash, Debit: amount("100000")},
+		}, true, nil},
+		{"debits and credits differ", []JournalLineRequest{
+			{Account: cash, Debit: amount("100000")},
+			{Account: available, Credit: amount("99999.99")},
+		}, true, ErrUnbalancedEntry},
+		{"negative amount", []JournalLineRequest{
+			{Account: cash, Debit: amount("-100")},
+			{Account: available, Credit: amount("-100")},
+		}, true, nil},
+		{"line both debits and credits", []JournalLineRequest{
+			{Account: cash, Debit: amount("100"), Credit: amount("100")},
+			{Account: available, Credit: amount("100")},
+		}, true, nil},
+		{"empty line", []JournalLineRequest{
+			{Account: cash},
+			{Account: available},
+		}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBalanced(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkBalanced error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("checkBalanced error = %v, want %v", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestAccountBalance(t *testing.T) {
+	debits, credits := decimal.RequireFromString("150000"), decimal.RequireFromString("50000")
+
+	tests := []struct {
+		accountType model.LedgerAccountType
+		want        string
+	}{
+		{model.LedgerAccountAsset, "100000"},
+		{model.LedgerAccountLiability, "-100000"},
+		{model.LedgerAccountRevenue, "-100000"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.accountType), func(t *testing.T) {
+			assertDecimal(t, "balance", accountBalance(tt.accountType, debits, credits), tt.want)
+		})
+	}
+}
+
+// A balanced entry leaves the sum of asset balances equal to the sum of
+// liability and revenue balances
+func TestBalancedEntryKeepsLedgerEquation(t *testing.T) {
+	types := map[string]model.LedgerAccountType{
+		"cash":   model.LedgerAccountAsset,
+		"wallet": model.LedgerAccountLiability,
+		"fees":   model.LedgerAccountRevenue,
+	}
+	type line struct {
+		account       string
+		debit, credit string
+	}
+	entries := [][]line{
+		{{"cash", "500000", "0"}, {"wallet", "0", "500000"}},
+		{{"wallet", "120000", "0"}, {"cash", "0", "100000"}, {"fees", "0", "20000"}},
+	}
+
+	debits := map[string]decimal.Decimal{}
+	credits := map[string]decimal.Decimal{}
+	for _, entry := range entries {
+		lines := make([]JournalLineRequest, 0, len(entry))
+		for _, l := range entry {
+			lines = append(lines, JournalLineRequest{
+				Account: LedgerAccountRef{Code: l.account, Type: types[l.account]},
+				Debit:   decimal.RequireFromString(l.debit),
+				Credit:  decimal.RequireFromString(l.credit),
+			})
+			debits[l.account] = debits[l.account].Add(decimal.RequireFromString(l.debit))
+			credits[l.account] = credits[l.account].Add(decimal.RequireFromString(l.credit))
+		}
+		if err := checkBalanced(lines); err != nil {
+			t.Fatalf("entry rejected: %v", err)
+		}
+	}
+
+	assets, claims := decimal.Zero, decimal.Zero
+	for account, accountType := range types {
+		balance := accountBalance(accountType, debits[account], credits[account])
+		if accountType == model.LedgerAccountAsset {
+			assets = assets.Add(balance)
+		} else {
+			claims = claims.Add(balance)
+		}
+	}
+	if !assets.Equal(claims) {
+		t.Errorf("assets %s != liabilities and revenue %s", assets, claims)
+	}
+}
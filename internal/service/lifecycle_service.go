@@ -0,0 +1,337 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// lifecycleBatchSize bounds the items one run transitions. A backlog left by
+// downtime is worked off over consecutive runs.
+const lifecycleBatchSize = 100
+
+// ========== AUCTION LIFECYCLE ==========
+
+// StartDueAuctions moves published items to ongoing once AuctionStart passes
+func (s *auctionService) StartDueAuctions(now time.Time) (int, error) {
+	ids, err := s.itemRepo.FindDueToStart(now, lifecycleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	started := 0
+	for _, id := range ids {
+		changed, err := s.startAuction(id, now)
+		if err != nil {
+			log.Printf("Failed to start auction item %d: %v", id, err)
+			continue
+		}
+		if changed {
+			started++
+		}
+	}
+	return started, nil
+}
+
+// CloseDueAuctions closes every running item whose AuctionEnd has passed,
+// including ends missed while no scheduler was running. Items left open by a
+// failure or another replica's lock are paged past rather than fetched
+// again, so they never hold back the items behind them; the next run retries
+// them.
+func (s *auctionService) CloseDueAuctions(now time.Time) (int, error) {
+	closed := 0
+	var after *model.AuctionSchedule
+	for closed < lifecycleBatchSize {
+		due, err := s.itemRepo.FindDueToClose(now, after, lifecycleBatchSize)
+		if err != nil {
+			return closed, err
+		}
+
+		for _, d := range due {
+			changed, err := s.closeAuction(d.ItemID, now)
+			if err != nil {
+				log.Printf("Failed to close auction item %d: %v", d.ItemID, err)
+				continue
+			}
+			if changed {
+				closed++
+			}
+		}
+		if len(due) < lifecycleBatchSize {
+			break
+		}
+		after = &due[len(due)-1]
+	}
+	return closed, nil
+}
+
+// startAuction transitions one item. Rows locked elsewhere are skipped, so
+// replicas running the same job never wait on each other; the state is
+// re-checked under the lock because another replica may already have acted.
+func (s *auctionService) startAuction(itemID uint, now time.Time) (bool, error) {
+	changed := false
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+
+		item, err := itemRepo.FindByIDForUpdateSkipLocked(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if item.Status != model.AuctionStatusPublished || item.Schedule == nil ||
+			now.Before(item.Schedule.AuctionStart) || !now.Before(item.Schedule.AuctionEnd) {
+			return nil
+		}
+
+		changed = true
+		s.publishStatusTx(tx, item.ID, model.AuctionStatusOngoing, nil)
+		return itemRepo.UpdateStatus(item.ID, model.AuctionStatusOngoing)
+	})
+	return changed, err
+}
+
+// closeAuction closes one item and settles its bids. Sealed lots are opened
+// instead. Holding the item lock also serialises against late bids and
+// soft-close extensions.
+func (s *auctionService) closeAuction(itemID uint, now time.Time) (bool, error) {
+	changed := false
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+
+		item, err := itemRepo.FindByIDForUpdateSkipLocked(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return nil
+		}
+		// A soft close may have extended the end since the item was listed
+		if item.Schedule == nil || now.Before(item.Schedule.AuctionEnd) || item.AuctionMethod == model.AuctionMethodTender {
+			return nil
+		}
+
+		changed = true
+		if isSealed(item) {
+			_, err := s.revealSealedBidsTx(tx, item, now)
+			return err
+		}
+
+		// Remaining maximums have nothing left to bid on
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+		proxies, err := proxyRepo.FindActiveByItem(item.ID)
+		if err != nil {
+			return err
+		}
+		for _, p := range proxies {
+			if err := proxyRepo.UpdateStatus(p.ID, model.ProxyBidStatusExhausted); err != nil {
+				return err
+			}
+		}
+
+		return s.settleOpenBidsTx(tx, item, now)
+	})
+	return changed, err
+}
+
+// settleOpenBidsTx decides an open-bidding lot at close. The leading bid wins
+// only if it reaches the limit price; otherwise the lot is unsold and every
+// bid is lost.
+func (s *auctionService) settleOpenBidsTx(tx *gorm.DB, item *model.AuctionItem, now time.Time) error {
+	bidRepo := s.bidRepo.WithTx(tx)
+
+	winner, err := bidRepo.FindWinningBid(item.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := s.settleBidHoldsTx(tx, item, nil); err != nil {
+			return err
+		}
+		return s.closeItemTx(tx, item, model.AuctionOutcomeUnsold, "no bids were placed", now)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !reserveMet(item, winner.BidAmount) {
+		if err := bidRepo.MarkAllAsLost(item.ID, 0); err != nil {
+			return err
+		}
+		if err := s.settleBidHoldsTx(tx, item, nil); err != nil {
+			return err
+		}
+		return s.closeItemTx(tx, item, model.AuctionOutcomeUnsold, unmetReserveReason(winner.BidAmount), now)
+	}
+
+	if err := bidRepo.UpdateStatus(winner.ID, model.BidStatusWon); err != nil {
+		return err
+	}
+	if err := bidRepo.MarkAllAsLost(item.ID, winner.ID); err != nil {
+		return err
+	}
+	if err := s.settleBidHoldsTx(tx, item, winner); err != nil {
+		return err
+	}
+	return s.closeItemTx(tx, item, model.AuctionOutcomeSold, "", now)
+}
+
+// CloseTenderTx closes a tender lot whose evaluation was just published. bids
+// holds one bid per ranked offer, best first; the top offer wins only if it
+// reaches the limit price. The caller must hold the item row lock.
+func (s *auctionService) CloseTenderTx(tx *gorm.DB, item *model.AuctionItem, bids []model.Bid, now time.Time) error {
+	bidRepo := s.bidRepo.WithTx(tx)
+
+	var winner *model.Bid
+	for i := range bids {
+		bid := &bids[i]
+		bid.ItemID = item.ID
+		bid.BidType = model.BidTypeTender
+		bid.BidStatus = model.BidStatusLost
+		if i == 0 && reserveMet(item, bid.BidAmount) {
+			bid.BidStatus = model.BidStatusWon
+			bid.IsHighest = true
+			winner = bid
+		}
+		if err := bidRepo.Create(bid); err != nil {
+			return err
+		}
+	}
+
+	if err := s.settleBidHoldsTx(tx, item, winner); err != nil {
+		return err
+	}
+	if winner == nil {
+		reason := "no offers were submitted"
+		if len(bids) > 0 {
+			reason = unmetReserveReason(bids[0].BidAmount)
+		}
+		return s.closeItemTx(tx, item, model.AuctionOutcomeUnsold, reason, now)
+	}
+	return s.closeItemTx(tx, item, model.AuctionOutcomeSold, "", now)
+}
+
+// settleBidHoldsTx opens the winner's settlement, which keeps their bid hold
+// at the outstanding amount, and releases every other bid hold on the item.
+// Deposits are returned to everyone but the winner. winner is nil when the
+// lot goes unsold.
+func (s *auctionService) settleBidHoldsTx(tx *gorm.DB, item *model.AuctionItem, winner *model.Bid) error {
+	keepUserID := ""
+	if winner != nil {
+		if _, err := s.settlement.OpenSettlementTx(tx, item, winner, time.Now()); err != nil {
+			return err
+		}
+		keepUserID = winner.UserID
+	}
+	if err := s.fundsService.ReleaseItemHoldsTx(tx, item.ID, model.HoldKindBid, keepUserID); err != nil {
+		return err
+	}
+	return s.participation.SettleDepositsTx(tx, item.ID, keepUserID)
+}
+
+// closeItemTx closes the item with its outcome; item is updated in place
+func (s *auctionService) closeItemTx(tx *gorm.DB, item *model.AuctionItem, outcome model.AuctionOutcome, reason string, now time.Time) error {
+	if err := s.itemRepo.WithTx(tx).CloseWithOutcome(item.ID, outcome, stringPtr(reason), now); err != nil {
+		return err
+	}
+	item.Status = model.AuctionStatusClosed
+	item.Outcome = &outcome
+	item.OutcomeReason = stringPtr(reason)
+	item.ClosedAt = &now
+	s.publishStatusTx(tx, item.ID, model.AuctionStatusClosed, &outcome)
+	return s.notifications.NotifyAuctionClosedTx(tx, item)
+}
+
+// CancelAuction withdraws a published or running lot. Bids stay on record
+// but every bid hold and deposit is released, and everyone who bid or
+// registered is told why.
+func (s *auctionService) CancelAuction(req CancelAuctionRequest) (*model.AuctionItem, error) {
+	var item *model.AuctionItem
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+
+		var err error
+		item, err = itemRepo.FindByIDForUpdate(req.ItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+		if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return errors.New("only published or ongoing auctions can be cancelled")
+		}
+		if item.AuctionMethod == model.AuctionMethodTender {
+			return errors.New("tender lots are handled by their committee")
+		}
+
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+		proxies, err := proxyRepo.FindActiveByItem(item.ID)
+		if err != nil {
+			return err
+		}
+		for _, p := range proxies {
+			if err := proxyRepo.UpdateStatus(p.ID, model.ProxyBidStatusExhausted); err != nil {
+				return err
+			}
+		}
+		if err := s.settleBidHoldsTx(tx, item, nil); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := itemRepo.Cancel(item.ID, req.Reason, now); err != nil {
+			return err
+		}
+		item.Status = model.AuctionStatusCancelled
+		item.OutcomeReason = stringPtr(req.Reason)
+		item.ClosedAt = &now
+		s.publishStatusTx(tx, item.ID, model.AuctionStatusCancelled, nil)
+
+		return s.notifications.NotifyAuctionCancelledTx(tx, item, req.Reason)
+	})
+	if err != nil {
+		if isRetryableTxError(err) {
+			return nil, ErrBidConflict
+		}
+		return nil, err
+	}
+	log.Printf("Auction item %d cancelled by %s: %s", item.ID, req.AdminID, req.Reason)
+	return item, nil
+}
+
+// ========== RESERVE ==========
+
+// reserveMet reports whether a bid amount reaches the item's limit price
+func reserveMet(item *model.AuctionItem, amount decimal.Decimal) bool {
+	return amount.GreaterThanOrEqual(item.LimitPrice)
+}
+
+// unmetReserveReason explains an unsold outcome without disclosing the limit
+func unmetReserveReason(highest decimal.Decimal) string {
+	return fmt.Sprintf("highest bid of %s did not meet the limit price", highest.StringFixed(2))
+}
+
+// ReserveStatus tells bidders whether the current price meets the limit price
+// without disclosing the limit itself. It is nil while sealed bids are closed.
+func ReserveStatus(item *model.AuctionItem) *bool {
+	met := false
+	switch {
+	case item.Outcome != nil:
+		met = *item.Outcome == model.AuctionOutcomeSold
+	case isSealed(item):
+		return nil
+	case item.BidCount > 0:
+		met = reserveMet(item, item.CurrentHighestBid)
+	}
+	return &met
+}
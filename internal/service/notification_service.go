@@ -0,0 +1,601 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+	"yourapp/internal/util"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// outbidCooldown keeps a bidding war from sending an email per bid: a
+	// bidder hears about being outbid on a lot at most once per window
+	outbidCooldown = 15 * time.Minute
+
+	// endingSoonWindow is how long before the end bidders are reminded
+	endingSoonWindow = time.Hour
+
+	notificationMailBatchSize = 100
+)
+
+// NotificationService raises notifications for users, keeps them in their
+// in-app notification center, pushes new ones to the live feed and queues
+// them as emails. Raising is idempotent per dedup key, so retried jobs and
+// replicas running the same job never notify twice.
+type NotificationService interface {
+	NotifyOutbidTx(tx *gorm.DB, item *model.AuctionItem, outbid *model.Bid) error
+	NotifyAuctionClosedTx(tx *gorm.DB, item *model.AuctionItem) error
+	NotifyAuctionCancelledTx(tx *gorm.DB, item *model.AuctionItem, reason string) error
+	NotifyBidCancelledTx(tx *gorm.DB, item *model.AuctionItem, cancellation *model.BidCancellation) error
+	NotifyEndingSoon(now time.Time) (int, error)
+	NotifyWatcher(item *model.AuctionItem, userID string, notificationType model.NotificationType) (bool, error)
+	NotifyDocumentIssuedTx(tx *gorm.DB, document *model.Document) error
+	NotifySavedSearchMatchTx(tx *gorm.DB, search *model.SavedSearch, item *model.AuctionItem) (bool, error)
+	NotifySavedSearchDigestTx(tx *gorm.DB, search *model.SavedSearch, matches []model.SavedSearchMatch) (bool, error)
+	EmailPendingNotifications() (int, error)
+	AttachRabbitMQ(rabbitMQ *util.RabbitMQClient)
+
+	// Notification center
+	GetMyNotifications(userID string, page, limit int) ([]NotificationView, int64, int64, error)
+	MarkRead(id uint, userID string) (*NotificationView, error)
+	MarkAllRead(userID string) (int64, error)
+}
+
+// NotificationPayload is what a notification shows, as of when it was raised
+type NotificationPayload struct {
+	ItemName     string           `json:"item_name"`
+	LotCode      string           `json:"lot_code"`
+	Amount       *decimal.Decimal `json:"amount,omitempty"`
+	AuctionStart *time.Time       `json:"auction_start,omitempty"`
+	AuctionEnd   *time.Time       `json:"auction_end,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+
+	// Issued documents
+	DocumentID     uint   `json:"document_id,omitempty"`
+	DocumentType   string `json:"document_type,omitempty"`
+	DocumentNumber string `json:"document_number,omitempty"`
+
+	// Saved-search alerts
+	SearchID         uint              `json:"search_id,omitempty"`
+	SearchName       string            `json:"search_name,omitempty"`
+	Lots             []NotificationLot `json:"lots,omitempty"` // digest only
+	UnsubscribeToken string            `json:"unsubscribe_token,omitempty"`
+}
+
+// NotificationLot is one lot listed in a saved-search digest
+type NotificationLot struct {
+	ItemID       uint       `json:"item_id"`
+	ItemName     string     `json:"item_name"`
+	LotCode      string     `json:"lot_code"`
+	AuctionStart *time.Time `json:"auction_start,omitempty"`
+}
+
+// NotificationView is a notification as the notification center and the live
+// feed show it. Link is the client path of the related lot or document.
+type NotificationView struct {
+	ID        uint                   `json:"id"`
+	Type      model.NotificationType `json:"type"`
+	ItemID    *uint                  `json:"item_id,omitempty"`
+	BidID     *uint                  `json:"bid_id,omitempty"`
+	Payload   json.RawMessage        `json:"payload"`
+	Link      string                 `json:"link,omitempty"`
+	Read      bool                   `json:"read"`
+	ReadAt    *time.Time             `json:"read_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func newNotificationView(notification *model.Notification) NotificationView {
+	view := NotificationView{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		ItemID:    notification.ItemID,
+		BidID:     notification.BidID,
+		Payload:   json.RawMessage(notification.Payload),
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+
+	var payload NotificationPayload
+	_ = json.Unmarshal(view.Payload, &payload)
+	switch {
+	case payload.DocumentID != 0:
+		view.Link = fmt.Sprintf("/documents/%d", payload.DocumentID)
+	case notification.ItemID != nil:
+		view.Link = fmt.Sprintf("/auctions/%d", *notification.ItemID)
+	case notification.Type == model.NotificationSavedSearchDaily:
+		view.Link = "/auctions"
+	}
+	return view
+}
+
+type notificationService struct {
+	transactor       repository.Transactor
+	notificationRepo repository.NotificationRepository
+	itemRepo         repository.AuctionItemRepository
+	bidRepo          repository.BidRepository
+	participantRepo  repository.ParticipantRepository
+	userRepo         repository.UserRepository
+	events           AuctionEventPublisher
+
+	rabbitMu sync.RWMutex
+	rabbitMQ *util.RabbitMQClient
+}
+
+func NewNotificationService(
+	transactor repository.Transactor,
+	notificationRepo repository.NotificationRepository,
+	itemRepo repository.AuctionItemRepository,
+	bidRepo repository.BidRepository,
+	participantRepo repository.ParticipantRepository,
+	userRepo repository.UserRepository,
+	rabbitMQ *util.RabbitMQClient,
+	events AuctionEventPublisher,
+) NotificationService {
+	return &notificationService{
+		transactor:       transactor,
+		notificationRepo: notificationRepo,
+		itemRepo:         itemRepo,
+		bidRepo:          bidRepo,
+		participantRepo:  participantRepo,
+		userRepo:         userRepo,
+		rabbitMQ:         rabbitMQ,
+		events:           events,
+	}
+}
+
+// ========== RAISING ==========
+
+// NotifyOutbidTx tells the bidder of outbid that they lost the lead. The
+// caller holds the item lock, so the cooldown check can't race another bid.
+func (s *notificationService) NotifyOutbidTx(tx *gorm.DB, item *model.AuctionItem, outbid *model.Bid) error {
+	notificationRepo := s.notificationRepo.WithTx(tx)
+
+	recent, err := notificationRepo.ExistsSince(outbid.UserID, item.ID, model.NotificationOutbid, time.Now().Add(-outbidCooldown))
+	if err != nil || recent {
+		return err
+	}
+
+	payload := lotPayload(item)
+	payload.Amount = &item.CurrentHighestBid
+	_, err = s.raiseTx(tx, outbid.UserID, model.NotificationOutbid, item.ID, &outbid.ID,
+		fmt.Sprintf("outbid:%d", outbid.ID), payload)
+	return err
+}
+
+// NotifyAuctionClosedTx tells the winner they won and every other bidder they
+// lost. On an unsold lot everyone lost.
+func (s *notificationService) NotifyAuctionClosedTx(tx *gorm.DB, item *model.AuctionItem) error {
+	bids, err := s.bidRepo.WithTx(tx).FindValidByItemID(item.ID)
+	if err != nil {
+		return err
+	}
+
+	var winner *model.Bid
+	best := make(map[string]*model.Bid)
+	for i := range bids {
+		bid := &bids[i]
+		if bid.BidStatus == model.BidStatusWon {
+			winner = bid
+		}
+		if current, ok := best[bid.UserID]; !ok || bid.BidAmount.GreaterThan(current.BidAmount) {
+			best[bid.UserID] = bid
+		}
+	}
+
+	for userID, bid := range best {
+		payload := lotPayload(item)
+		if winner != nil && userID == winner.UserID {
+			payload.Amount = &winner.BidAmount
+			if _, err := s.raiseTx(tx, userID, model.NotificationWon, item.ID, &winner.ID,
+				fmt.Sprintf("won:%d:%s", item.ID, userID), payload); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if winner == nil {
+			payload.Reason = "Lot tidak terjual karena penawaran tertinggi belum mencapai nilai limit."
+		}
+		if _, err := s.raiseTx(tx, userID, model.NotificationLost, item.ID, &bid.ID,
+			fmt.Sprintf("lost:%d:%s", item.ID, userID), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyAuctionCancelledTx tells everyone who bid on or registered for the
+// lot that it was withdrawn
+func (s *notificationService) NotifyAuctionCancelledTx(tx *gorm.DB, item *model.AuctionItem, reason string) error {
+	bids, err := s.bidRepo.WithTx(tx).FindValidByItemID(item.ID)
+	if err != nil {
+		return err
+	}
+	participants, err := s.participantRepo.WithTx(tx).FindByItemID(item.ID)
+	if err != nil {
+		return err
+	}
+
+	users := make(map[string]bool)
+	for _, bid := range bids {
+		users[bid.UserID] = true
+	}
+	for _, participant := range participants {
+		users[participant.UserID] = true
+	}
+
+	payload := lotPayload(item)
+	payload.Reason = reason
+	for userID := range users {
+		if _, err := s.raiseTx(tx, userID, model.NotificationAuctionCancelled, item.ID, nil,
+			fmt.Sprintf("auction_cancelled:%d:%s", item.ID, userID), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyBidCancelledTx tells the bidder that an admin voided their bid, and
+// with it their win if the lot had already closed
+func (s *notificationService) NotifyBidCancelledTx(tx *gorm.DB, item *model.AuctionItem, cancellation *model.BidCancellation) error {
+	payload := lotPayload(item)
+	payload.Amount = &cancellation.BidAmount
+	payload.Reason = cancellation.Reason
+	_, err := s.raiseTx(tx, cancellation.BidderID, model.NotificationBidCancelled, item.ID, &cancellation.BidID,
+		fmt.Sprintf("bid_cancelled:%d", cancellation.BidID), payload)
+	return err
+}
+
+// NotifyEndingSoon reminds the bidders of every lot ending within the next
+// hour, once per bidder and lot
+func (s *notificationService) NotifyEndingSoon(now time.Time) (int, error) {
+	ids, err := s.itemRepo.FindEndingBetween(now, now.Add(endingSoonWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, id := range ids {
+		item, err := s.itemRepo.FindByID(id)
+		if err != nil {
+			continue
+		}
+		bids, err := s.bidRepo.FindValidByItemID(id)
+		if err != nil {
+			log.Printf("Notifications: failed to load bids of item %d: %v", id, err)
+			continue
+		}
+
+		payload := lotPayload(item)
+		if !isSealed(item) {
+			payload.Amount = &item.CurrentHighestBid
+		}
+		notified := make(map[string]bool)
+		for _, bid := range bids {
+			if notified[bid.UserID] {
+				continue
+			}
+			notified[bid.UserID] = true
+
+			created, err := s.raiseTx(nil, bid.UserID, model.NotificationEndingSoon, item.ID, nil,
+				fmt.Sprintf("ending_soon:%d:%s", item.ID, bid.UserID), payload)
+			if err != nil {
+				log.Printf("Notifications: failed to remind user %s of item %d: %v", bid.UserID, id, err)
+				continue
+			}
+			if created {
+				raised++
+			}
+		}
+	}
+	return raised, nil
+}
+
+// NotifyWatcher reminds a watcher that the lot is about to start or end, once
+// per watcher, lot and reminder type
+func (s *notificationService) NotifyWatcher(item *model.AuctionItem, userID string, notificationType model.NotificationType) (bool, error) {
+	payload := lotPayload(item)
+	if notificationType == model.NotificationWatchEnding && item.BidCount > 0 && !isSealed(item) {
+		payload.Amount = &item.CurrentHighestBid
+	}
+	return s.raiseTx(nil, userID, notificationType, item.ID, nil,
+		fmt.Sprintf("%s:%d:%s", notificationType, item.ID, userID), payload)
+}
+
+// NotifyDocumentIssuedTx puts a newly issued invoice or receipt in its
+// owner's notification center. The document mailer sends the email with the
+// document attached, so the notification is stored as already emailed.
+func (s *notificationService) NotifyDocumentIssuedTx(tx *gorm.DB, document *model.Document) error {
+	if document.OwnerID == nil {
+		return nil
+	}
+
+	payload := NotificationPayload{
+		DocumentID:     document.ID,
+		DocumentType:   string(document.DocumentType),
+		DocumentNumber: document.DocumentNumber,
+	}
+	if document.ItemID != nil {
+		if item, err := s.itemRepo.WithTx(tx).FindByID(*document.ItemID); err == nil {
+			payload.ItemName = item.ItemName
+			payload.LotCode = item.LotCode
+		}
+	}
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	emailedAt := time.Now()
+	_, err = s.createTx(tx, &model.Notification{
+		UserID:    *document.OwnerID,
+		Type:      model.NotificationDocumentIssued,
+		ItemID:    document.ItemID,
+		Payload:   string(content),
+		DedupKey:  fmt.Sprintf("document:%d", document.ID),
+		EmailedAt: &emailedAt,
+	})
+	return err
+}
+
+// NotifySavedSearchMatchTx alerts the owner of an instant saved search to a
+// newly published lot. A lot matching several of the user's searches is
+// alerted once.
+func (s *notificationService) NotifySavedSearchMatchTx(tx *gorm.DB, search *model.SavedSearch, item *model.AuctionItem) (bool, error) {
+	payload := lotPayload(item)
+	payload.Amount = &item.StartingPrice
+	payload.SearchID = search.ID
+	payload.SearchName = search.Name
+	payload.UnsubscribeToken = search.UnsubscribeToken
+	return s.raiseTx(tx, search.UserID, model.NotificationSavedSearchMatch, item.ID, nil,
+		fmt.Sprintf("saved_search_match:%d:%s", item.ID, search.UserID), payload)
+}
+
+// NotifySavedSearchDigestTx sends the owner of a digest saved search one
+// alert listing matches. The first match keys the digest, so a batch is never
+// sent twice.
+func (s *notificationService) NotifySavedSearchDigestTx(tx *gorm.DB, search *model.SavedSearch, matches []model.SavedSearchMatch) (bool, error) {
+	if len(matches) == 0 {
+		return false, nil
+	}
+
+	payload := NotificationPayload{
+		SearchID:         search.ID,
+		SearchName:       search.Name,
+		UnsubscribeToken: search.UnsubscribeToken,
+	}
+	for _, match := range matches {
+		if match.Item == nil {
+			continue
+		}
+		lot := NotificationLot{
+			ItemID:   match.ItemID,
+			ItemName: match.Item.ItemName,
+			LotCode:  match.Item.LotCode,
+		}
+		if match.Item.Schedule != nil {
+			start := match.Item.Schedule.AuctionStart
+			lot.AuctionStart = &start
+		}
+		payload.Lots = append(payload.Lots, lot)
+	}
+	if len(payload.Lots) == 0 {
+		return false, nil
+	}
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	return s.createTx(tx, &model.Notification{
+		UserID:   search.UserID,
+		Type:     model.NotificationSavedSearchDaily,
+		Payload:  string(content),
+		DedupKey: fmt.Sprintf("saved_search_digest:%d:%d", search.ID, matches[0].ID),
+	})
+}
+
+// raiseTx stores a notification unless its dedup key was used before. tx may
+// be nil outside a transaction.
+func (s *notificationService) raiseTx(tx *gorm.DB, userID string, notificationType model.NotificationType, itemID uint, bidID *uint, dedupKey string, payload NotificationPayload) (bool, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	return s.createTx(tx, &model.Notification{
+		UserID:   userID,
+		Type:     notificationType,
+		ItemID:   &itemID,
+		BidID:    bidID,
+		Payload:  string(content),
+		DedupKey: dedupKey,
+	})
+}
+
+// createTx stores the notification once per dedup key and pushes it to the
+// recipient's live feed after tx commits
+func (s *notificationService) createTx(tx *gorm.DB, notification *model.Notification) (bool, error) {
+	notificationRepo := s.notificationRepo
+	if tx != nil {
+		notificationRepo = notificationRepo.WithTx(tx)
+	}
+	created, err := notificationRepo.CreateOnce(notification)
+	if err != nil || !created || s.events == nil {
+		return created, err
+	}
+
+	event := AuctionEvent{
+		Type:        AuctionEventNotification,
+		Data:        newNotificationView(notification),
+		RecipientID: notification.UserID,
+		At:          notification.CreatedAt,
+	}
+	if notification.ItemID != nil {
+		event.ItemID = *notification.ItemID
+	}
+	repository.AfterCommit(tx, func() {
+		s.events.PublishAuctionEvent(event)
+	})
+	return true, nil
+}
+
+func lotPayload(item *model.AuctionItem) NotificationPayload {
+	payload := NotificationPayload{
+		ItemName: item.ItemName,
+		LotCode:  item.LotCode,
+	}
+	if item.Schedule != nil {
+		start, end := item.Schedule.AuctionStart, item.Schedule.AuctionEnd
+		payload.AuctionStart = &start
+		payload.AuctionEnd = &end
+	}
+	return payload
+}
+
+// ========== EMAIL ==========
+
+// AttachRabbitMQ starts queueing emails, e.g. once a connection that failed
+// at startup comes up
+func (s *notificationService) AttachRabbitMQ(rabbitMQ *util.RabbitMQClient) {
+	s.rabbitMu.Lock()
+	s.rabbitMQ = rabbitMQ
+	s.rabbitMu.Unlock()
+}
+
+func (s *notificationService) mailQueue() *util.RabbitMQClient {
+	s.rabbitMu.RLock()
+	defer s.rabbitMu.RUnlock()
+	return s.rabbitMQ
+}
+
+// EmailPendingNotifications queues the email of every notification not yet
+// sent. Each one is claimed under a skip-locked row lock, so replicas share
+// the work without sending twice.
+func (s *notificationService) EmailPendingNotifications() (int, error) {
+	rabbitMQ := s.mailQueue()
+	if rabbitMQ == nil {
+		return 0, nil
+	}
+	pending, err := s.notificationRepo.FindUnemailed(notificationMailBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, candidate := range pending {
+		id := candidate.ID
+		err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			notificationRepo := s.notificationRepo.WithTx(tx)
+			notification, err := notificationRepo.FindByIDForUpdateSkipLocked(id)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if notification.EmailedAt != nil {
+				return nil
+			}
+
+			user, err := s.userRepo.WithTx(tx).FindByID(notification.UserID)
+			if err != nil {
+				return err
+			}
+			var payload NotificationPayload
+			if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+				return err
+			}
+
+			email := &util.AuctionEmail{
+				Name:         user.FullName,
+				ItemName:     payload.ItemName,
+				LotCode:      payload.LotCode,
+				AuctionStart: payload.AuctionStart,
+				AuctionEnd:   payload.AuctionEnd,
+				Reason:       payload.Reason,
+
+				SearchName:       payload.SearchName,
+				UnsubscribeToken: payload.UnsubscribeToken,
+			}
+			for _, lot := range payload.Lots {
+				email.Lots = append(email.Lots, util.AuctionEmailLot{
+					ItemID:       lot.ItemID,
+					ItemName:     lot.ItemName,
+					LotCode:      lot.LotCode,
+					AuctionStart: lot.AuctionStart,
+				})
+			}
+			if notification.ItemID != nil {
+				email.ItemID = *notification.ItemID
+			}
+			if payload.Amount != nil {
+				email.Amount = formatRupiah(*payload.Amount)
+			}
+
+			if err := rabbitMQ.PublishEmail(util.EmailMessage{
+				To:      user.Email,
+				Type:    string(notification.Type),
+				Auction: email,
+			}); err != nil {
+				return err
+			}
+			if err := notificationRepo.MarkEmailed(notification.ID, time.Now()); err != nil {
+				return err
+			}
+			sent++
+			return nil
+		})
+		if err != nil {
+			log.Printf("Notifications: failed to email notification #%d: %v", id, err)
+		}
+	}
+	return sent, nil
+}
+
+// ========== NOTIFICATION CENTER ==========
+
+// GetMyNotifications returns a page of the user's notifications, newest
+// first, with the total and the number still unread
+func (s *notificationService) GetMyNotifications(userID string, page, limit int) ([]NotificationView, int64, int64, error) {
+	notifications, total, err := s.notificationRepo.FindByUser(userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	views := make([]NotificationView, len(notifications))
+	for i := range notifications {
+		views[i] = newNotificationView(&notifications[i])
+	}
+	return views, total, unread, nil
+}
+
+func (s *notificationService) MarkRead(id uint, userID string) (*NotificationView, error) {
+	notification, err := s.notificationRepo.MarkRead(id, userID, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+	view := newNotificationView(notification)
+	return &view, nil
+}
+
+func (s *notificationService) MarkAllRead(userID string) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID, time.Now())
+}
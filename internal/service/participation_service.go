@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrNotParticipant is returned when a user bids on a lot without a paid deposit
+var ErrNotParticipant = errors.New("register for this lot and pay the deposit before bidding")
+
+// ParticipationService runs lot registration and the deposit (jaminan)
+// workflow. A user registers inside the schedule's registration window, pays
+// the deposit before DepositDeadline and then receives a participant number
+// (NUP). Only numbered participants may bid.
+type ParticipationService interface {
+	Register(itemID uint, userID string) (*model.AuctionParticipant, error)
+	PayDeposit(itemID uint, userID string) (*model.AuctionParticipant, error)
+	GetMyParticipation(itemID uint, userID string) (*model.AuctionParticipant, error)
+	GetMyParticipations(userID string) ([]model.AuctionParticipant, error)
+	GetParticipants(itemID uint) ([]model.AuctionParticipant, error)
+
+	// Used by bidding and close inside their transactions
+	RequireEligibleTx(tx *gorm.DB, itemID uint, userID string) error
+	SettleDepositsTx(tx *gorm.DB, itemID uint, keepUserID string) error
+
+	// Used by settlement for the winner's deposit
+	HeldDepositTx(tx *gorm.DB, itemID uint, userID string) (decimal.Decimal, error)
+	ApplyDepositTx(tx *gorm.DB, itemID uint, userID string, amount decimal.Decimal) error
+	ForfeitDepositTx(tx *gorm.DB, itemID uint, userID string) error
+}
+
+type participationService struct {
+	transactor      repository.Transactor
+	participantRepo repository.ParticipantRepository
+	itemRepo        repository.AuctionItemRepository
+	fundsService    FundsService
+}
+
+func NewParticipationService(
+	transactor repository.Transactor,
+	participantRepo repository.ParticipantRepository,
+	itemRepo repository.AuctionItemRepository,
+	fundsService FundsService,
+) ParticipationService {
+	return &participationService{
+		transactor:      transactor,
+		participantRepo: participantRepo,
+		itemRepo:        itemRepo,
+		fundsService:    fundsService,
+	}
+}
+
+// Register signs the user up for a lot. Lots without a deposit number the
+// participant straight away.
+func (s *participationService) Register(itemID uint, userID string) (*model.AuctionParticipant, error) {
+	var participant *model.AuctionParticipant
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := validateRegistrationWindow(item, now); err != nil {
+			return err
+		}
+
+		participantRepo := s.participantRepo.WithTx(tx)
+		if _, err := participantRepo.FindByItemAndUser(itemID, userID); err == nil {
+			return errors.New("already registered for this lot")
+		}
+
+		participant = &model.AuctionParticipant{
+			ItemID:        itemID,
+			UserID:        userID,
+			Status:        model.ParticipantStatusRegistered,
+			DepositAmount: item.DepositAmount,
+			RegisteredAt:  now,
+		}
+		if !item.DepositAmount.IsPositive() {
+			if err := s.assignNumberTx(tx, item, participant, now); err != nil {
+				return err
+			}
+		}
+		return participantRepo.Create(participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+// PayDeposit holds the deposit in the user's wallet and issues their NUP
+func (s *participationService) PayDeposit(itemID uint, userID string) (*model.AuctionParticipant, error) {
+	var participant *model.AuctionParticipant
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		// The item lock serialises NUP numbering for the lot
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+		if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return errors.New("auction is not accepting deposits")
+		}
+
+		participantRepo := s.participantRepo.WithTx(tx)
+		participant, err = participantRepo.FindByItemAndUserForUpdate(itemID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("register for this lot before paying the deposit")
+			}
+			return err
+		}
+		if participant.Status != model.ParticipantStatusRegistered {
+			return errors.New("deposit already paid")
+		}
+
+		now := time.Now()
+		if item.Schedule != nil && now.After(item.Schedule.DepositDeadline) {
+			return errors.New("deposit deadline has passed")
+		}
+
+		if err := s.fundsService.SetHoldTx(tx, userID, itemID, model.HoldKindDeposit, participant.DepositAmount); err != nil {
+			return err
+		}
+		if err := s.assignNumberTx(tx, item, participant, now); err != nil {
+			return err
+		}
+		return participantRepo.Update(participant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+func (s *participationService) GetMyParticipation(itemID uint, userID string) (*model.AuctionParticipant, error) {
+	return s.participantRepo.FindByItemAndUser(itemID, userID)
+}
+
+func (s *participationService) GetMyParticipations(userID string) ([]model.AuctionParticipant, error) {
+	return s.participantRepo.FindByUserID(userID)
+}
+
+func (s *participationService) GetParticipants(itemID uint) ([]model.AuctionParticipant, error) {
+	return s.participantRepo.FindByItemID(itemID)
+}
+
+// RequireEligibleTx fails unless the user holds a NUP for the item
+func (s *participationService) RequireEligibleTx(tx *gorm.DB, itemID uint, userID string) error {
+	participant, err := s.participantRepo.WithTx(tx).FindByItemAndUser(itemID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotParticipant
+		}
+		return err
+	}
+	if participant.Status != model.ParticipantStatusDepositPaid {
+		return ErrNotParticipant
+	}
+	return nil
+}
+
+// SettleDepositsTx returns the deposit of every numbered participant except
+// keepUserID, whose deposit stays held until the sale is settled. Pass an
+// empty keepUserID when the lot goes unsold.
+func (s *participationService) SettleDepositsTx(tx *gorm.DB, itemID uint, keepUserID string) error {
+	participantRepo := s.participantRepo.WithTx(tx)
+	participants, err := participantRepo.FindPaidByItemID(itemID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range participants {
+		p := &participants[i]
+		if p.UserID == keepUserID {
+			continue
+		}
+		if err := s.fundsService.ReleaseHoldTx(tx, p.UserID, itemID, model.HoldKindDeposit); err != nil {
+			return err
+		}
+		p.Status = model.ParticipantStatusRefunded
+		p.DepositRefundedAt = &now
+		if err := participantRepo.Update(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HeldDepositTx returns the user's deposit on the item while it is still held
+func (s *participationService) HeldDepositTx(tx *gorm.DB, itemID uint, userID string) (decimal.Decimal, error) {
+	participant, err := s.participantRepo.WithTx(tx).FindByItemAndUser(itemID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return decimal.Zero, nil
+		}
+		return decimal.Zero, err
+	}
+	if participant.Status != model.ParticipantStatusDepositPaid {
+		return decimal.Zero, nil
+	}
+	return participant.DepositAmount, nil
+}
+
+// ApplyDepositTx charges amount of the held deposit towards the purchase
+// price; any rest of the deposit is returned
+func (s *participationService) ApplyDepositTx(tx *gorm.DB, itemID uint, userID string, amount decimal.Decimal) error {
+	return s.closeDepositTx(tx, itemID, userID, model.ParticipantStatusApplied, func() error {
+		return s.fundsService.CaptureHoldTx(tx, userID, itemID, model.HoldKindDeposit, amount)
+	})
+}
+
+// ForfeitDepositTx keeps the whole held deposit of a defaulting winner
+func (s *participationService) ForfeitDepositTx(tx *gorm.DB, itemID uint, userID string) error {
+	return s.closeDepositTx(tx, itemID, userID, model.ParticipantStatusForfeited, func() error {
+		return s.fundsService.ForfeitHoldTx(tx, userID, itemID, model.HoldKindDeposit)
+	})
+}
+
+func (s *participationService) closeDepositTx(tx *gorm.DB, itemID uint, userID string, status model.ParticipantStatus, settle func() error) error {
+	participantRepo := s.participantRepo.WithTx(tx)
+	participant, err := participantRepo.FindByItemAndUserForUpdate(itemID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if participant.Status != model.ParticipantStatusDepositPaid {
+		return nil
+	}
+
+	if participant.DepositAmount.IsPositive() {
+		if err := settle(); err != nil {
+			return err
+		}
+	}
+	participant.Status = status
+	return participantRepo.Update(participant)
+}
+
+// assignNumberTx issues the next NUP of the lot. The caller must hold the item lock.
+func (s *participationService) assignNumberTx(tx *gorm.DB, item *model.AuctionItem, participant *model.AuctionParticipant, now time.Time) error {
+	count, err := s.participantRepo.WithTx(tx).CountNumbered(item.ID)
+	if err != nil {
+		return err
+	}
+	nup := fmt.Sprintf("%s-%04d", item.LotCode, count+1)
+	participant.NUP = &nup
+	participant.Status = model.ParticipantStatusDepositPaid
+	participant.DepositPaidAt = &now
+	return nil
+}
+
+// validateRegistrationWindow checks that the lot is open for registration.
+// Without a registration end, registration stays open until the auction ends.
+func validateRegistrationWindow(item *model.AuctionItem, now time.Time) error {
+	if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+		return errors.New("auction is not open for registration")
+	}
+	schedule := item.Schedule
+	if schedule == nil {
+		return errors.New("auction schedule not found")
+	}
+	if schedule.RegistrationStart != nil && !schedule.RegistrationStart.IsZero() && now.Before(*schedule.RegistrationStart) {
+		return errors.New("registration has not opened yet")
+	}
+	end := schedule.AuctionEnd
+	if schedule.RegistrationEnd != nil && !schedule.RegistrationEnd.IsZero() {
+		end = *schedule.RegistrationEnd
+	}
+	if now.After(end) {
+		return errors.New("registration has closed")
+	}
+	return nil
+}
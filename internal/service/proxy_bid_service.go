@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// maxProxyRounds bounds a single engine run. Every round exhausts one proxy,
+// so it is only reached with an unusually large number of competing maximums.
+const maxProxyRounds = 100
+
+// ========== PROXY BIDDING ==========
+
+func (s *auctionService) SetProxyBid(req ProxyBidRequest) (*model.ProxyBid, error) {
+	var proxy *model.ProxyBid
+	err := s.withBiddableItem(req.ItemID, func(tx *gorm.DB, item *model.AuctionItem) error {
+		if err := s.participation.RequireEligibleTx(tx, item.ID, req.UserID); err != nil {
+			return err
+		}
+
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+		if _, err := proxyRepo.FindActiveByItemAndUser(item.ID, req.UserID); err == nil {
+			return errors.New("maximum bid already set for this item, raise it instead")
+		}
+
+		maxAmount := decimal.NewFromFloat(req.MaxAmount)
+		if err := s.validateProxyMaximum(tx, item, req.UserID, maxAmount); err != nil {
+			return err
+		}
+
+		// The whole maximum is held so the engine can always bid up to it
+		if err := s.fundsService.EnsureHoldTx(tx, req.UserID, item.ID, model.HoldKindBid, maxAmount); err != nil {
+			return err
+		}
+
+		proxy = &model.ProxyBid{
+			ItemID:    item.ID,
+			UserID:    req.UserID,
+			MaxAmount: maxAmount,
+			Status:    model.ProxyBidStatusActive,
+			PlacedAt:  time.Now(),
+		}
+		if err := proxyRepo.Create(proxy); err != nil {
+			return err
+		}
+
+		return s.resolveProxyBids(tx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadProxy(proxy), nil
+}
+
+func (s *auctionService) RaiseProxyBid(req ProxyBidRequest) (*model.ProxyBid, error) {
+	var proxy *model.ProxyBid
+	err := s.withBiddableItem(req.ItemID, func(tx *gorm.DB, item *model.AuctionItem) error {
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+
+		var err error
+		proxy, err = proxyRepo.FindActiveByItemAndUser(item.ID, req.UserID)
+		if err != nil {
+			return errors.New("no active maximum bid for this item")
+		}
+
+		maxAmount := decimal.NewFromFloat(req.MaxAmount)
+		if !maxAmount.GreaterThan(proxy.MaxAmount) {
+			return fmt.Errorf("new maximum must be greater than %s", proxy.MaxAmount.String())
+		}
+		if err := s.validateProxyMaximum(tx, item, req.UserID, maxAmount); err != nil {
+			return err
+		}
+		if err := s.fundsService.EnsureHoldTx(tx, req.UserID, item.ID, model.HoldKindBid, maxAmount); err != nil {
+			return err
+		}
+
+		proxy.MaxAmount = maxAmount
+		proxy.PlacedAt = time.Now()
+		if err := proxyRepo.Update(proxy); err != nil {
+			return err
+		}
+
+		return s.resolveProxyBids(tx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reloadProxy(proxy), nil
+}
+
+// WithdrawProxyBid stops the engine from bidding further. Bids it already
+// placed stay on the item.
+func (s *auctionService) WithdrawProxyBid(itemID uint, userID string) error {
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+
+		proxyRepo := s.proxyBidRepo.WithTx(tx)
+		proxy, err := proxyRepo.FindActiveByItemAndUser(itemID, userID)
+		if err != nil {
+			return errors.New("no active maximum bid for this item")
+		}
+
+		if err := proxyRepo.UpdateStatus(proxy.ID, model.ProxyBidStatusWithdrawn); err != nil {
+			return err
+		}
+
+		// Only the current bid, if leading, stays held
+		return s.syncBidHoldsTx(tx, item)
+	})
+	if err != nil && isRetryableTxError(err) {
+		return ErrBidConflict
+	}
+	return err
+}
+
+func (s *auctionService) GetUserProxyBids(userID string) ([]model.ProxyBid, error) {
+	return s.proxyBidRepo.FindActiveByUser(userID)
+}
+
+func (s *auctionService) validateProxyMaximum(tx *gorm.DB, item *model.AuctionItem, userID string, maxAmount decimal.Decimal) error {
+	if item.AuctionMethod != "" && item.AuctionMethod != model.AuctionMethodOpenBidding {
+		return errors.New("maximum bids are only available for open bidding")
+	}
+
+	leading := false
+	if leader, err := s.bidRepo.WithTx(tx).FindWinningBid(item.ID); err == nil {
+		leading = leader.UserID == userID
+	}
+
+	if leading {
+		if !maxAmount.GreaterThan(item.CurrentHighestBid) {
+			return fmt.Errorf("maximum must be greater than your current bid of %s", item.CurrentHighestBid.String())
+		}
+	} else {
+		ladder, err := s.incrementsTx(tx, item)
+		if err != nil {
+			return err
+		}
+		if minBid := ladder.nextMinimumBid(item); maxAmount.LessThan(minBid) {
+			return fmt.Errorf("maximum must be at least %s", minBid.String())
+		}
+	}
+
+	return nil
+}
+
+func (s *auctionService) reloadProxy(proxy *model.ProxyBid) *model.ProxyBid {
+	proxies, err := s.proxyBidRepo.FindActiveByUser(proxy.UserID)
+	if err != nil {
+		return proxy
+	}
+	for i := range proxies {
+		if proxies[i].ID == proxy.ID {
+			return &proxies[i]
+		}
+	}
+	// Exhausted by the engine during this request
+	proxy.Status = model.ProxyBidStatusExhausted
+	return proxy
+}
+
+// resolveProxyBids lets active maximums answer the current price, eBay style:
+// the strongest maximum ends up leading at one increment above the runner-up's
+// maximum (capped at its own), and equal maximums go to the earliest placed.
+// Every automatic bid is stored as a BidTypeProxy bid. The caller must hold
+// the item row lock.
+func (s *auctionService) resolveProxyBids(tx *gorm.DB, item *model.AuctionItem) error {
+	proxyRepo := s.proxyBidRepo.WithTx(tx)
+	bidRepo := s.bidRepo.WithTx(tx)
+
+	ladder, err := s.incrementsTx(tx, item)
+	if err != nil {
+		return err
+	}
+
+	for round := 0; round < maxProxyRounds; round++ {
+		leaderID := ""
+		if leader, err := bidRepo.FindWinningBid(item.ID); err == nil {
+			leaderID = leader.UserID
+		}
+
+		proxies, err := proxyRepo.FindActiveByItem(item.ID)
+		if err != nil {
+			return err
+		}
+
+		leaderProxy, challenger := proxyContest(proxies, leaderID, ladder.nextMinimumBid(item))
+		if challenger == nil {
+			break
+		}
+
+		if leaderDefends(leaderProxy, challenger) {
+			// The challenger is pushed to its ceiling and immediately outbid
+			if _, err := s.placeBidTx(tx, item, challenger.UserID, challenger.MaxAmount, model.BidTypeProxy, "", ""); err != nil {
+				return err
+			}
+			if err := proxyRepo.UpdateStatus(challenger.ID, model.ProxyBidStatusExhausted); err != nil {
+				return err
+			}
+
+			amount := decimal.Min(leaderProxy.MaxAmount, ladder.nextMinimumBid(item))
+			if _, err := s.placeBidTx(tx, item, leaderProxy.UserID, amount, model.BidTypeProxy, "", ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The challenger takes the lead; a losing leader proxy bids its ceiling first
+		if leaderProxy != nil {
+			if leaderProxy.MaxAmount.GreaterThan(item.CurrentHighestBid) {
+				if _, err := s.placeBidTx(tx, item, leaderProxy.UserID, leaderProxy.MaxAmount, model.BidTypeProxy, "", ""); err != nil {
+					return err
+				}
+			}
+			if err := proxyRepo.UpdateStatus(leaderProxy.ID, model.ProxyBidStatusExhausted); err != nil {
+				return err
+			}
+		}
+
+		amount := decimal.Min(challenger.MaxAmount, ladder.nextMinimumBid(item))
+		if _, err := s.placeBidTx(tx, item, challenger.UserID, amount, model.BidTypeProxy, "", ""); err != nil {
+			return err
+		}
+	}
+
+	// Maximums that can no longer beat the price are spent
+	leaderID := ""
+	if leader, err := bidRepo.FindWinningBid(item.ID); err == nil {
+		leaderID = leader.UserID
+	}
+	proxies, err := proxyRepo.FindActiveByItem(item.ID)
+	if err != nil {
+		return err
+	}
+	minBid := ladder.nextMinimumBid(item)
+	for _, p := range proxies {
+		if p.UserID != leaderID && p.MaxAmount.LessThan(minBid) {
+			if err := proxyRepo.UpdateStatus(p.ID, model.ProxyBidStatusExhausted); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// proxyContest picks, from the active maximums in the repository's order
+// (highest first, earliest on ties), the leader's own maximum and the first
+// other one that can still reach minBid
+func proxyContest(proxies []model.ProxyBid, leaderID string, minBid decimal.Decimal) (leaderProxy, challenger *model.ProxyBid) {
+	for i := range proxies {
+		p := &proxies[i]
+		if p.UserID == leaderID {
+			leaderProxy = p
+			continue
+		}
+		if challenger == nil && p.MaxAmount.GreaterThanOrEqual(minBid) {
+			challenger = p
+		}
+	}
+	return leaderProxy, challenger
+}
+
+// leaderDefends reports whether the leader's maximum holds off the
+// challenger: it is higher, or equal and placed first
+func leaderDefends(leaderProxy, challenger *model.ProxyBid) bool {
+	return leaderProxy != nil &&
+		(leaderProxy.MaxAmount.GreaterThan(challenger.MaxAmount) ||
+			(leaderProxy.MaxAmount.Equal(challenger.MaxAmount) && !challenger.PlacedAt.Before(leaderProxy.PlacedAt)))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestProxyContest(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	// Repository order: highest maximum first, earliest on ties
+	proxies := []model.ProxyBid{
+		{ID: 1, UserID: "leader", MaxAmount: decimal.RequireFromString("5000"), PlacedAt: base},
+		{ID: 2, UserID: "b", MaxAmount: decimal.RequireFromString("4000"), PlacedAt: base.Add(time.Minute)},
+		{ID: 3, UserID: "c", MaxAmount: decimal.RequireFromString("4000"), PlacedAt: base.Add(2 * time.Minute)},
+		{ID: 4, UserID: "d", MaxAmount: decimal.RequireFromString("1000"), PlacedAt: base},
+	}
+
+	tests := []struct {
+		name           string
+		leaderID       string
+		minBid         string
+		wantLeader     uint
+		wantChallenger uint
+	}{
+		{"leader skipped, earliest of equal maximums challenges", "leader", "2000", 1, 2},
+		{"highest other maximum challenges when nobody leads", "", "2000", 0, 1},
+		{"maximums below the next bid are ignored", "leader", "4500", 1, 0},
+		{"lower maximum challenges once the price allows it", "b", "500", 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leader, challenger := proxyContest(proxies, tt.leaderID, decimal.RequireFromString(tt.minBid))
+			if got := proxyID(leader); got != tt.wantLeader {
+				t.Errorf("leader proxy = %d, want %d", got, tt.wantLeader)
+			}
+			if got := proxyID(challenger); got != tt.wantChallenger {
+				t.Errorf("challenger = %d, want %d", got, tt.wantChallenger)
+			}
+		})
+	}
+}
+
+func TestLeaderDefends(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	proxy := func(max string, placed time.Duration) *model.ProxyBid {
+		return &model.ProxyBid{MaxAmount: decimal.RequireFromString(max), PlacedAt: base.Add(placed)}
+	}
+
+	tests := []struct {
+		name       string
+		leader     *model.ProxyBid
+		challenger *model.ProxyBid
+		want       bool
+	}{
+		{"no leader maximum", nil, proxy("1000", 0), false},
+		{"higher maximum defends", proxy("2000", time.Minute), proxy("1500", 0), true},
+		{"lower maximum gives way", proxy("1500", 0), proxy("2000", time.Minute), false},
+		{"equal maximum placed first defends", proxy("2000", 0), proxy("2000", time.Minute), true},
+		{"equal maximum placed at the same time defends", proxy("2000", 0), proxy("2000", 0), true},
+		{"equal maximum placed later gives way", proxy("2000", time.Minute), proxy("2000", 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaderDefends(tt.leader, tt.challenger); got != tt.want {
+				t.Errorf("leaderDefends = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func proxyID(p *model.ProxyBid) uint {
+	if p == nil {
+		return 0
+	}
+	return p.ID
+}
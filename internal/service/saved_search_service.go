@@ -0,0 +1,355 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	maxSavedSearchesPerUser = 20
+	savedSearchDigestPeriod = 24 * time.Hour
+	savedSearchDigestBatch  = 100
+)
+
+// SavedSearchService keeps buyers' saved listing searches and alerts them when
+// a newly published lot matches, either at once or in a daily digest.
+type SavedSearchService interface {
+	CreateSavedSearch(req CreateSavedSearchRequest) (*model.SavedSearch, error)
+	UpdateSavedSearch(req UpdateSavedSearchRequest) (*model.SavedSearch, error)
+	DeleteSavedSearch(id uint, userID string) error
+	GetMySavedSearches(userID string) ([]model.SavedSearch, error)
+	Unsubscribe(token string) (*model.SavedSearch, error)
+
+	// Used by publishing and the scheduler
+	MatchPublishedItem(item *model.AuctionItem) (int, error)
+	SendDailyDigests(now time.Time) (int, error)
+}
+
+// CreateSavedSearchRequest takes the public listing's filters. At least one
+// filter is required so a search doesn't alert on every lot.
+type CreateSavedSearchRequest struct {
+	UserID     string                    `json:"-"`
+	Name       string                    `json:"name" binding:"required,max=100"`
+	Search     string                    `json:"search" binding:"max=255"`
+	CategoryID *uint                     `json:"category_id"`
+	Province   string                    `json:"province" binding:"max=100"`
+	MinPrice   *decimal.Decimal          `json:"min_price"`
+	MaxPrice   *decimal.Decimal          `json:"max_price"`
+	Delivery   model.SavedSearchDelivery `json:"delivery"` // defaults to instant
+}
+
+// UpdateSavedSearchRequest renames a search, changes its delivery or turns
+// its alerts back on after unsubscribing. Empty fields are left unchanged.
+type UpdateSavedSearchRequest struct {
+	ID       uint                      `json:"-"`
+	UserID   string                    `json:"-"`
+	Name     string                    `json:"name" binding:"max=100"`
+	Delivery model.SavedSearchDelivery `json:"delivery"`
+	Active   *bool                     `json:"active"`
+}
+
+type savedSearchService struct {
+	transactor      repository.Transactor
+	savedSearchRepo repository.SavedSearchRepository
+	notifications   NotificationService
+}
+
+func NewSavedSearchService(
+	transactor repository.Transactor,
+	savedSearchRepo repository.SavedSearchRepository,
+	notifications NotificationService,
+) SavedSearchService {
+	return &savedSearchService{
+		transactor:      transactor,
+		savedSearchRepo: savedSearchRepo,
+		notifications:   notifications,
+	}
+}
+
+// ========== SAVED SEARCHES ==========
+
+func (s *savedSearchService) CreateSavedSearch(req CreateSavedSearchRequest) (*model.SavedSearch, error) {
+	search := strings.TrimSpace(req.Search)
+	province := strings.TrimSpace(req.Province)
+	if search == "" && req.CategoryID == nil && province == "" && req.MinPrice == nil && req.MaxPrice == nil {
+		return nil, errors.New("a saved search needs at least one filter")
+	}
+	if (req.MinPrice != nil && req.MinPrice.IsNegative()) || (req.MaxPrice != nil && req.MaxPrice.IsNegative()) {
+		return nil, errors.New("prices cannot be negative")
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && req.MinPrice.GreaterThan(*req.MaxPrice) {
+		return nil, errors.New("min_price cannot exceed max_price")
+	}
+	delivery := req.Delivery
+	if delivery == "" {
+		delivery = model.SavedSearchDeliveryInstant
+	}
+	if !validSavedSearchDelivery(delivery) {
+		return nil, errors.New("delivery must be instant or daily_digest")
+	}
+
+	existing, err := s.savedSearchRepo.FindByUser(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxSavedSearchesPerUser {
+		return nil, errors.New("saved search limit reached; delete one first")
+	}
+
+	token, err := newUnsubscribeToken()
+	if err != nil {
+		return nil, err
+	}
+	saved := &model.SavedSearch{
+		UserID:           req.UserID,
+		Name:             strings.TrimSpace(req.Name),
+		Search:           search,
+		CategoryID:       req.CategoryID,
+		MinPrice:         req.MinPrice,
+		MaxPrice:         req.MaxPrice,
+		Delivery:         delivery,
+		Active:           true,
+		UnsubscribeToken: token,
+	}
+	if province != "" {
+		saved.Province = &province
+	}
+	if err := s.savedSearchRepo.Create(saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *savedSearchService) UpdateSavedSearch(req UpdateSavedSearchRequest) (*model.SavedSearch, error) {
+	saved, err := s.savedSearchRepo.FindByIDAndUser(req.ID, req.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("saved search not found")
+		}
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		saved.Name = name
+	}
+	if req.Delivery != "" {
+		if !validSavedSearchDelivery(req.Delivery) {
+			return nil, errors.New("delivery must be instant or daily_digest")
+		}
+		saved.Delivery = req.Delivery
+	}
+	if req.Active != nil {
+		saved.Active = *req.Active
+	}
+
+	if err := s.savedSearchRepo.Update(saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *savedSearchService) DeleteSavedSearch(id uint, userID string) error {
+	return s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		deleted, err := s.savedSearchRepo.WithTx(tx).Delete(id, userID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errors.New("saved search not found")
+		}
+		return nil
+	})
+}
+
+func (s *savedSearchService) GetMySavedSearches(userID string) ([]model.SavedSearch, error) {
+	return s.savedSearchRepo.FindByUser(userID)
+}
+
+// Unsubscribe turns off the alerts of the search behind an email's
+// unsubscribe link. The search is kept so the user can turn it back on.
+func (s *savedSearchService) Unsubscribe(token string) (*model.SavedSearch, error) {
+	saved, err := s.savedSearchRepo.FindByUnsubscribeToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid unsubscribe link")
+		}
+		return nil, err
+	}
+	if !saved.Active {
+		return saved, nil
+	}
+
+	saved.Active = false
+	if err := s.savedSearchRepo.Update(saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ========== ALERTS ==========
+
+// MatchPublishedItem records the lot against every active saved search it
+// matches and alerts instant searches straight away. Digest searches pick the
+// match up with their next digest. It returns the number of matches.
+func (s *savedSearchService) MatchPublishedItem(item *model.AuctionItem) (int, error) {
+	searches, err := s.savedSearchRepo.FindActiveByCategory(item.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for i := range searches {
+		search := &searches[i]
+		if !matchesSavedSearch(search, item) {
+			continue
+		}
+
+		recorded := false
+		err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			match := &model.SavedSearchMatch{SavedSearchID: search.ID, ItemID: item.ID}
+			instant := search.Delivery == model.SavedSearchDeliveryInstant
+			if instant {
+				// Alerted now, so never part of a later digest
+				now := time.Now()
+				match.DigestedAt = &now
+			}
+			created, err := s.savedSearchRepo.WithTx(tx).CreateMatchOnce(match)
+			if err != nil || !created {
+				return err
+			}
+			if instant {
+				if _, err := s.notifications.NotifySavedSearchMatchTx(tx, search, item); err != nil {
+					return err
+				}
+			}
+			recorded = true
+			return nil
+		})
+		if err != nil {
+			log.Printf("Saved searches: failed to match item %d against search %d: %v", item.ID, search.ID, err)
+			continue
+		}
+		if recorded {
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+// SendDailyDigests sends each digest search that has new matches and was
+// last sent a day or more ago one alert listing them. Lots withdrawn since
+// they matched are left out. Searches are claimed under a skip-locked row
+// lock, so replicas never send the same digest.
+func (s *savedSearchService) SendDailyDigests(now time.Time) (int, error) {
+	due, err := s.savedSearchRepo.FindDigestsDue(now.Add(-savedSearchDigestPeriod), savedSearchDigestBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, candidate := range due {
+		id := candidate.ID
+		err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			savedSearchRepo := s.savedSearchRepo.WithTx(tx)
+			search, err := savedSearchRepo.FindByIDForUpdateSkipLocked(id)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if !search.Active || search.Delivery != model.SavedSearchDeliveryDailyDigest ||
+				(search.LastDigestAt != nil && search.LastDigestAt.After(now.Add(-savedSearchDigestPeriod))) {
+				return nil
+			}
+
+			matches, err := savedSearchRepo.FindUndigestedMatches(search.ID)
+			if err != nil {
+				return err
+			}
+			ids := make([]uint, 0, len(matches))
+			live := make([]model.SavedSearchMatch, 0, len(matches))
+			for _, match := range matches {
+				ids = append(ids, match.ID)
+				if match.Item != nil && (match.Item.Status == model.AuctionStatusPublished || match.Item.Status == model.AuctionStatusOngoing) {
+					live = append(live, match)
+				}
+			}
+
+			created, err := s.notifications.NotifySavedSearchDigestTx(tx, search, live)
+			if err != nil {
+				return err
+			}
+			if err := savedSearchRepo.MarkMatchesDigested(ids, now); err != nil {
+				return err
+			}
+			if !created {
+				return nil
+			}
+			search.LastDigestAt = &now
+			if err := savedSearchRepo.Update(search); err != nil {
+				return err
+			}
+			sent++
+			return nil
+		})
+		if err != nil {
+			log.Printf("Saved searches: failed to send digest of search %d: %v", id, err)
+		}
+	}
+	return sent, nil
+}
+
+// matchesSavedSearch applies a saved search's filters to one lot the way the
+// public listing applies them
+func matchesSavedSearch(search *model.SavedSearch, item *model.AuctionItem) bool {
+	if search.CategoryID != nil && *search.CategoryID != item.CategoryID {
+		return false
+	}
+	if search.Search != "" {
+		term := strings.ToLower(search.Search)
+		description := ""
+		if item.Description != nil {
+			description = *item.Description
+		}
+		if !strings.Contains(strings.ToLower(item.ItemName), term) &&
+			!strings.Contains(strings.ToLower(item.LotCode), term) &&
+			!strings.Contains(strings.ToLower(description), term) {
+			return false
+		}
+	}
+	if search.Province != nil {
+		if item.Organizer == nil || item.Organizer.Province == nil || !strings.EqualFold(*item.Organizer.Province, *search.Province) {
+			return false
+		}
+	}
+	if search.MinPrice != nil && item.StartingPrice.LessThan(*search.MinPrice) {
+		return false
+	}
+	if search.MaxPrice != nil && item.StartingPrice.GreaterThan(*search.MaxPrice) {
+		return false
+	}
+	return true
+}
+
+func validSavedSearchDelivery(delivery model.SavedSearchDelivery) bool {
+	return delivery == model.SavedSearchDeliveryInstant || delivery == model.SavedSearchDeliveryDailyDigest
+}
+
+func newUnsubscribeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
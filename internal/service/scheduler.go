@@ -0,0 +1,81 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ScheduledJob is a unit of periodic background work. Run receives the tick
+// time so a job evaluates every item against the same clock.
+type ScheduledJob struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Scheduler runs registered jobs on their own interval. Jobs must be safe to
+// run concurrently on several replicas; the scheduler itself does no leader
+// election.
+type Scheduler struct {
+	jobs []ScheduledJob
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+// Register adds a job; it must be called before Start
+func (s *Scheduler) Register(name string, interval time.Duration, run func(now time.Time) error) {
+	s.jobs = append(s.jobs, ScheduledJob{Name: name, Interval: interval, Run: run})
+}
+
+// Start runs every job once immediately, to catch up on work missed while
+// the service was down, and then on each tick of its interval
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+	log.Printf("Scheduler started with %d job(s)", len(s.jobs))
+}
+
+func (s *Scheduler) loop(job ScheduledJob) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.runJob(job)
+	for {
+		select {
+		case <-ticker.C:
+			s.runJob(job)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) runJob(job ScheduledJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Scheduler job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(time.Now()); err != nil {
+		log.Printf("Scheduler job %s failed: %v", job.Name, err)
+	}
+}
+
+// Stop signals every job to finish and waits for running ones to return
+func (s *Scheduler) Stop() {
+	log.Println("Stopping scheduler...")
+	close(s.stop)
+	s.wg.Wait()
+}
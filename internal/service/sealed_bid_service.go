@@ -0,0 +1,290 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrBidsSealed is returned when bid amounts of a closed-bidding lot are
+// requested before they have been opened
+var ErrBidsSealed = errors.New("bids are sealed until the auction closes")
+
+// SealedBidResult is the public outcome of a closed-bidding lot
+type SealedBidResult struct {
+	Reveal   *model.SealedBidReveal `json:"reveal"`
+	Ranking  []model.SealedBidRank  `json:"ranking"`
+	Verified bool                   `json:"verified"` // stored digest matches the ranking, and the ranking the bids as stored now
+}
+
+// ========== SEALED BIDDING ==========
+
+// isSealed reports whether bid amounts on the item stay hidden until close
+func isSealed(item *model.AuctionItem) bool {
+	return item.AuctionMethod == model.AuctionMethodClosedBidding
+}
+
+// placeSealedBidTx submits the bidder's single sealed bid, or revises it if
+// one exists. CurrentHighestBid is left untouched; only the number of
+// participants becomes visible. The caller must hold the item row lock.
+func (s *auctionService) placeSealedBidTx(tx *gorm.DB, item *model.AuctionItem, req PlaceBidRequest, amount decimal.Decimal) (*model.Bid, error) {
+	if amount.LessThan(item.StartingPrice) {
+		return nil, fmt.Errorf("bid must be at least the starting price: %s", item.StartingPrice.String())
+	}
+
+	// A revision may lower the hold as well as raise it
+	if err := s.fundsService.SetHoldTx(tx, req.UserID, item.ID, model.HoldKindBid, amount); err != nil {
+		return nil, err
+	}
+
+	bidRepo := s.bidRepo.WithTx(tx)
+	bids, err := bidRepo.FindByItemAndUser(item.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, existing := range bids {
+		if existing.BidStatus == model.BidStatusCancelled {
+			continue
+		}
+
+		// A revision counts from the time it was made for tie-breaks
+		existing.BidAmount = amount
+		existing.BidTime = now
+		existing.IPAddress = stringPtr(req.IPAddress)
+		existing.UserAgent = stringPtr(req.UserAgent)
+		if err := bidRepo.Update(&existing); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+
+	bid := &model.Bid{
+		ItemID:    item.ID,
+		UserID:    req.UserID,
+		BidAmount: amount,
+		BidType:   model.BidTypeManual,
+		BidStatus: model.BidStatusActive,
+		BidTime:   now,
+		IPAddress: stringPtr(req.IPAddress),
+		UserAgent: stringPtr(req.UserAgent),
+	}
+	if err := bidRepo.Create(bid); err != nil {
+		return nil, err
+	}
+
+	currentFloat, _ := item.CurrentHighestBid.Float64()
+	if err := s.itemRepo.WithTx(tx).UpdateBidInfo(item.ID, currentFloat, item.BidCount+1); err != nil {
+		return nil, err
+	}
+	item.BidCount++
+	s.publishBidTx(tx, item, bid)
+
+	return bid, nil
+}
+
+// RevealSealedBids opens the sealed bids of a closed-bidding lot whose
+// deadline has passed and closes it
+func (s *auctionService) RevealSealedBids(itemID uint) (*SealedBidResult, error) {
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+
+		if !isSealed(item) {
+			return errors.New("auction item is not a closed-bidding lot")
+		}
+		if _, err := s.sealedBidRepo.WithTx(tx).FindRevealByItemID(itemID); err == nil {
+			return errors.New("sealed bids have already been opened")
+		}
+		if item.Schedule == nil || time.Now().Before(item.Schedule.AuctionEnd) {
+			return errors.New("sealed bids can only be opened after the auction ends")
+		}
+
+		_, err = s.revealSealedBidsTx(tx, item, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSealedBidResult(itemID)
+}
+
+// revealSealedBidsTx ranks the sealed bids (highest amount, then earliest
+// BidTime), marks the winner and stores the reveal record. The top bid only
+// wins if it reaches the limit price; otherwise the lot closes unsold.
+func (s *auctionService) revealSealedBidsTx(tx *gorm.DB, item *model.AuctionItem, now time.Time) (*model.SealedBidReveal, error) {
+	bidRepo := s.bidRepo.WithTx(tx)
+	itemRepo := s.itemRepo.WithTx(tx)
+
+	bids, err := bidRepo.FindValidByItemID(item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := rankSealedBids(bids)
+	rankingJSON, err := json.Marshal(ranking)
+	if err != nil {
+		return nil, err
+	}
+
+	reveal := &model.SealedBidReveal{
+		ItemID:     item.ID,
+		BidCount:   len(ranking),
+		Ranking:    string(rankingJSON),
+		Digest:     sealedBidDigest(item.ID, ranking),
+		RevealedAt: now,
+	}
+
+	outcome, reason := model.AuctionOutcomeUnsold, "no bids were placed"
+	var winner *model.Bid
+	for _, rank := range ranking {
+		bid := bidByID(bids, rank.BidID)
+		if rank.Rank == 1 {
+			if reserveMet(item, bid.BidAmount) {
+				bid.BidStatus = model.BidStatusWon
+				bid.IsHighest = true
+				winningID := bid.ID
+				reveal.WinningBidID = &winningID
+				outcome, reason = model.AuctionOutcomeSold, ""
+				winner = bid
+			} else {
+				bid.BidStatus = model.BidStatusLost
+				bid.IsHighest = false
+				reason = unmetReserveReason(bid.BidAmount)
+			}
+
+			amountFloat, _ := bid.BidAmount.Float64()
+			if err := itemRepo.UpdateBidInfo(item.ID, amountFloat, len(ranking)); err != nil {
+				return nil, err
+			}
+			item.CurrentHighestBid = bid.BidAmount
+		} else {
+			bid.BidStatus = model.BidStatusLost
+			bid.IsHighest = false
+		}
+		if err := bidRepo.Update(bid); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.settleBidHoldsTx(tx, item, winner); err != nil {
+		return nil, err
+	}
+	if err := s.closeItemTx(tx, item, outcome, reason, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.sealedBidRepo.WithTx(tx).CreateReveal(reveal); err != nil {
+		return nil, err
+	}
+	return reveal, nil
+}
+
+// GetSealedBidResult returns the opened ranking and re-verifies it: the digest
+// must match the stored ranking, and every ranked bid must still read as it
+// did when the bids were opened. A bid cancelled after the reveal keeps its
+// place in the ranking, so a legitimate cancellation doesn't read as tampering.
+func (s *auctionService) GetSealedBidResult(itemID uint) (*SealedBidResult, error) {
+	reveal, err := s.sealedBidRepo.FindRevealByItemID(itemID)
+	if err != nil {
+		return nil, ErrBidsSealed
+	}
+
+	var ranking []model.SealedBidRank
+	if err := json.Unmarshal([]byte(reveal.Ranking), &ranking); err != nil {
+		return nil, err
+	}
+
+	// Cancelled bids included, so a cancellation after the reveal still
+	// finds its ranked bid
+	bids, err := s.bidRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedBidResult{
+		Reveal:   reveal,
+		Ranking:  ranking,
+		Verified: sealedBidDigest(itemID, ranking) == reveal.Digest && rankingMatchesBids(ranking, bids),
+	}, nil
+}
+
+// rankingMatchesBids reports whether every ranked bid is stored unchanged and
+// every bid left out of the ranking was cancelled before it was opened
+func rankingMatchesBids(ranking []model.SealedBidRank, bids []model.Bid) bool {
+	ranked := make(map[uint]bool, len(ranking))
+	for _, r := range ranking {
+		bid := bidByID(bids, r.BidID)
+		if bid == nil || bid.UserID != r.UserID || bid.BidAmount.StringFixed(2) != r.BidAmount ||
+			!bid.BidTime.UTC().Equal(r.BidTime) {
+			return false
+		}
+		ranked[bid.ID] = true
+	}
+	for _, bid := range bids {
+		if !ranked[bid.ID] && bid.BidStatus != model.BidStatusCancelled {
+			return false
+		}
+	}
+	return true
+}
+
+func rankSealedBids(bids []model.Bid) []model.SealedBidRank {
+	sorted := make([]model.Bid, len(bids))
+	copy(sorted, bids)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].BidAmount.Equal(sorted[j].BidAmount) {
+			return sorted[i].BidAmount.GreaterThan(sorted[j].BidAmount)
+		}
+		if !sorted[i].BidTime.Equal(sorted[j].BidTime) {
+			return sorted[i].BidTime.Before(sorted[j].BidTime)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	ranking := make([]model.SealedBidRank, 0, len(sorted))
+	for i, bid := range sorted {
+		ranking = append(ranking, model.SealedBidRank{
+			Rank:      i + 1,
+			BidID:     bid.ID,
+			UserID:    bid.UserID,
+			BidAmount: bid.BidAmount.StringFixed(2),
+			BidTime:   bid.BidTime.UTC(),
+		})
+	}
+	return ranking
+}
+
+// sealedBidDigest hashes the ranking in a canonical line format
+func sealedBidDigest(itemID uint, ranking []model.SealedBidRank) string {
+	var b strings.Builder
+	for _, r := range ranking {
+		fmt.Fprintf(&b, "%d|%d|%d|%s|%s|%s\n",
+			itemID, r.Rank, r.BidID, r.UserID, r.BidAmount, r.BidTime.UTC().Format(time.RFC3339Nano))
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func bidByID(bids []model.Bid, id uint) *model.Bid {
+	for i := range bids {
+		if bids[i].ID == id {
+			return &bids[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"yourapp/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func sealedTestBids() []model.Bid {
+	base := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	return []model.Bid{
+		{ID: 10, UserID: "a", BidAmount: decimal.RequireFromString("1500"), BidTime: base.Add(3 * time.Minute)},
+		{ID: 11, UserID: "b", BidAmount: decimal.RequireFromString("2000"), BidTime: base.Add(2 * time.Minute)},
+		{ID: 12, UserID: "c", BidAmount: decimal.RequireFromString("2000"), BidTime: base.Add(time.Minute)},
+		{ID: 14, UserID: "d", BidAmount: decimal.RequireFromString("1500"), BidTime: base.Add(3 * time.Minute)},
+		{ID: 13, UserID: "e", BidAmount: decimal.RequireFromString("1500"), BidTime: base.Add(3 * time.Minute)},
+	}
+}
+
+func TestRankSealedBids(t *testing.T) {
+	ranking := rankSealedBids(sealedTestBids())
+
+	// Highest amount first, then earliest bid, then lowest id
+	want := []uint{12, 11, 10, 13, 14}
+	if len(ranking) != len(want) {
+		t.Fatalf("got %d ranks, want %d", len(ranking), len(want))
+	}
+	for i, r := range ranking {
+		if r.BidID != want[i] || r.Rank != i+1 {
+			t.Errorf("rank %d = bid %d (rank %d), want bid %d", i+1, r.BidID, r.Rank, want[i])
+		}
+	}
+	if ranking[0].BidAmount != "2000.00" {
+		t.Errorf("bid amount = %q, want fixed two decimals", ranking[0].BidAmount)
+	}
+}
+
+func TestSealedBidDigest(t *testing.T) {
+	ranking := rankSealedBids(sealedTestBids())
+	digest := sealedBidDigest(7, ranking)
+
+	if again := sealedBidDigest(7, rankSealedBids(sealedTestBids())); again != digest {
+		t.Error("digest of the same bids differs")
+	}
+	if other := sealedBidDigest(8, ranking); other == digest {
+		t.Error("digest does not depend on the item")
+	}
+
+	changed := rankSealedBids(sealedTestBids())
+	changed[2].BidAmount = "1500.01"
+	if sealedBidDigest(7, changed) == digest {
+		t.Error("digest does not depend on the amounts")
+	}
+}
+
+func TestRankingMatchesBids(t *testing.T) {
+	ranking := rankSealedBids(sealedTestBids())
+
+	t.Run("unchanged bids", func(t *testing.T) {
+		if !rankingMatchesBids(ranking, sealedTestBids()) {
+			t.Error("unchanged bids do not match")
+		}
+	})
+
+	t.Run("winner cancelled after the reveal", func(t *testing.T) {
+		bids := sealedTestBids()
+		bids[2].BidStatus = model.BidStatusCancelled
+		if !rankingMatchesBids(ranking, bids) {
+			t.Error("a cancellation reads as tampering")
+		}
+	})
+
+	t.Run("bid cancelled before the reveal", func(t *testing.T) {
+		bids := append(sealedTestBids(), model.Bid{
+			ID: 20, UserID: "f", BidAmount: decimal.RequireFromString("9000"), BidStatus: model.BidStatusCancelled,
+		})
+		if !rankingMatchesBids(ranking, bids) {
+			t.Error("an unranked cancelled bid reads as tampering")
+		}
+	})
+
+	t.Run("amount changed", func(t *testing.T) {
+		bids := sealedTestBids()
+		bids[0].BidAmount = decimal.RequireFromString("1600")
+		if rankingMatchesBids(ranking, bids) {
+			t.Error("a changed amount still matches")
+		}
+	})
+
+	t.Run("ranked bid removed", func(t *testing.T) {
+		if rankingMatchesBids(ranking, sealedTestBids()[1:]) {
+			t.Error("a missing bid still matches")
+		}
+	})
+
+	t.Run("bid added", func(t *testing.T) {
+		bids := append(sealedTestBids(), model.Bid{
+			ID: 21, UserID: "g", BidAmount: decimal.RequireFromString("9000"), BidStatus: model.BidStatusLost,
+		})
+		if rankingMatchesBids(ranking, bids) {
+			t.Error("an unranked valid bid still matches")
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// SecondChanceService offers a lot whose winner defaulted or had the winning
+// bid cancelled to the next-highest valid bidder at their own bid amount.
+// Accepting records a distinct outcome on the item and opens a settlement.
+type SecondChanceService interface {
+	CreateOffer(req CreateSecondChanceRequest) (*model.SecondChanceOffer, error)
+	AcceptOffer(offerID uint, userID string) (*model.SecondChanceOffer, error)
+	DeclineOffer(offerID uint, userID string) (*model.SecondChanceOffer, error)
+	ExpireOverdueOffers(now time.Time) (int64, error)
+	GetItemOffers(itemID uint) ([]model.SecondChanceOffer, error)
+	GetMyOffers(userID string) ([]model.SecondChanceOffer, error)
+}
+
+type CreateSecondChanceRequest struct {
+	ItemID        uint   `json:"-"`
+	AdminID       string `json:"-"`
+	ResponseHours int    `json:"response_hours"` // defaults to the configured window
+}
+
+type secondChanceService struct {
+	transactor       repository.Transactor
+	secondChanceRepo repository.SecondChanceRepository
+	itemRepo         repository.AuctionItemRepository
+	bidRepo          repository.BidRepository
+	settlementRepo   repository.SettlementRepository
+	settlement       SettlementService
+	responseWindow   time.Duration
+}
+
+func NewSecondChanceService(
+	transactor repository.Transactor,
+	secondChanceRepo repository.SecondChanceRepository,
+	itemRepo repository.AuctionItemRepository,
+	bidRepo repository.BidRepository,
+	settlementRepo repository.SettlementRepository,
+	settlement SettlementService,
+	responseWindow time.Duration,
+) SecondChanceService {
+	return &secondChanceService{
+		transactor:       transactor,
+		secondChanceRepo: secondChanceRepo,
+		itemRepo:         itemRepo,
+		bidRepo:          bidRepo,
+		settlementRepo:   settlementRepo,
+		settlement:       settlement,
+		responseWindow:   responseWindow,
+	}
+}
+
+// CreateOffer picks the runner-up and offers them the lot. Previous winners
+// and bidders who already had an offer on the lot are skipped, so repeated
+// calls walk down the ranking. Only bids that meet the limit price qualify.
+func (s *secondChanceService) CreateOffer(req CreateSecondChanceRequest) (*model.SecondChanceOffer, error) {
+	window := s.responseWindow
+	if req.ResponseHours > 0 {
+		window = time.Duration(req.ResponseHours) * time.Hour
+	}
+
+	var offer *model.SecondChanceOffer
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(req.ItemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		if item.Status != model.AuctionStatusClosed || !item.ReauctionRequired {
+			return errors.New("second-chance offers are only possible after the winner defaulted or was cancelled")
+		}
+
+		secondChanceRepo := s.secondChanceRepo.WithTx(tx)
+		previous, err := secondChanceRepo.FindByItemID(item.ID)
+		if err != nil {
+			return err
+		}
+		excluded := make(map[string]bool)
+		for _, o := range previous {
+			if o.Status == model.SecondChanceStatusPending {
+				return errors.New("a second-chance offer is already pending for this lot")
+			}
+			excluded[o.UserID] = true
+		}
+
+		settlements, err := s.settlementRepo.WithTx(tx).FindAllByItemID(item.ID)
+		if err != nil {
+			return err
+		}
+		for _, st := range settlements {
+			excluded[st.WinnerID] = true
+		}
+
+		runnerUp, err := s.findRunnerUpTx(tx, item, excluded)
+		if err != nil {
+			return err
+		}
+
+		offer = &model.SecondChanceOffer{
+			ItemID:    item.ID,
+			BidID:     runnerUp.ID,
+			UserID:    runnerUp.UserID,
+			Amount:    runnerUp.BidAmount,
+			Status:    model.SecondChanceStatusPending,
+			ExpiresAt: time.Now().Add(window),
+			OfferedBy: req.AdminID,
+		}
+		return secondChanceRepo.Create(offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// AcceptOffer sells the lot to the runner-up and opens their settlement
+func (s *secondChanceService) AcceptOffer(offerID uint, userID string) (*model.SecondChanceOffer, error) {
+	var offer *model.SecondChanceOffer
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var err error
+		offer, err = s.lockPendingOfferTx(tx, offerID, userID, now)
+		if err != nil {
+			return err
+		}
+
+		itemRepo := s.itemRepo.WithTx(tx)
+		item, err := itemRepo.FindByIDForUpdate(offer.ItemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		if !item.ReauctionRequired {
+			return errors.New("lot is no longer available")
+		}
+
+		bidRepo := s.bidRepo.WithTx(tx)
+		bid, err := bidRepo.FindByID(offer.BidID)
+		if err != nil || bid.BidStatus == model.BidStatusCancelled {
+			return errors.New("offered bid is no longer valid")
+		}
+		if err := bidRepo.UpdateStatus(bid.ID, model.BidStatusWon); err != nil {
+			return err
+		}
+		if err := itemRepo.RecordSecondChanceSale(item.ID); err != nil {
+			return err
+		}
+		if _, err := s.settlement.OpenSettlementTx(tx, item, bid, now); err != nil {
+			return err
+		}
+
+		offer.Status = model.SecondChanceStatusAccepted
+		offer.RespondedAt = &now
+		return s.secondChanceRepo.WithTx(tx).Update(offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+func (s *secondChanceService) DeclineOffer(offerID uint, userID string) (*model.SecondChanceOffer, error) {
+	var offer *model.SecondChanceOffer
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var err error
+		offer, err = s.lockPendingOfferTx(tx, offerID, userID, now)
+		if err != nil {
+			return err
+		}
+
+		offer.Status = model.SecondChanceStatusDeclined
+		offer.RespondedAt = &now
+		return s.secondChanceRepo.WithTx(tx).Update(offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// ExpireOverdueOffers expires pending offers the runner-up did not answer in time
+func (s *secondChanceService) ExpireOverdueOffers(now time.Time) (int64, error) {
+	return s.secondChanceRepo.ExpirePending(now)
+}
+
+func (s *secondChanceService) GetItemOffers(itemID uint) ([]model.SecondChanceOffer, error) {
+	return s.secondChanceRepo.FindByItemID(itemID)
+}
+
+func (s *secondChanceService) GetMyOffers(userID string) ([]model.SecondChanceOffer, error) {
+	return s.secondChanceRepo.FindByUserID(userID)
+}
+
+func (s *secondChanceService) lockPendingOfferTx(tx *gorm.DB, offerID uint, userID string, now time.Time) (*model.SecondChanceOffer, error) {
+	offer, err := s.secondChanceRepo.WithTx(tx).FindByIDForUpdate(offerID)
+	if err != nil || offer.UserID != userID {
+		return nil, errors.New("second-chance offer not found")
+	}
+	if offer.Status != model.SecondChanceStatusPending {
+		return nil, errors.New("second-chance offer is no longer open")
+	}
+	if now.After(offer.ExpiresAt) {
+		return nil, errors.New("second-chance offer has expired")
+	}
+	return offer, nil
+}
+
+// findRunnerUpTx returns each remaining bidder's best bid ranked by amount,
+// then earliest bid, and picks the first that meets the limit price
+func (s *secondChanceService) findRunnerUpTx(tx *gorm.DB, item *model.AuctionItem, excluded map[string]bool) (*model.Bid, error) {
+	bids, err := s.bidRepo.WithTx(tx).FindValidByItemID(item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(bids, func(i, j int) bool {
+		if !bids[i].BidAmount.Equal(bids[j].BidAmount) {
+			return bids[i].BidAmount.GreaterThan(bids[j].BidAmount)
+		}
+		if !bids[i].BidTime.Equal(bids[j].BidTime) {
+			return bids[i].BidTime.Before(bids[j].BidTime)
+		}
+		return bids[i].ID < bids[j].ID
+	})
+
+	for i := range bids {
+		if excluded[bids[i].UserID] {
+			continue
+		}
+		if !reserveMet(item, bids[i].BidAmount) {
+			break
+		}
+		return &bids[i], nil
+	}
+	return nil, errors.New("no remaining bid meets the limit price")
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// settlementBatchSize bounds how many overdue settlements one job run handles
+const settlementBatchSize = 100
+
+// SettlementService settles sold lots. At close the buyer's fees and taxes
+// are added to the winning bid, the winner's deposit is credited against the
+// total and the rest becomes due by a deadline;
+// the winner's bid hold is kept at that outstanding amount so the money
+// stays reserved. Paying captures both and splits the fees and taxes out of
+// the proceeds, leaving the seller's net. Missing the deadline forfeits the
+// deposit and flags the lot for re-auction.
+type SettlementService interface {
+	OpenSettlementTx(tx *gorm.DB, item *model.AuctionItem, winner *model.Bid, now time.Time) (*model.Settlement, error)
+	PaySettlement(itemID uint, userID string) (*model.Settlement, error)
+	ForfeitOverdueSettlements(now time.Time) (int, error)
+	CancelSettlementTx(tx *gorm.DB, itemID uint, reason string, now time.Time) error
+
+	GetItemSettlement(itemID uint) (*model.Settlement, error)
+	GetMySettlement(itemID uint, userID string) (*model.Settlement, error)
+	GetMySettlements(userID string) ([]model.Settlement, error)
+}
+
+type settlementService struct {
+	transactor     repository.Transactor
+	settlementRepo repository.SettlementRepository
+	itemRepo       repository.AuctionItemRepository
+	fundsService   FundsService
+	ledgerService  LedgerService
+	feeService     FeeService
+	participation  ParticipationService
+	documents      DocumentService
+	paymentWindow  time.Duration
+}
+
+func NewSettlementService(
+	transactor repository.Transactor,
+	settlementRepo repository.SettlementRepository,
+	itemRepo repository.AuctionItemRepository,
+	fundsService FundsService,
+	ledgerService LedgerService,
+	feeService FeeService,
+	participation ParticipationService,
+	documents DocumentService,
+	paymentWindow time.Duration,
+) SettlementService {
+	return &settlementService{
+		transactor:     transactor,
+		settlementRepo: settlementRepo,
+		itemRepo:       itemRepo,
+		fundsService:   fundsService,
+		ledgerService:  ledgerService,
+		feeService:     feeService,
+		participation:  participation,
+		documents:      documents,
+		paymentWindow:  paymentWindow,
+	}
+}
+
+// OpenSettlementTx records what the winner owes. It runs inside the close
+// transaction, before the other bidders' holds are released.
+func (s *settlementService) OpenSettlementTx(tx *gorm.DB, item *model.AuctionItem, winner *model.Bid, now time.Time) (*model.Settlement, error) {
+	deposit, err := s.participation.HeldDepositTx(tx, item.ID, winner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.feeService.QuoteTx(tx, item, winner.BidAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	credit := decimal.Min(deposit, quote.BuyerTotal)
+	settlement := &model.Settlement{
+		ItemID:            item.ID,
+		WinnerID:          winner.UserID,
+		WinningBidID:      winner.ID,
+		BidAmount:         winner.BidAmount,
+		BuyerCharges:      quote.BuyerChargesTotal,
+		TotalAmount:       quote.BuyerTotal,
+		SellerCharges:     quote.SellerChargesTotal,
+		SellerNet:         quote.SellerNet,
+		DepositCredit:     credit,
+		OutstandingAmount: quote.BuyerTotal.Sub(credit),
+		PaymentDeadline:   now.Add(s.paymentWindow),
+		Status:            model.SettlementStatusPending,
+		Charges:           settlementCharges(quote),
+	}
+	if err := s.settlementRepo.WithTx(tx).Create(settlement); err != nil {
+		return nil, err
+	}
+	if _, err := s.documents.IssueInvoiceTx(tx, settlement); err != nil {
+		return nil, err
+	}
+
+	// Only the outstanding part needs to stay reserved; the bid hold is never
+	// raised here, paying tops it up from the available balance
+	if err := s.fundsService.CapHoldTx(tx, winner.UserID, item.ID, model.HoldKindBid, settlement.OutstandingAmount); err != nil {
+		return nil, err
+	}
+
+	// A deposit that covers the whole price settles the lot straight away
+	if !settlement.OutstandingAmount.IsPositive() {
+		if err := s.completeTx(tx, settlement, now); err != nil {
+			return nil, err
+		}
+	}
+	return settlement, nil
+}
+
+// PaySettlement charges the outstanding amount from the winner's wallet and
+// applies the deposit. Funds beyond the bid hold come from the available
+// balance.
+func (s *settlementService) PaySettlement(itemID uint, userID string) (*model.Settlement, error) {
+	var settlement *model.Settlement
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		var err error
+		settlement, err = s.settlementRepo.WithTx(tx).FindByItemIDForUpdate(itemID)
+		if err != nil || settlement.WinnerID != userID {
+			return errors.New("settlement not found")
+		}
+		if settlement.Status != model.SettlementStatusPending {
+			return errors.New("settlement is not awaiting payment")
+		}
+
+		now := time.Now()
+		if now.After(settlement.PaymentDeadline) {
+			return errors.New("payment deadline has passed")
+		}
+
+		// Raising the hold first checks the available balance
+		if err := s.fundsService.EnsureHoldTx(tx, userID, itemID, model.HoldKindBid, settlement.OutstandingAmount); err != nil {
+			return err
+		}
+		return s.completeTx(tx, settlement, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// ForfeitOverdueSettlements defaults every winner past their deadline. Each
+// settlement is handled in its own transaction and skipped if another
+// replica holds it.
+func (s *settlementService) ForfeitOverdueSettlements(now time.Time) (int, error) {
+	itemIDs, err := s.settlementRepo.FindOverdueItemIDs(now, settlementBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	defaulted := 0
+	for _, itemID := range itemIDs {
+		changed := false
+		err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+			settlementRepo := s.settlementRepo.WithTx(tx)
+			settlement, err := settlementRepo.FindByItemIDForUpdateSkipLocked(itemID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if settlement.Status != model.SettlementStatusPending || !now.After(settlement.PaymentDeadline) {
+				return nil
+			}
+
+			if err := s.fundsService.ReleaseHoldTx(tx, settlement.WinnerID, itemID, model.HoldKindBid); err != nil {
+				return err
+			}
+			if err := s.participation.ForfeitDepositTx(tx, itemID, settlement.WinnerID); err != nil {
+				return err
+			}
+
+			settlement.Status = model.SettlementStatusDefaulted
+			settlement.DefaultedAt = &now
+			if err := settlementRepo.Update(settlement); err != nil {
+				return err
+			}
+
+			changed = true
+			return s.itemRepo.WithTx(tx).FlagForReauction(itemID, "winner missed the payment deadline")
+		})
+		if err != nil {
+			return defaulted, err
+		}
+		if changed {
+			defaulted++
+		}
+	}
+	return defaulted, nil
+}
+
+// CancelSettlementTx voids the pending settlement after an admin cancels the
+// winning bid. Unlike a default, the winner is not at fault, so the bid hold
+// and the deposit are returned. The lot is flagged for re-auction.
+func (s *settlementService) CancelSettlementTx(tx *gorm.DB, itemID uint, reason string, now time.Time) error {
+	settlementRepo := s.settlementRepo.WithTx(tx)
+	settlement, err := settlementRepo.FindByItemIDForUpdate(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("settlement not found")
+		}
+		return err
+	}
+	if settlement.Status != model.SettlementStatusPending {
+		return errors.New("only a settlement awaiting payment can be cancelled")
+	}
+
+	if err := s.fundsService.ReleaseHoldTx(tx, settlement.WinnerID, itemID, model.HoldKindBid); err != nil {
+		return err
+	}
+	if err := s.participation.SettleDepositsTx(tx, itemID, ""); err != nil {
+		return err
+	}
+
+	settlement.Status = model.SettlementStatusCancelled
+	settlement.CancelledAt = &now
+	if err := settlementRepo.Update(settlement); err != nil {
+		return err
+	}
+	return s.itemRepo.WithTx(tx).FlagForReauction(itemID, reason)
+}
+
+func (s *settlementService) GetItemSettlement(itemID uint) (*model.Settlement, error) {
+	return s.settlementRepo.FindByItemID(itemID)
+}
+
+func (s *settlementService) GetMySettlement(itemID uint, userID string) (*model.Settlement, error) {
+	settlement, err := s.settlementRepo.FindByItemID(itemID)
+	if err != nil || settlement.WinnerID != userID {
+		return nil, errors.New("settlement not found")
+	}
+	return settlement, nil
+}
+
+func (s *settlementService) GetMySettlements(userID string) ([]model.Settlement, error) {
+	return s.settlementRepo.FindByWinnerID(userID)
+}
+
+// completeTx captures the outstanding amount and the deposit credit, then
+// moves the charges out of the proceeds
+func (s *settlementService) completeTx(tx *gorm.DB, settlement *model.Settlement, now time.Time) error {
+	if settlement.OutstandingAmount.IsPositive() {
+		if err := s.fundsService.CaptureHoldTx(tx, settlement.WinnerID, settlement.ItemID, model.HoldKindBid, settlement.OutstandingAmount); err != nil {
+			return err
+		}
+	}
+	if err := s.participation.ApplyDepositTx(tx, settlement.ItemID, settlement.WinnerID, settlement.DepositCredit); err != nil {
+		return err
+	}
+	if err := s.distributeChargesTx(tx, settlement); err != nil {
+		return err
+	}
+
+	settlement.Status = model.SettlementStatusPaid
+	settlement.PaidAt = &now
+	if err := s.settlementRepo.WithTx(tx).Update(settlement); err != nil {
+		return err
+	}
+	_, err := s.documents.IssueSettlementReceiptTx(tx, settlement)
+	return err
+}
+
+// distributeChargesTx books the buyer's and seller's charges out of the
+// proceeds: duties and premiums to fee revenue, taxes to taxes payable. What
+// stays in the proceeds is the seller's net.
+func (s *settlementService) distributeChargesTx(tx *gorm.DB, settlement *model.Settlement) error {
+	charges, err := s.settlementRepo.WithTx(tx).FindCharges(settlement.ID)
+	if err != nil {
+		return err
+	}
+
+	fees, taxes := decimal.Zero, decimal.Zero
+	for _, charge := range charges {
+		if charge.Kind == model.FeeKindTax {
+			taxes = taxes.Add(charge.Amount)
+		} else {
+			fees = fees.Add(charge.Amount)
+		}
+	}
+	total := fees.Add(taxes)
+	if !total.IsPositive() {
+		return nil
+	}
+
+	lines := []JournalLineRequest{{Account: PlatformProceedsAccount, Debit: total}}
+	if fees.IsPositive() {
+		lines = append(lines, JournalLineRequest{Account: PlatformFeeAccount, Credit: fees})
+	}
+	if taxes.IsPositive() {
+		lines = append(lines, JournalLineRequest{Account: PlatformTaxAccount, Credit: taxes})
+	}
+
+	reference := fmt.Sprintf("settlement:%d:charges", settlement.ID)
+	_, err = s.ledgerService.PostTx(tx, JournalRequest{
+		Type:        model.JournalEntryFee,
+		Description: fmt.Sprintf("Fees and taxes for item #%d", settlement.ItemID),
+		Reference:   &reference,
+		ItemID:      &settlement.ItemID,
+		Lines:       lines,
+	})
+	return err
+}
+
+// settlementCharges freezes the quote's lines for the settlement
+func settlementCharges(quote *FeeQuote) []model.SettlementCharge {
+	lines := append(append([]FeeLine{}, quote.BuyerCharges...), quote.SellerCharges...)
+	charges := make([]model.SettlementCharge, 0, len(lines))
+	for _, line := range lines {
+		ruleID := line.RuleID
+		charges = append(charges, model.SettlementCharge{
+			RuleID: &ruleID,
+			Code:   line.Code,
+			Name:   line.Name,
+			Party:  line.Party,
+			Kind:   line.Kind,
+			Rate:   line.Rate,
+			Amount: line.Amount,
+		})
+	}
+	return charges
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"yourapp/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ========== SOFT CLOSE ==========
+
+// SaveSoftCloseRule creates the rule for an item or organizer, or replaces
+// the existing one for the same target
+func (s *auctionService) SaveSoftCloseRule(req SoftCloseRuleRequest) (*model.SoftCloseRule, error) {
+	if (req.ItemID == nil) == (req.OrganizerID == nil) {
+		return nil, errors.New("exactly one of item_id or organizer_id is required")
+	}
+	if req.WindowMinutes <= 0 || req.ExtensionMinutes <= 0 || req.MaxExtensions <= 0 {
+		return nil, errors.New("window_minutes, extension_minutes and max_extensions must be positive")
+	}
+
+	var rule *model.SoftCloseRule
+	var err error
+	if req.ItemID != nil {
+		if _, err := s.itemRepo.FindByID(*req.ItemID); err != nil {
+			return nil, errors.New("auction item not found")
+		}
+		rule, err = s.softCloseRepo.FindRuleByItemID(*req.ItemID)
+	} else {
+		if _, err := s.organizerRepo.FindByID(*req.OrganizerID); err != nil {
+			return nil, errors.New("organizer not found")
+		}
+		rule, err = s.softCloseRepo.FindRuleByOrganizerID(*req.OrganizerID)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	if err != nil {
+		rule = &model.SoftCloseRule{
+			ItemID:      req.ItemID,
+			OrganizerID: req.OrganizerID,
+		}
+	}
+	rule.WindowMinutes = req.WindowMinutes
+	rule.ExtensionMinutes = req.ExtensionMinutes
+	rule.MaxExtensions = req.MaxExtensions
+	rule.IsActive = isActive
+
+	if rule.ID == 0 {
+		err = s.softCloseRepo.CreateRule(rule)
+	} else {
+		err = s.softCloseRepo.UpdateRule(rule)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *auctionService) GetSoftCloseRules() ([]model.SoftCloseRule, error) {
+	return s.softCloseRepo.FindAllRules()
+}
+
+func (s *auctionService) DeleteSoftCloseRule(id uint) error {
+	if _, err := s.softCloseRepo.FindRuleByID(id); err != nil {
+		return errors.New("soft close rule not found")
+	}
+	return s.softCloseRepo.DeleteRule(id)
+}
+
+func (s *auctionService) GetAuctionExtensions(itemID uint) ([]model.AuctionExtension, error) {
+	return s.softCloseRepo.FindExtensionsByItemID(itemID)
+}
+
+// applySoftClose pushes AuctionEnd out when a bid was accepted inside the
+// item's soft-close window. The caller must hold the item row lock.
+func (s *auctionService) applySoftClose(tx *gorm.DB, item *model.AuctionItem, bidID uint, now time.Time) error {
+	schedule := item.Schedule
+	if schedule == nil {
+		return nil
+	}
+
+	softCloseRepo := s.softCloseRepo.WithTx(tx)
+	rule, err := softCloseRepo.FindEffectiveRule(item.ID, item.OrganizerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	window := time.Duration(rule.WindowMinutes) * time.Minute
+	if schedule.AuctionEnd.Sub(now) > window {
+		return nil
+	}
+	if schedule.ExtensionCount >= rule.MaxExtensions {
+		return nil
+	}
+
+	previousEnd := schedule.AuctionEnd
+	if schedule.OriginalEnd == nil {
+		schedule.OriginalEnd = &previousEnd
+	}
+	schedule.AuctionEnd = previousEnd.Add(time.Duration(rule.ExtensionMinutes) * time.Minute)
+	schedule.ExtensionCount++
+
+	if err := s.scheduleRepo.WithTx(tx).Update(schedule); err != nil {
+		return err
+	}
+
+	ruleID := rule.ID
+	extension := &model.AuctionExtension{
+		ItemID:          item.ID,
+		BidID:           &bidID,
+		RuleID:          &ruleID,
+		ExtensionNumber: schedule.ExtensionCount,
+		PreviousEnd:     previousEnd,
+		NewEnd:          schedule.AuctionEnd,
+		Reason: fmt.Sprintf("bid #%d accepted %s before the scheduled end (soft-close window %d minutes)",
+			bidID, previousEnd.Sub(now).Round(time.Second), rule.WindowMinutes),
+	}
+	if err := softCloseRepo.CreateExtension(extension); err != nil {
+		return err
+	}
+
+	s.publishTx(tx, func() AuctionEvent {
+		return AuctionEvent{
+			Type:   AuctionEventExtended,
+			ItemID: item.ID,
+			Data: AuctionExtendedEvent{
+				PreviousEnd:    extension.PreviousEnd,
+				NewEnd:         extension.NewEnd,
+				ExtensionCount: extension.ExtensionNumber,
+			},
+		}
+	})
+	return nil
+}
@@ -0,0 +1,802 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrNotCommitteeMember is returned when a user acts on a tender they do not evaluate
+var ErrNotCommitteeMember = errors.New("user is not on the evaluation committee")
+
+var hundred = decimal.NewFromInt(100)
+
+type TenderService interface {
+	// Committee / admin
+	OpenTender(itemID uint, req OpenTenderRequest) (*model.Tender, error)
+	CloseSubmissions(itemID uint, force bool) (*model.Tender, error)
+	GetTender(itemID uint) (*model.Tender, error)
+	GetOffers(itemID uint) ([]model.TenderOffer, error)
+	ScoreOffer(itemID uint, req ScoreOfferRequest) error
+	GetEvaluation(itemID uint) (*TenderEvaluation, error)
+	SignOffEvaluation(itemID uint, userID string) (*TenderEvaluation, error)
+	PublishEvaluation(itemID uint, userID string) (*TenderEvaluation, error)
+
+	// Bidders
+	SubmitOffer(req SubmitOfferRequest) (*model.TenderOffer, error)
+	GetMyOffer(itemID uint, userID string) (*model.TenderOffer, error)
+	GetTenderResult(itemID uint) (*TenderEvaluation, error)
+}
+
+// ========== REQUEST/RESPONSE STRUCTS ==========
+
+type OpenTenderRequest struct {
+	Criteria  []TenderCriterionRequest `json:"criteria" binding:"required,dive"`
+	Committee []CommitteeMemberRequest `json:"committee" binding:"required,dive"`
+}
+
+type TenderCriterionRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight" binding:"required"`
+	IsPrice     bool    `json:"is_price"`
+	MaxScore    float64 `json:"max_score"`
+}
+
+type CommitteeMemberRequest struct {
+	UserID string              `json:"user_id" binding:"required"`
+	Role   model.CommitteeRole `json:"role"`
+}
+
+type SubmitOfferRequest struct {
+	ItemID      uint                    `json:"-"`
+	UserID      string                  `json:"-"`
+	OfferAmount float64                 `json:"offer_amount" binding:"required"`
+	Notes       string                  `json:"notes"`
+	Documents   []TenderDocumentRequest `json:"documents" binding:"required,dive"`
+}
+
+type TenderDocumentRequest struct {
+	DocumentName string `json:"document_name" binding:"required"`
+	DocumentURL  string `json:"document_url" binding:"required"`
+	DocumentType string `json:"document_type"`
+}
+
+type ScoreOfferRequest struct {
+	OfferID     uint                    `json:"offer_id" binding:"required"`
+	EvaluatorID string                  `json:"-"`
+	Scores      []CriterionScoreRequest `json:"scores" binding:"required,dive"`
+}
+
+type CriterionScoreRequest struct {
+	CriterionID uint    `json:"criterion_id" binding:"required"`
+	Score       float64 `json:"score"`
+	Notes       string  `json:"notes"`
+}
+
+// TenderEvaluation is the weighted ranking of a tender's offers
+type TenderEvaluation struct {
+	Tender        *model.Tender     `json:"tender"`
+	Ranking       []TenderRankEntry `json:"ranking"`
+	Digest        string            `json:"digest"`
+	Complete      bool              `json:"complete"` // every evaluator scored every offer
+	SignedOff     int               `json:"signed_off"`
+	CommitteeSize int               `json:"committee_size"`
+}
+
+type TenderRankEntry struct {
+	Rank        int                    `json:"rank"`
+	OfferID     uint                   `json:"offer_id"`
+	UserID      string                 `json:"user_id"`
+	OfferAmount string                 `json:"offer_amount"`
+	TotalScore  string                 `json:"total_score"`
+	Criteria    []TenderCriterionScore `json:"criteria"`
+}
+
+type TenderCriterionScore struct {
+	CriterionID uint   `json:"criterion_id"`
+	Name        string `json:"name"`
+	Score       string `json:"score"`    // average on the criterion's own scale
+	Weighted    string `json:"weighted"` // contribution to the 0-100 total
+}
+
+// ========== SERVICE IMPLEMENTATION ==========
+
+type tenderService struct {
+	transactor    repository.Transactor
+	tenderRepo    repository.TenderRepository
+	itemRepo      repository.AuctionItemRepository
+	userRepo      repository.UserRepository
+	fundsService  FundsService
+	participation ParticipationService
+	auctions      AuctionService
+}
+
+func NewTenderService(
+	transactor repository.Transactor,
+	tenderRepo repository.TenderRepository,
+	itemRepo repository.AuctionItemRepository,
+	userRepo repository.UserRepository,
+	fundsService FundsService,
+	participation ParticipationService,
+	auctions AuctionService,
+) TenderService {
+	return &tenderService{
+		transactor:    transactor,
+		tenderRepo:    tenderRepo,
+		itemRepo:      itemRepo,
+		userRepo:      userRepo,
+		fundsService:  fundsService,
+		participation: participation,
+		auctions:      auctions,
+	}
+}
+
+// ========== COMMITTEE ==========
+
+// OpenTender fixes the criteria and committee of a published tender lot and
+// starts accepting offers. Committee members must be admin users, since the
+// committee works through the admin routes.
+func (s *tenderService) OpenTender(itemID uint, req OpenTenderRequest) (*model.Tender, error) {
+	if len(req.Criteria) == 0 {
+		return nil, errors.New("at least one criterion is required")
+	}
+	if len(req.Committee) == 0 {
+		return nil, errors.New("at least one committee member is required")
+	}
+
+	criteria := make([]model.TenderCriterion, 0, len(req.Criteria))
+	priceCriteria := 0
+	for _, c := range req.Criteria {
+		if c.Weight <= 0 {
+			return nil, fmt.Errorf("criterion %q must have a positive weight", c.Name)
+		}
+		maxScore := decimal.NewFromFloat(c.MaxScore)
+		if c.MaxScore <= 0 {
+			maxScore = hundred
+		}
+		if c.IsPrice {
+			priceCriteria++
+		}
+		criteria = append(criteria, model.TenderCriterion{
+			Name:        c.Name,
+			Description: stringPtr(c.Description),
+			Weight:      decimal.NewFromFloat(c.Weight),
+			IsPrice:     c.IsPrice,
+			MaxScore:    maxScore,
+		})
+	}
+	if priceCriteria > 1 {
+		return nil, errors.New("only one price criterion is allowed")
+	}
+
+	committee := make([]model.TenderCommitteeMember, 0, len(req.Committee))
+	seen := make(map[string]bool)
+	chairs := 0
+	for _, m := range req.Committee {
+		if seen[m.UserID] {
+			return nil, fmt.Errorf("user %s is listed twice", m.UserID)
+		}
+		seen[m.UserID] = true
+		user, err := s.userRepo.FindByID(m.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("committee user %s not found", m.UserID)
+		}
+		if user.UserType != model.UserTypeAdmin {
+			return nil, fmt.Errorf("committee user %s is not an admin", m.UserID)
+		}
+
+		role := m.Role
+		switch role {
+		case model.CommitteeRoleChair:
+			chairs++
+		case "", model.CommitteeRoleMember:
+			role = model.CommitteeRoleMember
+		default:
+			return nil, fmt.Errorf("invalid committee role: %s", m.Role)
+		}
+		committee = append(committee, model.TenderCommitteeMember{UserID: m.UserID, Role: role})
+	}
+	if chairs != 1 {
+		return nil, errors.New("the committee needs exactly one chair")
+	}
+
+	var tender *model.Tender
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		item, err := itemRepo.FindByIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		if item.AuctionMethod != model.AuctionMethodTender {
+			return errors.New("auction item is not a tender lot")
+		}
+		if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return errors.New("tender lot must be published before opening")
+		}
+		if _, err := tenderRepo.FindByItemID(itemID); err == nil {
+			return errors.New("tender already opened for this item")
+		}
+
+		tender = &model.Tender{
+			ItemID:    itemID,
+			Status:    model.TenderStatusOpen,
+			OpenedAt:  time.Now(),
+			Criteria:  criteria,
+			Committee: committee,
+		}
+		if err := tenderRepo.Create(tender); err != nil {
+			return err
+		}
+
+		return itemRepo.UpdateStatus(itemID, model.AuctionStatusOngoing)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tenderRepo.FindByItemID(itemID)
+}
+
+// CloseSubmissions stops accepting offers and starts the evaluation. Before
+// the lot's submission period has ended this only happens when force is set.
+func (s *tenderService) CloseSubmissions(itemID uint, force bool) (*model.Tender, error) {
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		tender, err := tenderRepo.FindByItemIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("tender not found")
+		}
+		if tender.Status != model.TenderStatusOpen {
+			return errors.New("tender is not accepting offers")
+		}
+
+		now := time.Now()
+		if !force && item.Schedule != nil && now.Before(item.Schedule.AuctionEnd) {
+			return errors.New("tender submission period has not ended; pass force=true to close it early")
+		}
+
+		tender.Status = model.TenderStatusEvaluating
+		tender.ClosedAt = &now
+		return tenderRepo.Update(tender)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tenderRepo.FindByItemID(itemID)
+}
+
+func (s *tenderService) GetTender(itemID uint) (*model.Tender, error) {
+	return s.tenderRepo.FindByItemID(itemID)
+}
+
+func (s *tenderService) GetOffers(itemID uint) ([]model.TenderOffer, error) {
+	tender, err := s.tenderRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if tender.Status == model.TenderStatusOpen {
+		return nil, errors.New("offers stay closed until submissions end")
+	}
+	return s.tenderRepo.FindSubmittedOffers(tender.ID)
+}
+
+// ScoreOffer records the evaluator's scores for one offer. Any change voids
+// sign-offs already given, since they covered a different ranking.
+func (s *tenderService) ScoreOffer(itemID uint, req ScoreOfferRequest) error {
+	return s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		tender, err := tenderRepo.FindByItemIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("tender not found")
+		}
+		if tender.Status != model.TenderStatusEvaluating {
+			return errors.New("tender is not under evaluation")
+		}
+		if committeeMember(tender, req.EvaluatorID) == nil {
+			return ErrNotCommitteeMember
+		}
+
+		offer, err := tenderRepo.FindOfferByID(req.OfferID)
+		if err != nil || offer.TenderID != tender.ID || offer.Status == model.TenderOfferStatusWithdrawn {
+			return errors.New("offer not found")
+		}
+
+		for _, sc := range req.Scores {
+			criterion := tenderCriterion(tender, sc.CriterionID)
+			if criterion == nil {
+				return fmt.Errorf("criterion %d not found", sc.CriterionID)
+			}
+			if criterion.IsPrice {
+				return fmt.Errorf("criterion %q is scored from the offer amount", criterion.Name)
+			}
+			score := decimal.NewFromFloat(sc.Score)
+			if score.IsNegative() || score.GreaterThan(criterion.MaxScore) {
+				return fmt.Errorf("score for %q must be between 0 and %s", criterion.Name, criterion.MaxScore.String())
+			}
+
+			if err := tenderRepo.SaveScore(&model.TenderScore{
+				OfferID:     offer.ID,
+				CriterionID: criterion.ID,
+				EvaluatorID: req.EvaluatorID,
+				Score:       score,
+				Notes:       stringPtr(sc.Notes),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return tenderRepo.ClearSignOffs(tender.ID)
+	})
+}
+
+func (s *tenderService) GetEvaluation(itemID uint) (*TenderEvaluation, error) {
+	tender, err := s.tenderRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if tender.Status == model.TenderStatusOpen {
+		return nil, errors.New("offers stay closed until submissions end")
+	}
+	return s.evaluate(s.tenderRepo, tender)
+}
+
+// SignOffEvaluation records the member's approval of the ranking as it stands
+func (s *tenderService) SignOffEvaluation(itemID uint, userID string) (*TenderEvaluation, error) {
+	var evaluation *TenderEvaluation
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		tender, err := tenderRepo.FindByItemIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("tender not found")
+		}
+		if tender.Status != model.TenderStatusEvaluating {
+			return errors.New("tender is not under evaluation")
+		}
+		member := committeeMember(tender, userID)
+		if member == nil {
+			return ErrNotCommitteeMember
+		}
+
+		evaluation, err = s.evaluate(tenderRepo, tender)
+		if err != nil {
+			return err
+		}
+		if len(evaluation.Ranking) == 0 {
+			return errors.New("there are no offers to evaluate")
+		}
+		if !evaluation.Complete {
+			return errors.New("every committee member must score every offer before sign-off")
+		}
+
+		now := time.Now()
+		member.SignedDigest = &evaluation.Digest
+		member.SignedOffAt = &now
+		if err := tenderRepo.UpdateCommitteeMember(member); err != nil {
+			return err
+		}
+
+		evaluation.SignedOff = countSignOffs(tender, evaluation.Digest)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// PublishEvaluation declares the top-ranked offer the winner and closes the
+// lot like any other: the offer must reach the limit price, the winner's
+// settlement is opened and every bidder is told the outcome. Only the chair
+// may publish, and only once the whole committee signed off on this ranking.
+func (s *tenderService) PublishEvaluation(itemID uint, userID string) (*TenderEvaluation, error) {
+	var evaluation *TenderEvaluation
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		tender, err := tenderRepo.FindByItemIDForUpdate(itemID)
+		if err != nil {
+			return errors.New("tender not found")
+		}
+		if tender.Status != model.TenderStatusEvaluating {
+			return errors.New("tender is not under evaluation")
+		}
+		member := committeeMember(tender, userID)
+		if member == nil {
+			return ErrNotCommitteeMember
+		}
+		if member.Role != model.CommitteeRoleChair {
+			return errors.New("only the committee chair can publish the evaluation")
+		}
+
+		evaluation, err = s.evaluate(tenderRepo, tender)
+		if err != nil {
+			return err
+		}
+		if len(evaluation.Ranking) == 0 {
+			return errors.New("there are no offers to evaluate")
+		}
+		if evaluation.SignedOff != evaluation.CommitteeSize {
+			return fmt.Errorf("%d of %d committee members signed off on the current ranking",
+				evaluation.SignedOff, evaluation.CommitteeSize)
+		}
+
+		winner := evaluation.Ranking[0]
+		winningAmount, err := decimal.NewFromString(winner.OfferAmount)
+		if err != nil {
+			return err
+		}
+		sold := reserveMet(item, winningAmount)
+
+		offers, err := tenderRepo.FindSubmittedOffers(tender.ID)
+		if err != nil {
+			return err
+		}
+		bids := make([]model.Bid, 0, len(evaluation.Ranking))
+		for _, entry := range evaluation.Ranking {
+			offer := tenderOffer(offers, entry.OfferID)
+			total, err := decimal.NewFromString(entry.TotalScore)
+			if err != nil {
+				return err
+			}
+			rank := entry.Rank
+			offer.TotalScore = &total
+			offer.Rank = &rank
+			offer.Status = model.TenderOfferStatusNotSelected
+			if rank == 1 && sold {
+				offer.Status = model.TenderOfferStatusWinner
+			}
+			if err := tenderRepo.UpdateOffer(offer); err != nil {
+				return err
+			}
+			bids = append(bids, model.Bid{
+				UserID:    offer.UserID,
+				BidAmount: offer.OfferAmount,
+				BidTime:   offer.SubmittedAt,
+			})
+		}
+
+		now := time.Now()
+		tender.Status = model.TenderStatusPublished
+		if sold {
+			tender.WinningOfferID = &winner.OfferID
+		}
+		tender.PublishedAt = &now
+		if err := tenderRepo.Update(tender); err != nil {
+			return err
+		}
+
+		amountFloat, _ := winningAmount.Float64()
+		if err := s.itemRepo.WithTx(tx).UpdateBidInfo(itemID, amountFloat, len(evaluation.Ranking)); err != nil {
+			return err
+		}
+		item.CurrentHighestBid = winningAmount
+
+		// Settlement, hold release and won/lost notices follow the offers
+		// recorded as bids
+		if err := s.auctions.CloseTenderTx(tx, item, bids, now); err != nil {
+			return err
+		}
+
+		evaluation.Tender = tender
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// ========== BIDDERS ==========
+
+// SubmitOffer files the bidder's offer with its documents, or replaces it if
+// one exists. Offers are accepted while the tender is open and the lot's
+// schedule window is running.
+func (s *tenderService) SubmitOffer(req SubmitOfferRequest) (*model.TenderOffer, error) {
+	if len(req.Documents) == 0 {
+		return nil, errors.New("at least one supporting document is required")
+	}
+	amount := decimal.NewFromFloat(req.OfferAmount)
+	if !amount.IsPositive() {
+		return nil, errors.New("offer amount must be positive")
+	}
+
+	var offer *model.TenderOffer
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		tenderRepo := s.tenderRepo.WithTx(tx)
+
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(req.ItemID)
+		if err != nil {
+			return errors.New("auction item not found")
+		}
+		tender, err := tenderRepo.FindByItemIDForUpdate(req.ItemID)
+		if err != nil || tender.Status != model.TenderStatusOpen {
+			return errors.New("tender is not accepting offers")
+		}
+
+		now := time.Now()
+		if item.Schedule != nil {
+			if now.Before(item.Schedule.AuctionStart) {
+				return errors.New("tender has not started yet")
+			}
+			if now.After(item.Schedule.AuctionEnd) {
+				return errors.New("tender submission period has ended")
+			}
+		}
+		if committeeMember(tender, req.UserID) != nil {
+			return errors.New("committee members cannot submit offers")
+		}
+		if err := s.participation.RequireEligibleTx(tx, item.ID, req.UserID); err != nil {
+			return err
+		}
+		if amount.LessThan(item.StartingPrice) {
+			return fmt.Errorf("offer must be at least the starting price: %s", item.StartingPrice.String())
+		}
+
+		// The offer stays reserved until the tender is published; a revision
+		// may lower the hold as well as raise it
+		if err := s.fundsService.SetHoldTx(tx, req.UserID, item.ID, model.HoldKindBid, amount); err != nil {
+			return err
+		}
+
+		offer, err = tenderRepo.FindOfferByUser(tender.ID, req.UserID)
+		if err == nil {
+			offer.OfferAmount = amount
+			offer.Notes = stringPtr(req.Notes)
+			offer.SubmittedAt = now
+			if err := tenderRepo.UpdateOffer(offer); err != nil {
+				return err
+			}
+		} else {
+			offer = &model.TenderOffer{
+				TenderID:    tender.ID,
+				UserID:      req.UserID,
+				OfferAmount: amount,
+				Notes:       stringPtr(req.Notes),
+				Status:      model.TenderOfferStatusSubmitted,
+				SubmittedAt: now,
+			}
+			if err := tenderRepo.CreateOffer(offer); err != nil {
+				return err
+			}
+		}
+
+		documents := make([]model.TenderDocument, 0, len(req.Documents))
+		for _, d := range req.Documents {
+			documents = append(documents, model.TenderDocument{
+				DocumentName: d.DocumentName,
+				DocumentURL:  d.DocumentURL,
+				DocumentType: stringPtr(d.DocumentType),
+			})
+		}
+		return tenderRepo.ReplaceDocuments(offer.ID, documents)
+	})
+	if err != nil {
+		if isRetryableTxError(err) {
+			return nil, ErrBidConflict
+		}
+		return nil, err
+	}
+
+	return s.tenderRepo.FindOfferByID(offer.ID)
+}
+
+func (s *tenderService) GetMyOffer(itemID uint, userID string) (*model.TenderOffer, error) {
+	tender, err := s.tenderRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	return s.tenderRepo.FindOfferByUser(tender.ID, userID)
+}
+
+// GetTenderResult returns the ranking once it has been published
+func (s *tenderService) GetTenderResult(itemID uint) (*TenderEvaluation, error) {
+	tender, err := s.tenderRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if tender.Status != model.TenderStatusPublished {
+		return nil, errors.New("tender result has not been published")
+	}
+	return s.evaluate(s.tenderRepo, tender)
+}
+
+// ========== EVALUATION ==========
+
+// evaluate ranks the tender's offers by weighted score on a 0-100 scale.
+// Committee criteria use the average of the evaluators' scores; the price
+// criterion scores each offer relative to the highest offer. Ties go to the
+// higher offer, then the earlier submission.
+func (s *tenderService) evaluate(tenderRepo repository.TenderRepository, tender *model.Tender) (*TenderEvaluation, error) {
+	offers, err := tenderRepo.FindSubmittedOffers(tender.ID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := tenderRepo.FindScoresByTender(tender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// scoreSums[offer][criterion] and scoreCounts alike
+	scoreSums := make(map[uint]map[uint]decimal.Decimal)
+	scoreCounts := make(map[uint]map[uint]int)
+	for _, sc := range scores {
+		if committeeMember(tender, sc.EvaluatorID) == nil {
+			continue
+		}
+		if scoreSums[sc.OfferID] == nil {
+			scoreSums[sc.OfferID] = make(map[uint]decimal.Decimal)
+			scoreCounts[sc.OfferID] = make(map[uint]int)
+		}
+		scoreSums[sc.OfferID][sc.CriterionID] = scoreSums[sc.OfferID][sc.CriterionID].Add(sc.Score)
+		scoreCounts[sc.OfferID][sc.CriterionID]++
+	}
+
+	totalWeight := decimal.Zero
+	for _, c := range tender.Criteria {
+		totalWeight = totalWeight.Add(c.Weight)
+	}
+	highestOffer := decimal.Zero
+	for _, o := range offers {
+		highestOffer = decimal.Max(highestOffer, o.OfferAmount)
+	}
+
+	type scored struct {
+		offer model.TenderOffer
+		total decimal.Decimal
+		entry TenderRankEntry
+	}
+
+	complete := true
+	results := make([]scored, 0, len(offers))
+	for _, o := range offers {
+		total := decimal.Zero
+		criteria := make([]TenderCriterionScore, 0, len(tender.Criteria))
+		for _, c := range tender.Criteria {
+			var average decimal.Decimal
+			if c.IsPrice {
+				if highestOffer.IsPositive() {
+					average = o.OfferAmount.Div(highestOffer).Mul(c.MaxScore)
+				}
+			} else {
+				count := scoreCounts[o.ID][c.ID]
+				if count < len(tender.Committee) {
+					complete = false
+				}
+				if count > 0 {
+					average = scoreSums[o.ID][c.ID].Div(decimal.NewFromInt(int64(count)))
+				}
+			}
+
+			weighted := decimal.Zero
+			if totalWeight.IsPositive() {
+				weighted = average.Div(c.MaxScore).Mul(c.Weight).Div(totalWeight).Mul(hundred)
+			}
+			total = total.Add(weighted)
+			criteria = append(criteria, TenderCriterionScore{
+				CriterionID: c.ID,
+				Name:        c.Name,
+				Score:       average.StringFixed(2),
+				Weighted:    weighted.StringFixed(4),
+			})
+		}
+
+		total = total.Round(4)
+		results = append(results, scored{
+			offer: o,
+			total: total,
+			entry: TenderRankEntry{
+				OfferID:     o.ID,
+				UserID:      o.UserID,
+				OfferAmount: o.OfferAmount.StringFixed(2),
+				TotalScore:  total.StringFixed(4),
+				Criteria:    criteria,
+			},
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if !a.total.Equal(b.total) {
+			return a.total.GreaterThan(b.total)
+		}
+		if !a.offer.OfferAmount.Equal(b.offer.OfferAmount) {
+			return a.offer.OfferAmount.GreaterThan(b.offer.OfferAmount)
+		}
+		if !a.offer.SubmittedAt.Equal(b.offer.SubmittedAt) {
+			return a.offer.SubmittedAt.Before(b.offer.SubmittedAt)
+		}
+		return a.offer.ID < b.offer.ID
+	})
+
+	ranking := make([]TenderRankEntry, 0, len(results))
+	for i, r := range results {
+		r.entry.Rank = i + 1
+		ranking = append(ranking, r.entry)
+	}
+
+	digest := tenderDigest(tender.ItemID, ranking)
+	return &TenderEvaluation{
+		Tender:        tender,
+		Ranking:       ranking,
+		Digest:        digest,
+		Complete:      complete,
+		SignedOff:     countSignOffs(tender, digest),
+		CommitteeSize: len(tender.Committee),
+	}, nil
+}
+
+// tenderDigest hashes the ranking in a canonical line format, so a sign-off
+// can be matched to the exact ranking it approved
+func tenderDigest(itemID uint, ranking []TenderRankEntry) string {
+	var b strings.Builder
+	for _, r := range ranking {
+		fmt.Fprintf(&b, "%d|%d|%d|%s|%s|%s\n",
+			itemID, r.Rank, r.OfferID, r.UserID, r.OfferAmount, r.TotalScore)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func countSignOffs(tender *model.Tender, digest string) int {
+	count := 0
+	for _, m := range tender.Committee {
+		if m.SignedDigest != nil && *m.SignedDigest == digest {
+			count++
+		}
+	}
+	return count
+}
+
+func committeeMember(tender *model.Tender, userID string) *model.TenderCommitteeMember {
+	for i := range tender.Committee {
+		if tender.Committee[i].UserID == userID {
+			return &tender.Committee[i]
+		}
+	}
+	return nil
+}
+
+func tenderCriterion(tender *model.Tender, id uint) *model.TenderCriterion {
+	for i := range tender.Criteria {
+		if tender.Criteria[i].ID == id {
+			return &tender.Criteria[i]
+		}
+	}
+	return nil
+}
+
+func tenderOffer(offers []model.TenderOffer, id uint) *model.TenderOffer {
+	for i := range offers {
+		if offers[i].ID == id {
+			return &offers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// minTopUpAmount is the smallest top-up a virtual account is issued for
+var minTopUpAmount = decimal.NewFromInt(10000)
+
+// TopUpService issues virtual accounts for wallet top-ups and credits the
+// wallet when the gateway reports the payment
+type TopUpService interface {
+	CreateTopUp(req CreateTopUpRequest) (*model.TopUpOrder, error)
+	GetTopUp(orderID uint, userID string) (*model.TopUpOrder, error)
+	GetMyTopUps(userID string) ([]model.TopUpOrder, error)
+	HandleWebhook(payload []byte, signature string) (*model.TopUpOrder, error)
+	SimulatePayment(orderID uint, userID string) (*model.TopUpOrder, error)
+	ExpireStaleTopUps(now time.Time) (int64, error)
+}
+
+type CreateTopUpRequest struct {
+	UserID string  `json:"-"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Bank   string  `json:"bank" binding:"required"`
+}
+
+type topUpService struct {
+	transactor    repository.Transactor
+	topUpRepo     repository.TopUpRepository
+	userRepo      repository.UserRepository
+	ledgerService LedgerService
+	gateway       PaymentGateway
+	documents     DocumentService
+	expiry        time.Duration
+}
+
+func NewTopUpService(
+	transactor repository.Transactor,
+	topUpRepo repository.TopUpRepository,
+	userRepo repository.UserRepository,
+	ledgerService LedgerService,
+	gateway PaymentGateway,
+	documents DocumentService,
+	expiry time.Duration,
+) TopUpService {
+	return &topUpService{
+		transactor:    transactor,
+		topUpRepo:     topUpRepo,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		gateway:       gateway,
+		documents:     documents,
+		expiry:        expiry,
+	}
+}
+
+func (s *topUpService) CreateTopUp(req CreateTopUpRequest) (*model.TopUpOrder, error) {
+	user, err := s.userRepo.FindByID(req.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
+	if amount.LessThan(minTopUpAmount) {
+		return nil, fmt.Errorf("top-up must be at least %s", minTopUpAmount.String())
+	}
+
+	code, err := randomDigits(8)
+	if err != nil {
+		return nil, err
+	}
+	order := &model.TopUpOrder{
+		OrderCode: fmt.Sprintf("TU-%s-%s", time.Now().Format("20060102"), code),
+		UserID:    user.ID,
+		Amount:    amount,
+		Method:    model.PaymentMethodVirtualAccount,
+		Bank:      strings.ToUpper(req.Bank),
+		Gateway:   s.gateway.Name(),
+		Status:    model.TopUpStatusPending,
+		ExpiresAt: time.Now().Add(s.expiry),
+	}
+
+	va, err := s.gateway.CreateVirtualAccount(VirtualAccountRequest{
+		OrderCode:    order.OrderCode,
+		Bank:         order.Bank,
+		CustomerName: user.FullName,
+		Amount:       amount,
+		ExpiresAt:    order.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.GatewayReference = &va.Reference
+	order.VANumber = &va.Number
+
+	if err := s.topUpRepo.Create(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *topUpService) GetTopUp(orderID uint, userID string) (*model.TopUpOrder, error) {
+	order, err := s.topUpRepo.FindByID(orderID)
+	if err != nil || order.UserID != userID {
+		return nil, errors.New("top-up not found")
+	}
+	return order, nil
+}
+
+func (s *topUpService) GetMyTopUps(userID string) ([]model.TopUpOrder, error) {
+	return s.topUpRepo.FindByUserID(userID)
+}
+
+// HandleWebhook applies a signed gateway notification. It is idempotent:
+// redelivered notifications for a settled order change nothing, and the
+// ledger entry is keyed by the order code.
+func (s *topUpService) HandleWebhook(payload []byte, signature string) (*model.TopUpOrder, error) {
+	notification, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyNotification(notification)
+}
+
+// SimulatePayment pays a pending top-up through the simulator's signed
+// webhook path. It only works when the configured gateway is a simulator.
+func (s *topUpService) SimulatePayment(orderID uint, userID string) (*model.TopUpOrder, error) {
+	simulator, ok := s.gateway.(PaymentSimulator)
+	if !ok {
+		return nil, errors.New("payment simulation is not available for this gateway")
+	}
+
+	order, err := s.GetTopUp(orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reference := ""
+	if order.GatewayReference != nil {
+		reference = *order.GatewayReference
+	}
+	payload, signature, err := simulator.SimulatePayment(PaymentNotification{
+		OrderCode: order.OrderCode,
+		Reference: reference,
+		Status:    PaymentNotificationPaid,
+		Amount:    order.Amount,
+		PaidAt:    &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.HandleWebhook(payload, signature)
+}
+
+// ExpireStaleTopUps expires pending orders whose virtual account has lapsed
+func (s *topUpService) ExpireStaleTopUps(now time.Time) (int64, error) {
+	return s.topUpRepo.ExpirePending(now)
+}
+
+func (s *topUpService) applyNotification(n *PaymentNotification) (*model.TopUpOrder, error) {
+	var order *model.TopUpOrder
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		topUpRepo := s.topUpRepo.WithTx(tx)
+
+		var err error
+		order, err = topUpRepo.FindByCodeForUpdate(n.OrderCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("top-up not found")
+			}
+			return err
+		}
+		if order.GatewayReference != nil && n.Reference != "" && *order.GatewayReference != n.Reference {
+			return errors.New("gateway reference does not match the top-up")
+		}
+
+		// Already settled: a redelivery, nothing to do
+		if order.Status == model.TopUpStatusPaid {
+			return nil
+		}
+
+		switch n.Status {
+		case PaymentNotificationPaid:
+			if !n.Amount.Equal(order.Amount) {
+				return fmt.Errorf("paid amount %s does not match the top-up amount %s", n.Amount.String(), order.Amount.String())
+			}
+			// A payment that lands after expiry is still money received, so
+			// it is credited rather than bounced
+			return s.creditTx(tx, order, n)
+		case PaymentNotificationExpired:
+			if order.Status != model.TopUpStatusPending {
+				return nil
+			}
+			order.Status = model.TopUpStatusExpired
+		case PaymentNotificationFailed:
+			if order.Status != model.TopUpStatusPending {
+				return nil
+			}
+			order.Status = model.TopUpStatusFailed
+			order.FailureReason = stringPtr(n.Reason)
+		default:
+			return fmt.Errorf("unknown payment status %q", n.Status)
+		}
+		return topUpRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// creditTx marks the order paid, moves the amount from platform cash into
+// the user's wallet and issues the receipt
+func (s *topUpService) creditTx(tx *gorm.DB, order *model.TopUpOrder, n *PaymentNotification) error {
+	reference := "topup:" + order.OrderCode
+	entry, err := s.ledgerService.TransferTx(tx, TransferRequest{
+		Type:        model.JournalEntryTopUp,
+		From:        PlatformCashAccount,
+		To:          UserAvailableAccount(order.UserID),
+		Amount:      order.Amount,
+		Description: fmt.Sprintf("Top-up %s via %s virtual account", order.OrderCode, order.Bank),
+		Reference:   &reference,
+	})
+	if err != nil {
+		return err
+	}
+
+	paidAt := time.Now()
+	if n.PaidAt != nil {
+		paidAt = *n.PaidAt
+	}
+	order.Status = model.TopUpStatusPaid
+	order.PaidAt = &paidAt
+	order.FailureReason = nil
+	order.JournalEntryID = &entry.ID
+	if err := s.topUpRepo.WithTx(tx).Update(order); err != nil {
+		return err
+	}
+	_, err = s.documents.IssueTopUpReceiptTx(tx, order)
+	return err
+}
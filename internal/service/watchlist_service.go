@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// WatchlistService lets users follow lots without bidding. Watchers are
+// reminded a configurable time before a lot starts and before it ends.
+type WatchlistService interface {
+	Watch(itemID uint, userID string) (*model.WatchlistEntry, error)
+	Unwatch(itemID uint, userID string) error
+	GetMyWatchlist(userID string) ([]model.WatchlistEntry, error)
+	WatchedItemIDs(userID string, itemIDs []uint) (map[uint]bool, error)
+
+	// Used by the scheduler
+	RemindWatchers(now time.Time) (int, error)
+}
+
+type watchlistService struct {
+	transactor    repository.Transactor
+	watchlistRepo repository.WatchlistRepository
+	itemRepo      repository.AuctionItemRepository
+	bidRepo       repository.BidRepository
+	notifications NotificationService
+	startLead     time.Duration
+	endLead       time.Duration
+}
+
+func NewWatchlistService(
+	transactor repository.Transactor,
+	watchlistRepo repository.WatchlistRepository,
+	itemRepo repository.AuctionItemRepository,
+	bidRepo repository.BidRepository,
+	notifications NotificationService,
+	startLead time.Duration,
+	endLead time.Duration,
+) WatchlistService {
+	return &watchlistService{
+		transactor:    transactor,
+		watchlistRepo: watchlistRepo,
+		itemRepo:      itemRepo,
+		bidRepo:       bidRepo,
+		notifications: notifications,
+		startLead:     startLead,
+		endLead:       endLead,
+	}
+}
+
+// ========== WATCHLIST ==========
+
+// Watch adds a published or running lot to the user's watchlist. Watching a
+// lot twice returns the existing entry.
+func (s *watchlistService) Watch(itemID uint, userID string) (*model.WatchlistEntry, error) {
+	var entry *model.WatchlistEntry
+	err := s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+		item, err := itemRepo.FindByID(itemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("auction item not found")
+			}
+			return err
+		}
+		if item.Status != model.AuctionStatusPublished && item.Status != model.AuctionStatusOngoing {
+			return errors.New("only published or ongoing lots can be watched")
+		}
+
+		watchlistRepo := s.watchlistRepo.WithTx(tx)
+		if existing, err := watchlistRepo.FindByUserAndItem(userID, itemID); err == nil {
+			entry = existing
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		entry = &model.WatchlistEntry{UserID: userID, ItemID: itemID}
+		if err := watchlistRepo.Create(entry); err != nil {
+			return err
+		}
+		return itemRepo.AdjustWatcherCount(itemID, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *watchlistService) Unwatch(itemID uint, userID string) error {
+	return s.transactor.WithinTransaction(func(tx *gorm.DB) error {
+		removed, err := s.watchlistRepo.WithTx(tx).Delete(userID, itemID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errors.New("lot is not on your watchlist")
+		}
+		return s.itemRepo.WithTx(tx).AdjustWatcherCount(itemID, -1)
+	})
+}
+
+func (s *watchlistService) GetMyWatchlist(userID string) ([]model.WatchlistEntry, error) {
+	return s.watchlistRepo.FindByUser(userID)
+}
+
+// WatchedItemIDs reports which of itemIDs the user watches
+func (s *watchlistService) WatchedItemIDs(userID string, itemIDs []uint) (map[uint]bool, error) {
+	ids, err := s.watchlistRepo.FindWatchedItemIDs(userID, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		watched[id] = true
+	}
+	return watched, nil
+}
+
+// ========== REMINDERS ==========
+
+// RemindWatchers notifies the watchers of lots starting within startLead and
+// of lots ending within endLead. Watchers who already bid on a lot get the
+// bidders' ending-soon notice instead of a second reminder.
+func (s *watchlistService) RemindWatchers(now time.Time) (int, error) {
+	starting, err := s.itemRepo.FindStartingBetween(now, now.Add(s.startLead))
+	if err != nil {
+		return 0, err
+	}
+	ending, err := s.itemRepo.FindEndingBetween(now, now.Add(s.endLead))
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, id := range starting {
+		raised += s.remind(id, model.NotificationWatchStarting)
+	}
+	for _, id := range ending {
+		raised += s.remind(id, model.NotificationWatchEnding)
+	}
+	return raised, nil
+}
+
+func (s *watchlistService) remind(itemID uint, notificationType model.NotificationType) int {
+	watchers, err := s.watchlistRepo.FindUserIDsByItem(itemID)
+	if err != nil {
+		log.Printf("Watchlist: failed to load watchers of item %d: %v", itemID, err)
+		return 0
+	}
+	if len(watchers) == 0 {
+		return 0
+	}
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return 0
+	}
+
+	bidders := make(map[string]bool)
+	if notificationType == model.NotificationWatchEnding {
+		bids, err := s.bidRepo.FindValidByItemID(itemID)
+		if err != nil {
+			log.Printf("Watchlist: failed to load bids of item %d: %v", itemID, err)
+			return 0
+		}
+		for _, bid := range bids {
+			bidders[bid.UserID] = true
+		}
+	}
+
+	raised := 0
+	for _, userID := range watchers {
+		if bidders[userID] {
+			continue
+		}
+		created, err := s.notifications.NotifyWatcher(item, userID, notificationType)
+		if err != nil {
+			log.Printf("Watchlist: failed to remind user %s of item %d: %v", userID, itemID, err)
+			continue
+		}
+		if created {
+			raised++
+		}
+	}
+	return raised
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"yourapp/internal/config"
 
@@ -17,15 +18,54 @@ type RabbitMQClient struct {
 }
 
 type EmailMessage struct {
-	To      string `json:"to"`
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
-	Type    string `json:"type"` // "otp", "reset_password", "verification"
+	To          string            `json:"to"`
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`
+	Type        string            `json:"type"` // "otp", "reset_password", "verification", "document", or an auction notification type
+	Attachments []EmailAttachment `json:"attachments,omitempty"`
+	Auction     *AuctionEmail     `json:"auction,omitempty"`
+}
+
+// EmailAttachment is a file sent along with an email, e.g. an invoice PDF
+type EmailAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"` // base64 in the JSON message
+}
+
+// AuctionEmail carries what an auction notification email shows. The message
+// Type names the notification: "outbid", "ending_soon", "won", "lost",
+// "auction_cancelled" or "bid_cancelled".
+type AuctionEmail struct {
+	Name         string     `json:"name"`
+	ItemID       uint       `json:"item_id"`
+	ItemName     string     `json:"item_name"`
+	LotCode      string     `json:"lot_code"`
+	Amount       string     `json:"amount,omitempty"` // formatted, e.g. Rp 150.000.000,00
+	AuctionStart *time.Time `json:"auction_start,omitempty"`
+	AuctionEnd   *time.Time `json:"auction_end,omitempty"`
+	Reason       string     `json:"reason,omitempty"`
+
+	// Saved-search alerts
+	SearchName       string            `json:"search_name,omitempty"`
+	Lots             []AuctionEmailLot `json:"lots,omitempty"` // digest only
+	UnsubscribeToken string            `json:"unsubscribe_token,omitempty"`
+}
+
+// AuctionEmailLot is one lot listed in a saved-search digest
+type AuctionEmailLot struct {
+	ItemID       uint       `json:"item_id"`
+	ItemName     string     `json:"item_name"`
+	LotCode      string     `json:"lot_code"`
+	AuctionStart *time.Time `json:"auction_start,omitempty"`
 }
 
 const (
 	EmailQueueName = "email_queue"
 	EmailExchange  = "email_exchange"
+
+	// AuctionEventsExchange fans live auction events out to every instance
+	AuctionEventsExchange = "auction_events"
 )
 
 func NewRabbitMQClient(cfg *config.Config) (*RabbitMQClient, error) {
@@ -92,6 +132,12 @@ func NewRabbitMQClient(cfg *config.Config) (*RabbitMQClient, error) {
 		return nil, fmt.Errorf("failed to bind queue: %w", err)
 	}
 
+	if err := declareAuctionEvents(channel); err != nil {
+		channel.Close()
+		conn.Close()
+		return nil, err
+	}
+
 	return &RabbitMQClient{
 		conn:    conn,
 		channel: channel,
@@ -199,6 +245,22 @@ func (r *RabbitMQClient) setupChannel(channel *amqp.Channel) error {
 		return fmt.Errorf("failed to bind queue: %w", err)
 	}
 
+	return declareAuctionEvents(channel)
+}
+
+// declareAuctionEvents declares the fanout exchange for live auction events
+func declareAuctionEvents(channel *amqp.Channel) error {
+	if err := channel.ExchangeDeclare(
+		AuctionEventsExchange, // name
+		"fanout",              // type
+		true,                  // durable
+		false,                 // auto-deleted
+		false,                 // internal
+		false,                 // no-wait
+		nil,                   // arguments
+	); err != nil {
+		return fmt.Errorf("failed to declare auction events exchange: %w", err)
+	}
 	return nil
 }
 
@@ -234,6 +296,80 @@ func (r *RabbitMQClient) PublishEmail(message EmailMessage) error {
 	return nil
 }
 
+// PublishAuctionEvent broadcasts a live auction event to every instance. The
+// events are transient, so they are not persisted.
+func (r *RabbitMQClient) PublishAuctionEvent(body []byte) error {
+	if err := r.ensureConnection(); err != nil {
+		return fmt.Errorf("connection error: %w", err)
+	}
+
+	err := r.channel.Publish(
+		AuctionEventsExchange, // exchange
+		"",                    // routing key
+		false,                 // mandatory
+		false,                 // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish auction event: %w", err)
+	}
+	return nil
+}
+
+// ConsumeAuctionEvents subscribes this instance to live auction events on its
+// own exclusive queue, which is removed when the channel closes. The returned
+// channel closes when the connection is lost.
+func (r *RabbitMQClient) ConsumeAuctionEvents() (<-chan amqp.Delivery, error) {
+	if err := r.ensureConnection(); err != nil {
+		return nil, fmt.Errorf("connection error: %w", err)
+	}
+
+	channel, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := declareAuctionEvents(channel); err != nil {
+		channel.Close()
+		return nil, err
+	}
+
+	queue, err := channel.QueueDeclare(
+		"",    // name, generated by the broker
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	if err := channel.QueueBind(queue.Name, "", AuctionEventsExchange, false, nil); err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("failed to bind queue: %w", err)
+	}
+
+	deliveries, err := channel.Consume(
+		queue.Name, // queue
+		"",         // consumer
+		true,       // auto-ack
+		true,       // exclusive
+		false,      // no-local
+		false,      // no-wait
+		nil,        // args
+	)
+	if err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("failed to register consumer: %w", err)
+	}
+	return deliveries, nil
+}
+
 // Close closes the RabbitMQ connection
 func (r *RabbitMQClient) Close() error {
 	if r.channel != nil {
@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"log"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 512
+	sendBufferSize = 64
+)
+
+// Client is one WebSocket subscription to an auction item. UserID is empty
+// for anonymous viewers.
+type Client struct {
+	hub    *Hub
+	conn   *gws.Conn
+	send   chan []byte
+	itemID uint
+	userID string
+}
+
+func newClient(hub *Hub, conn *gws.Conn, itemID uint, userID string) *Client {
+	return &Client{
+		hub:    hub,
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+		itemID: itemID,
+		userID: userID,
+	}
+}
+
+// readPump only watches the connection: the feed is one-way, so anything the
+// client sends is discarded. It unregisters the client once the peer is gone.
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			if gws.IsUnexpectedCloseError(err, gws.CloseGoingAway, gws.CloseNormalClosure) {
+				log.Printf("WebSocket closed for item %d: %v", c.itemID, err)
+			}
+			return
+		}
+	}
+}
+
+// writePump sends queued events and keeps the connection alive with pings.
+// It closes the connection once the hub closes the send channel.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(gws.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(gws.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(gws.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
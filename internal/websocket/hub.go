@@ -0,0 +1,363 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"yourapp/internal/service"
+	"yourapp/internal/util"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// presenceEvent carries one instance's viewer count of an item between
+	// instances; it is never sent to clients
+	presenceEvent = "presence"
+
+	presenceInterval = 30 * time.Second
+	presenceTTL      = 3 * presenceInterval
+	resubscribeDelay = 5 * time.Second
+)
+
+// message is an auction event as relayed between instances. ID is set by the
+// instance the event started on and is what SSE clients resume from. A
+// message with a RecipientID only goes to that user's notification streams.
+type message struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	ItemID      uint            `json:"item_id"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	ActorID     string          `json:"actor_id,omitempty"`
+	RecipientID string          `json:"recipient_id,omitempty"`
+	Instance    string          `json:"instance"`
+	At          time.Time       `json:"at"`
+}
+
+// outbound is an auction event as sent to a client. Mine is set on the
+// client's own bids; who else bid is never revealed.
+type outbound struct {
+	ID     int64           `json:"id,omitempty"`
+	Type   string          `json:"type"`
+	ItemID uint            `json:"item_id"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Mine   bool            `json:"mine,omitempty"`
+	At     time.Time       `json:"at"`
+}
+
+type presence struct {
+	count int
+	seen  time.Time
+}
+
+// Hub keeps the live subscribers of each auction item on this instance. Events
+// go out through the RabbitMQ fanout exchange and come back to every instance,
+// so subscribers see bids placed anywhere. Without RabbitMQ the hub only serves
+// its own instance.
+type Hub struct {
+	instanceID string
+
+	mu       sync.RWMutex
+	rooms    map[uint]map[*Client]bool
+	presence map[uint]map[string]presence // item -> instance -> viewers
+
+	rabbitMu sync.RWMutex
+	rabbitMQ *util.RabbitMQClient
+
+	lastID int64
+	log    *eventLog
+}
+
+func NewHub(rabbitMQ *util.RabbitMQClient) *Hub {
+	h := &Hub{
+		instanceID: uuid.New().String(),
+		rooms:      make(map[uint]map[*Client]bool),
+		presence:   make(map[uint]map[string]presence),
+		log:        newEventLog(),
+	}
+	go h.heartbeat()
+	if rabbitMQ != nil {
+		h.Attach(rabbitMQ)
+	}
+	return h
+}
+
+// Attach starts relaying events through RabbitMQ, e.g. once a connection that
+// failed at startup comes up
+func (h *Hub) Attach(rabbitMQ *util.RabbitMQClient) {
+	h.rabbitMu.Lock()
+	h.rabbitMQ = rabbitMQ
+	h.rabbitMu.Unlock()
+	go h.consume(rabbitMQ)
+}
+
+// PublishAuctionEvent implements service.AuctionEventPublisher
+func (h *Hub) PublishAuctionEvent(event service.AuctionEvent) {
+	msg := message{
+		Type:        event.Type,
+		ItemID:      event.ItemID,
+		ActorID:     event.ActorID,
+		RecipientID: event.RecipientID,
+		At:          event.At,
+	}
+	if event.Data != nil {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			log.Printf("Failed to marshal auction event %s: %v", event.Type, err)
+			return
+		}
+		msg.Data = data
+	}
+	h.publish(msg)
+}
+
+// publish sends msg to every instance, this one included
+func (h *Hub) publish(msg message) {
+	msg.Instance = h.instanceID
+	msg.ID = h.nextID()
+	if msg.At.IsZero() {
+		msg.At = time.Now()
+	}
+
+	h.rabbitMu.RLock()
+	rabbitMQ := h.rabbitMQ
+	h.rabbitMu.RUnlock()
+
+	if rabbitMQ != nil {
+		body, err := json.Marshal(msg)
+		if err == nil {
+			if err = rabbitMQ.PublishAuctionEvent(body); err == nil {
+				return
+			}
+		}
+		log.Printf("Failed to relay auction event %s, delivering locally: %v", msg.Type, err)
+	}
+	h.deliver(msg)
+}
+
+// nextID returns an event ID that grows on this instance and follows the
+// clock, so IDs from different instances rarely collide. Microseconds keep it
+// within what JavaScript numbers hold exactly.
+func (h *Hub) nextID() int64 {
+	for {
+		last := atomic.LoadInt64(&h.lastID)
+		id := time.Now().UnixMicro()
+		if id <= last {
+			id = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&h.lastID, last, id) {
+			return id
+		}
+	}
+}
+
+// consume feeds events from RabbitMQ to local subscribers, subscribing again
+// whenever the connection drops
+func (h *Hub) consume(rabbitMQ *util.RabbitMQClient) {
+	for {
+		deliveries, err := rabbitMQ.ConsumeAuctionEvents()
+		if err != nil {
+			log.Printf("Failed to subscribe to auction events: %v", err)
+			time.Sleep(resubscribeDelay)
+			continue
+		}
+
+		for d := range deliveries {
+			var msg message
+			if err := json.Unmarshal(d.Body, &msg); err != nil {
+				log.Printf("Invalid auction event: %v", err)
+				continue
+			}
+			h.deliver(msg)
+		}
+
+		log.Println("Auction events subscription closed, resubscribing...")
+		time.Sleep(resubscribeDelay)
+	}
+}
+
+// deliver hands an event to the local subscribers of its item
+func (h *Hub) deliver(msg message) {
+	if msg.Type == presenceEvent {
+		var data service.ViewersEvent
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return
+		}
+		h.setPresence(msg.ItemID, msg.Instance, data.Count)
+		return
+	}
+	// Viewer counts are only current, so SSE streams neither get nor replay them
+	if msg.Type != service.AuctionEventViewers {
+		h.log.append(msg)
+	}
+	// Private events never reach the public item rooms
+	if msg.RecipientID != "" {
+		return
+	}
+
+	out := outbound{ID: msg.ID, Type: msg.Type, ItemID: msg.ItemID, Data: msg.Data, At: msg.At}
+	theirs, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+	out.Mine = true
+	mine, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+
+	var slow []*Client
+	h.mu.RLock()
+	for client := range h.rooms[msg.ItemID] {
+		payload := theirs
+		if msg.ActorID != "" && client.userID == msg.ActorID {
+			payload = mine
+		}
+		select {
+		case client.send <- payload:
+		default:
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	// A client that can't keep up is dropped rather than holding up the room
+	for _, client := range slow {
+		h.unregister(client)
+	}
+}
+
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	room := h.rooms[client.itemID]
+	if room == nil {
+		room = make(map[*Client]bool)
+		h.rooms[client.itemID] = room
+	}
+	room[client] = true
+	count := len(room)
+	h.mu.Unlock()
+
+	h.announcePresence(client.itemID, count)
+}
+
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	room := h.rooms[client.itemID]
+	if !room[client] {
+		h.mu.Unlock()
+		return
+	}
+	delete(room, client)
+	close(client.send)
+	count := len(room)
+	if count == 0 {
+		delete(h.rooms, client.itemID)
+	}
+	h.mu.Unlock()
+
+	h.announcePresence(client.itemID, count)
+}
+
+// announcePresence records this instance's viewer count of an item and tells
+// the other instances about it
+func (h *Hub) announcePresence(itemID uint, count int) {
+	h.setPresence(itemID, h.instanceID, count)
+
+	data, _ := json.Marshal(service.ViewersEvent{Count: count})
+	h.publish(message{Type: presenceEvent, ItemID: itemID, Data: data})
+}
+
+// setPresence updates one instance's viewer count and pushes the new total to
+// local subscribers when it changed
+func (h *Hub) setPresence(itemID uint, instance string, count int) {
+	h.mu.Lock()
+	before := h.viewersLocked(itemID)
+	instances := h.presence[itemID]
+	if instances == nil {
+		instances = make(map[string]presence)
+		h.presence[itemID] = instances
+	}
+	if count > 0 {
+		instances[instance] = presence{count: count, seen: time.Now()}
+	} else {
+		delete(instances, instance)
+	}
+	if len(instances) == 0 {
+		delete(h.presence, itemID)
+	}
+	after := h.viewersLocked(itemID)
+	h.mu.Unlock()
+
+	if after != before {
+		h.pushViewers(itemID, after)
+	}
+}
+
+func (h *Hub) viewersLocked(itemID uint) int {
+	total := 0
+	for _, p := range h.presence[itemID] {
+		total += p.count
+	}
+	return total
+}
+
+func (h *Hub) pushViewers(itemID uint, count int) {
+	data, _ := json.Marshal(service.ViewersEvent{Count: count})
+	h.deliver(message{
+		Type:   service.AuctionEventViewers,
+		ItemID: itemID,
+		Data:   data,
+		At:     time.Now(),
+	})
+}
+
+// heartbeat re-announces local viewer counts so other instances keep them,
+// and forgets counts of instances that stopped announcing
+func (h *Hub) heartbeat() {
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.mu.RLock()
+		counts := make(map[uint]int, len(h.rooms))
+		for itemID, room := range h.rooms {
+			counts[itemID] = len(room)
+		}
+		h.mu.RUnlock()
+
+		for itemID, count := range counts {
+			h.announcePresence(itemID, count)
+		}
+
+		h.prunePresence(time.Now().Add(-presenceTTL))
+	}
+}
+
+func (h *Hub) prunePresence(cutoff time.Time) {
+	changed := make(map[uint]int)
+
+	h.mu.Lock()
+	for itemID, instances := range h.presence {
+		before := h.viewersLocked(itemID)
+		for instance, p := range instances {
+			if instance != h.instanceID && p.seen.Before(cutoff) {
+				delete(instances, instance)
+			}
+		}
+		if len(instances) == 0 {
+			delete(h.presence, itemID)
+		}
+		if after := h.viewersLocked(itemID); after != before {
+			changed[itemID] = after
+		}
+	}
+	h.mu.Unlock()
+
+	for itemID, count := range changed {
+		h.pushViewers(itemID, count)
+	}
+}
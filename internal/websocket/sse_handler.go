@@ -0,0 +1,168 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yourapp/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// countdownEvent and resetEvent are sent on SSE streams only
+	countdownEvent = "countdown"
+	resetEvent     = "reset"
+
+	lotCountdownInterval     = time.Second
+	listingCountdownInterval = 5 * time.Second
+	keepAliveInterval        = 15 * time.Second
+	retryMillis              = 3000
+)
+
+// StreamItem streams the bid, status and countdown events of one auction item
+// as Server-Sent Events
+func (h *Handler) StreamItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+	if !h.auctionService.AuctionItemExists(uint(id)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		return
+	}
+	h.stream(c, uint(id), lotCountdownInterval)
+}
+
+// StreamListing streams the events of every lot on the public listing, with
+// the countdowns of all published and running lots
+func (h *Handler) StreamListing(c *gin.Context) {
+	h.stream(c, 0, listingCountdownInterval)
+}
+
+// StreamNotifications streams the signed-in user's new in-app notifications
+// as they are raised. It resumes from Last-Event-ID like the auction streams.
+func (h *Handler) StreamNotifications(c *gin.Context) {
+	userID, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+	if userID == "" {
+		util.Unauthorized(c, "Authorization required")
+		return
+	}
+	h.serve(c, newStream(0, userID, true), 0)
+}
+
+func (h *Handler) stream(c *gin.Context, itemID uint, countdownInterval time.Duration) {
+	userID, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+	h.serve(c, newStream(itemID, userID, false), countdownInterval)
+}
+
+// serve writes the events of s until the client goes away. A client that
+// reconnects with Last-Event-ID first gets the events it missed; if they are
+// no longer kept it gets a reset event and should reload the page data.
+// Countdowns carry no ID since only the latest one matters; inbox streams,
+// with no countdownInterval, get none.
+func (h *Handler) serve(c *gin.Context, s *stream, countdownInterval time.Duration) {
+	missed, complete := h.hub.log.subscribe(s, lastEventID(c))
+	defer h.hub.log.unsubscribe(s)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", retryMillis)
+	if !complete {
+		writeSSE(c, 0, resetEvent, gin.H{})
+	}
+	for _, msg := range missed {
+		h.writeMessage(c, s, msg)
+	}
+
+	var countdown <-chan time.Time
+	if countdownInterval > 0 {
+		h.writeCountdown(c, s.itemID)
+		ticker := time.NewTicker(countdownInterval)
+		defer ticker.Stop()
+		countdown = ticker.C
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case msg, ok := <-s.events:
+			if !ok {
+				return
+			}
+			h.writeMessage(c, s, msg)
+		case <-countdown:
+			h.writeCountdown(c, s.itemID)
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (h *Handler) writeMessage(c *gin.Context, s *stream, msg message) {
+	writeSSE(c, msg.ID, msg.Type, outbound{
+		ID:     msg.ID,
+		Type:   msg.Type,
+		ItemID: msg.ItemID,
+		Data:   msg.Data,
+		Mine:   msg.ActorID != "" && msg.ActorID == s.userID,
+		At:     msg.At,
+	})
+}
+
+func (h *Handler) writeCountdown(c *gin.Context, itemID uint) {
+	now := time.Now()
+	if itemID == 0 {
+		writeSSE(c, 0, countdownEvent, gin.H{"lots": h.countdowns.all(now), "server_time": now})
+		return
+	}
+	if countdown, ok := h.countdowns.lot(itemID, now); ok {
+		writeSSE(c, 0, countdownEvent, gin.H{"lot": countdown, "server_time": now})
+	}
+}
+
+func writeSSE(c *gin.Context, id int64, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != 0 {
+		fmt.Fprintf(c.Writer, "id: %d\n", id)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// lastEventID reads the ID an EventSource resumes from. The query parameter
+// is for proxies and polyfills that drop the header.
+func lastEventID(c *gin.Context) int64 {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
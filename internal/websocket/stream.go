@@ -0,0 +1,250 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"yourapp/internal/model"
+	"yourapp/internal/service"
+)
+
+const (
+	replaySize        = 1000
+	streamBufferSize  = 64
+	countdownRefresh  = 30 * time.Second
+	countdownRetryGap = 5 * time.Second
+)
+
+// stream is one SSE subscription, to a single item or, with itemID 0, to
+// every item on the listing. An inbox stream gets its user's private events
+// and nothing else.
+type stream struct {
+	itemID uint
+	userID string
+	inbox  bool
+	events chan message
+}
+
+func newStream(itemID uint, userID string, inbox bool) *stream {
+	return &stream{
+		itemID: itemID,
+		userID: userID,
+		inbox:  inbox,
+		events: make(chan message, streamBufferSize),
+	}
+}
+
+func (s *stream) wants(msg message) bool {
+	if s.inbox || msg.RecipientID != "" {
+		return s.inbox && s.userID != "" && msg.RecipientID == s.userID
+	}
+	return s.itemID == 0 || s.itemID == msg.ItemID
+}
+
+// eventLog keeps the latest events for SSE clients to resume from and hands
+// new ones to the open streams
+type eventLog struct {
+	mu      sync.Mutex
+	events  []message // oldest first
+	streams map[*stream]bool
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{streams: make(map[*stream]bool)}
+}
+
+func (l *eventLog) append(msg message) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, msg)
+	// Trim in batches rather than copying on every event
+	if len(l.events) >= 2*replaySize {
+		l.events = append([]message(nil), l.events[len(l.events)-replaySize:]...)
+	}
+
+	for s := range l.streams {
+		if !s.wants(msg) {
+			continue
+		}
+		select {
+		case s.events <- msg:
+		default:
+			// Too slow to keep up; the client reconnects and resumes
+			delete(l.streams, s)
+			close(s.events)
+		}
+	}
+}
+
+// subscribe opens s and returns the events it missed after lastID. Resuming
+// and subscribing happen under one lock so no event falls between them.
+// complete is false when lastID has already left the log and the client has
+// to reload what it shows.
+func (l *eventLog) subscribe(s *stream, lastID int64) (missed []message, complete bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.streams[s] = true
+
+	if lastID == 0 {
+		return nil, true
+	}
+
+	// Look the ID up rather than compare it: IDs from different instances
+	// are only roughly ordered
+	start := -1
+	for i := len(l.events) - 1; i >= 0; i-- {
+		if l.events[i].ID == lastID {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	for _, msg := range l.events[start:] {
+		if s.wants(msg) {
+			missed = append(missed, msg)
+		}
+	}
+	return missed, true
+}
+
+func (l *eventLog) unsubscribe(s *stream) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.streams[s] {
+		delete(l.streams, s)
+		close(s.events)
+	}
+}
+
+// countdownBoard keeps the schedule of every published and running lot so
+// SSE streams can send countdowns without querying the database each tick.
+// It reloads periodically and follows extensions and status changes as they
+// happen.
+type countdownBoard struct {
+	auctionService service.AuctionService
+
+	mu   sync.RWMutex
+	lots map[uint]service.Countdown
+}
+
+func newCountdownBoard(hub *Hub, auctionService service.AuctionService) *countdownBoard {
+	b := &countdownBoard{
+		auctionService: auctionService,
+		lots:           make(map[uint]service.Countdown),
+	}
+	go b.run(hub)
+	return b
+}
+
+func (b *countdownBoard) run(hub *Hub) {
+	ticker := time.NewTicker(countdownRefresh)
+	defer ticker.Stop()
+
+	for {
+		s := newStream(0, "", false)
+		hub.log.subscribe(s, 0)
+		b.load()
+
+		for open := true; open; {
+			select {
+			case msg, ok := <-s.events:
+				if !ok {
+					open = false
+					break
+				}
+				b.apply(msg)
+			case <-ticker.C:
+				b.load()
+			}
+		}
+		time.Sleep(countdownRetryGap)
+	}
+}
+
+func (b *countdownBoard) load() {
+	countdowns, err := b.auctionService.GetCountdowns(time.Now())
+	if err != nil {
+		log.Printf("Failed to load auction countdowns: %v", err)
+		return
+	}
+
+	lots := make(map[uint]service.Countdown, len(countdowns))
+	for _, countdown := range countdowns {
+		lots[countdown.ItemID] = countdown
+	}
+
+	b.mu.Lock()
+	b.lots = lots
+	b.mu.Unlock()
+}
+
+func (b *countdownBoard) apply(msg message) {
+	switch msg.Type {
+	case service.AuctionEventExtended:
+		var data service.AuctionExtendedEvent
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return
+		}
+		b.mu.Lock()
+		if countdown, ok := b.lots[msg.ItemID]; ok {
+			countdown.AuctionEnd = data.NewEnd
+			b.lots[msg.ItemID] = countdown
+		}
+		b.mu.Unlock()
+
+	case service.AuctionEventStatusChanged:
+		var data service.StatusChangedEvent
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return
+		}
+		b.mu.Lock()
+		countdown, known := b.lots[msg.ItemID]
+		switch {
+		case data.Status == model.AuctionStatusClosed || data.Status == model.AuctionStatusCancelled:
+			delete(b.lots, msg.ItemID)
+		case known:
+			countdown.Status = data.Status
+			b.lots[msg.ItemID] = countdown
+		}
+		b.mu.Unlock()
+
+		// A newly published lot brings a schedule the event doesn't carry
+		if !known && (data.Status == model.AuctionStatusPublished || data.Status == model.AuctionStatusOngoing) {
+			b.load()
+		}
+	}
+}
+
+// lot returns the countdown of one item as of now
+func (b *countdownBoard) lot(itemID uint, now time.Time) (service.Countdown, bool) {
+	b.mu.RLock()
+	countdown, ok := b.lots[itemID]
+	b.mu.RUnlock()
+
+	countdown.Tick(now)
+	return countdown, ok
+}
+
+// all returns the countdowns of every live lot as of now, soonest ending first
+func (b *countdownBoard) all(now time.Time) []service.Countdown {
+	b.mu.RLock()
+	countdowns := make([]service.Countdown, 0, len(b.lots))
+	for _, countdown := range b.lots {
+		countdown.Tick(now)
+		countdowns = append(countdowns, countdown)
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(countdowns, func(i, j int) bool {
+		return countdowns[i].AuctionEnd.Before(countdowns[j].AuctionEnd)
+	})
+	return countdowns
+}
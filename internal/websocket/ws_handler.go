@@ -0,0 +1,104 @@
+package websocket
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"yourapp/internal/service"
+	"yourapp/internal/util"
+
+	"github.com/gin-gonic/gin"
+	gws "github.com/gorilla/websocket"
+)
+
+// Handler serves the live auction feed, over WebSocket or, for clients whose
+// proxies break WebSockets, as Server-Sent Events
+type Handler struct {
+	hub            *Hub
+	auctionService service.AuctionService
+	jwtSecret      string
+	upgrader       gws.Upgrader
+	countdowns     *countdownBoard
+}
+
+func NewHandler(hub *Hub, auctionService service.AuctionService, jwtSecret, clientURL string) *Handler {
+	return &Handler{
+		hub:            hub,
+		auctionService: auctionService,
+		jwtSecret:      jwtSecret,
+		upgrader: gws.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || origin == clientURL
+			},
+		},
+		countdowns: newCountdownBoard(hub, auctionService),
+	}
+}
+
+// Subscribe streams the live events of an auction item. Viewers may connect
+// anonymously; a signed-in viewer passes their access token as a Bearer
+// header or, since browsers can't set headers on WebSocket requests, as the
+// token query parameter, and then sees which bids were their own.
+func (h *Handler) Subscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	userID, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+
+	if !h.auctionService.AuctionItemExists(uint(id)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	// Registering raises the viewer count, which also greets the new client
+	// with the current total
+	client := newClient(h.hub, conn, uint(id), userID)
+	h.hub.register(client)
+
+	go client.writePump()
+	go client.readPump()
+}
+
+// viewer returns the signed-in user of a feed request, or "" for an anonymous
+// viewer. It answers 401 and returns false for a bad token.
+func (h *Handler) viewer(c *gin.Context) (string, bool) {
+	token := requestToken(c)
+	if token == "" {
+		return "", true
+	}
+	claims, err := util.ValidateToken(token, h.jwtSecret)
+	if err != nil {
+		util.Unauthorized(c, "Invalid or expired token")
+		return "", false
+	}
+	return claims.UserID, true
+}
+
+func requestToken(c *gin.Context) string {
+	if token := c.Query("token"); token != "" {
+		return token
+	}
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		return parts[1]
+	}
+	return ""
+}